@@ -0,0 +1,58 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "bytes"
+
+// Alignment is how a column's values are aligned in text output.
+type Alignment int
+
+const (
+	// AlignDefault aligns the text columns left and the numeric columns,
+	// Ops, NsOp, BytesOp, and AllocsOp, right.
+	AlignDefault Alignment = iota
+	AlignLeft
+	AlignRight
+)
+
+// SetAlignment sets how the column's values are aligned; e.g. left aligned
+// numbers can be easier to diff.  The column headers are always left
+// aligned.
+func (b *StringBench) SetAlignment(c Column, a Alignment) {
+	if b.align == nil {
+		b.align = make(map[Column]Alignment)
+	}
+	b.align[c] = a
+}
+
+// alignment returns the column's alignment.
+func (b *StringBench) alignment(c Column) Alignment {
+	if a := b.align[c]; a != AlignDefault {
+		return a
+	}
+	switch c {
+	case OpsColumn, NsOpColumn, BytesOpColumn, AllocsOpColumn:
+		return AlignRight
+	}
+	return AlignLeft
+}
+
+// writeColumn writes s, aligned, as a cell of the column, of width w,
+// followed by the column padding, to buf.  The Note column, being the last,
+// isn't padded.
+func (b *StringBench) writeColumn(buf *bytes.Buffer, c Column, w int, s string) {
+	if c == NoteColumn {
+		if b.alignment(c) == AlignRight && len(s) < w {
+			buf.WriteString(padding(w - len(s)))
+		}
+		buf.WriteString(s)
+		return
+	}
+	if b.alignment(c) == AlignRight {
+		b.writeColumnR(buf, w, s)
+		return
+	}
+	b.writeColumnL(buf, w, s)
+}
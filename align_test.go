@@ -0,0 +1,41 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSetAlignment(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewStringBench(&buf)
+	b.Hostname = "host"
+	b.SetAlignment(NsOpColumn, AlignLeft)
+	b.SetAlignment(NameColumn, AlignRight)
+	b.SetAlignment(NoteColumn, AlignRight)
+	b.Append(
+		Bench{Name: "a", Iterations: 1, Result: Result{Ops: 10, NsOp: 5}, Note: "x"},
+		Bench{Name: "bbbb", Iterations: 1, Result: Result{Ops: 200, NsOp: 12345}, Note: "long"},
+	)
+	err := b.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	want := []string{
+		"Name  Ops  ns/Op  B/Op  Allocs/Op  Note",
+		"---------------------------------------",
+		"   a   10  5         0          0     x",
+		"bbbb  200  12345     0          0  long",
+	}
+	got := lines[len(lines)-1-len(want):]
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d: got %q; want %q", i, got[i], want[i])
+		}
+	}
+}
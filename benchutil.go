@@ -7,44 +7,85 @@ package benchutil
 
 import (
 	"bytes"
-	crand "crypto/rand"
 	"encoding/csv"
 	"fmt"
 	"io"
-	"math/big"
 	"os"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
-
-	pcg "github.com/dgryski/go-pcgr"
-	human "github.com/dustin/go-humanize"
-	"github.com/mohae/csv2md"
-	"github.com/mohae/joefriday/cpu/cpuinfo"
-	"github.com/mohae/joefriday/mem/membasic"
-	"github.com/mohae/joefriday/system/version"
-	release "github.com/mohae/joefriday/system/os"
+	"unicode/utf8"
 )
 
 const defaultPadding = 2
 
-var prng pcg.Rand
-
-func init() {
-	prng.Seed(NewSeed())
-}
-
 // Benchmarker defines common behavior for a Benchmark output harness; format
-// specific methods may be
+// specific methods may be added by the implementations.  It's the union of
+// the focused interfaces below; code that only needs part of it, e.g. a func
+// that only appends results, should accept the smaller interface so custom
+// implementations and mocks don't have to implement the rest.
+//
+// Capabilities that were added later, e.g. warnings and metadata, a title,
+// or hiding columns, aren't part of Benchmarker, so implementations written
+// against it keep working; they are optional interfaces, e.g. Annotator and
+// DocumentConfigurer, that the Benchmarkers in this package implement.  Use
+// a type assertion to check for them:
+//
+//	if a, ok := b.(benchutil.Annotator); ok {
+//		a.SetMeta("dataset", "enwik9")
+//	}
 type Benchmarker interface {
+	Appender
+	Outputter
+	SystemInfoConfigurer
+	HeaderConfigurer
+	ColumnConfigurer
+	SectionConfigurer
+}
+
+// Appender is implemented by things that collect benchmark results.
+type Appender interface {
 	Append(...Bench)
+}
+
+// Outputter is implemented by things that write benchmark results.
+type Outputter interface {
 	Out() error
-	IncludeOpsColumnDesc(bool)
+}
+
+// Annotator is implemented by things that record information about the
+// conditions a set of benchmarks were run under; it's optional.
+type Annotator interface {
+	AddWarning(s string)
+	SetMeta(key, value string)
+}
+
+// SystemInfoConfigurer is implemented by things that can include the system
+// info with their output.
+type SystemInfoConfigurer interface {
 	IncludeSystemInfo(bool)
 	IncludeDetailedSystemInfo(bool)
 	SystemInfo() (string, error)
 	DetailedSystemInfo() (string, error)
+}
+
+// DeviceInfoConfigurer is implemented by things that can enumerate the
+// system's devices as part of the system info; it's optional.
+type DeviceInfoConfigurer interface {
+	IncludeGPUInfo(bool)
+	IncludeDiskInfo(bool)
+}
+
+// InfoProvider is implemented by things that provide the system info as
+// a SysInfo; it's optional.
+type InfoProvider interface {
+	Info() (*SysInfo, error)
+}
+
+// HeaderConfigurer is implemented by things whose column headers can be
+// set.
+type HeaderConfigurer interface {
 	SetGroupColumnHeader(s string)
 	SetSubGroupColumnHeader(s string)
 	SetNameColumnHeader(s string)
@@ -54,7 +95,39 @@ type Benchmarker interface {
 	SetBytesOpColumnHeader(s string)
 	SetAllocsOpColumnHeader(s string)
 	SetNoteColumnHeader(s string)
+}
+
+// ColumnConfigurer is implemented by things whose columns can be
+// configured.
+type ColumnConfigurer interface {
+	IncludeOpsColumnDesc(bool)
 	SetColumnPadding(i int)
+}
+
+// ColumnSelector is implemented by things whose optional columns can be
+// hidden, or forced; it's optional.
+type ColumnSelector interface {
+	HideColumns(cols ...string)
+	ForceColumn(col string, v bool)
+}
+
+// RowFormatterSetter is implemented by things whose cells can be formatted
+// by a RowFormatter; it's optional.
+type RowFormatterSetter interface {
+	SetRowFormatter(f RowFormatter)
+}
+
+// DocumentConfigurer is implemented by things whose output can have a
+// title, a caption, and a footer; it's optional.
+type DocumentConfigurer interface {
+	SetTitle(s string)
+	SetCaption(s string)
+	SetFooter(s string)
+}
+
+// SectionConfigurer is implemented by things that can split their output
+// into sections.
+type SectionConfigurer interface {
 	SectionPerGroup(bool)
 	SectionHeaders(bool)
 	NameSections(bool)
@@ -141,122 +214,115 @@ func (h *header) SetNoteColumnHeader(s string) {
 
 // Benches is a collection of benchmark informtion and their results.
 type Benches struct {
-	Name       string  // Name of the set; optional.
-	Desc       string  // Description of the collection of benchmarks; optional.
-	Note       string  // Additional notes about the set; optional.
-	Benchmarks []Bench // The benchmark results
+	Name       string            // Name of the set; optional.
+	Desc       string            // Description of the collection of benchmarks; optional.
+	Note       string            // Additional notes about the set; optional.
+	Benchmarks []Bench           // The benchmark results
+	Warnings   []string          // Warnings about the conditions the benchmarks were run under.
+	SysInfo    *SysInfo          // Information about the system; set when the system info is first used.
+	Hostname   string            // The name of the host the benchmarks were run on; set on the first Append.
+	Timestamp  time.Time         // When the benchmarks were run; set on the first Append.
+	Git        *GitInfo          // The version of the code that was benchmarked; optional, see CaptureGitInfo.
+	Env        map[string]string // The environment variables the benchmarks were run with; optional, see CaptureEnv.
+	Meta       [][2]string       // Additional key value pairs about the run, in the order they were set; see SetMeta.
 	header
-	columnPadding             int  // The number of spaces between columns.
-	includeOpsColumnDesc      bool // Include the description of the ops info in each column's result output.
-	includeSystemInfo         bool // Add basic system info to the output
-	includeDetailedSystemInfo bool // SystemInfo output uses DetailedSystemInfo.
-	sectionPerGroup           bool // make a section for each group
-	sectionHeaders            bool // if each section should have it's own col headers, when applicable
-	nameSections              bool // Use the group name as the section name when there are sections.
+	columnPadding             int             // The number of spaces between columns.
+	includeOpsColumnDesc      bool            // Include the description of the ops info in each column's result output.
+	includeSystemInfo         bool            // Add basic system info to the output
+	includeDetailedSystemInfo bool            // SystemInfo output uses DetailedSystemInfo.
+	includeGPUInfo            bool            // Enumerate the GPUs as part of the system info.
+	includeDiskInfo           bool            // Enumerate the block devices as part of the system info.
+	sectionPerGroup           bool            // make a section for each group
+	sectionHeaders            bool            // if each section should have it's own col headers, when applicable
+	nameSections              bool            // Use the group name as the section name when there are sections.
+	hidden                    map[string]bool // The optional columns that are left out of the output; see HideColumns.
+	shown                     map[string]bool // The optional columns that are output even if they're empty; see ForceColumn.
+	rowFormatter              RowFormatter    // Formats the cells; nil uses the default formatting.
+	title                     string          // The report's title; see SetTitle.
+	caption                   string          // The table's caption; see SetCaption.
+	footer                    string          // The report's footer; see SetFooter.
+	locale                    *Locale         // The locale numbers and dates are formatted with; nil is the default formatting.
+	localeTag                 string          // The tag the locale was set with; see SetLocale.
+	precision                 map[Column]int  // The decimal places of the per op columns; see SetPrecision.
+	nsOpDuration              bool            // Output the NsOp column as durations; see NsOpAsDuration.
+	sortedAppend              bool            // Append inserts the benches in Group, SubGroup, Name order; see SortedAppend.
+	aggregation               Aggregation     // How the benches' samples are combined for output; see SetAggregation.
+	parallelism               int             // The number of goroutines the rows are formatted with; see SetParallelism.
+	csvPreamble               bool            // Write the records that aren't benchmarks in csv; see CSVPreamble.
+	optErr                    error           // The error of an option that couldn't be applied, e.g. WithLocale's; Out returns it.
 	length
 }
 
-// DetailedSystemInfo generates the System Information string, including
-// information about every CPU core on the system.
-func (b *Benches) DetailedSystemInfo() (string, error) {
-	inf, err := cpuinfo.Get()
-	if err != nil {
-		return "", err
+// NewBenches returns Benches with the default column headers and padding.
+// Use it for Benches that aren't part of a Benchmarker, e.g. when decoding
+// saved results, so they can be output later.
+func NewBenches(opts ...Option) *Benches {
+	b := &Benches{
+		header:        newHeader(),
+		columnPadding: defaultPadding,
 	}
-	v, err := version.Get()
-	if err != nil {
-		return "", err
+	b.apply(opts)
+	return b
+}
+
+// Append adds Benches to the slice of Benchmarks.  The first Append sets the
+// Hostname and Timestamp, if they aren't already set.  Append isn't safe
+// for concurrent use; see ConcurrentBenches.  If SortedAppend is set, the
+// benches are inserted in order.
+func (b *Benches) Append(benches ...Bench) {
+	b.add(0, benches)
+}
+
+// setRunInfo sets the Hostname and Timestamp; if they aren't set.
+func (b *Benches) setRunInfo() {
+	if b.Timestamp.IsZero() {
+		b.Timestamp = time.Now()
 	}
-	r, err := release.Get()
-	if err != nil {
-		return "", err
+	if b.Hostname == "" {
+		b.Hostname, _ = os.Hostname()
 	}
-	m, err := membasic.Get()
-	if err != nil {
-		return "", err
-	}
-	var buff bytes.Buffer
-	for _, cpu := range inf.CPU {
-		buff.WriteString(fmt.Sprintf("Processor:  %d\n", cpu.Processor))
-		buff.WriteString("Model:      ")
-		buff.WriteString(cpu.ModelName)
-		buff.WriteRune('\n')
-		buff.WriteString(fmt.Sprintf("CPU MHz:    %7.2f\n", cpu.CPUMHz))
-		buff.WriteString("Cache:      ")
-		buff.WriteString(cpu.CacheSize)
-		buff.WriteRune('\n')
-	}
-	buff.WriteString("Memory:     ")
-	buff.WriteString(human.Bytes(m.MemTotal * 1000))
-	buff.WriteRune('\n')
-	// release info
-	info := r.PrettyName
-	if info == "" {
-		info = r.Version
-		if info == "" {
-			info = r.VersionID
-		}
-	}
-	buff.WriteString(fmt.Sprintf("OS:         %s %s\n", strings.Title(r.ID), info))
-	// OS kernel info
-	if v.Version != "" {
-		buff.WriteString(fmt.Sprintf("Kernel:     %s\n", v.Version))
-		buff.WriteRune('\n')
-	}
-	return buff.String(), nil
-}
-
-// SystemInfo generates a System Information string.
-func (b *Benches) SystemInfo() (string, error) {
-	inf, err := cpuinfo.Get()
-	if err != nil {
-		return "", err
+}
+
+// runInfo returns the key value pairs for the information about the run;
+// values that aren't set are skipped.
+func (b *Benches) runInfo() [][2]string {
+	var info [][2]string
+	if b.Hostname != "" {
+		info = append(info, [2]string{"Host", b.Hostname})
+	}
+	if !b.Timestamp.IsZero() {
+		layout := time.RFC3339
+		if b.locale != nil {
+			layout = b.locale.DateLayout
+		}
+		info = append(info, [2]string{"Timestamp", b.Timestamp.Format(layout)})
 	}
-	v, err := version.Get()
-	if err != nil {
-		return "", err
+	if b.Git != nil {
+		info = append(info, [2]string{"Commit", b.Git.String()})
 	}
-	r, err := release.Get()
-	if err != nil {
-		return "", err
+	if len(b.Env) > 0 {
+		info = append(info, [2]string{"Env", b.envString()})
 	}
-	m, err := membasic.Get()
-	if err != nil {
-		return "", err
-	}
-	var buff bytes.Buffer
-
-	buff.WriteString(fmt.Sprintf("Processors:  %d\n", len(inf.CPU)))
-	buff.WriteString("Model:       ")
-	buff.WriteString(inf.CPU[0].ModelName)
-	buff.WriteRune('\n')
-	buff.WriteString(fmt.Sprintf("CPU MHz:     %7.2f\n", inf.CPU[0].CPUMHz))
-	buff.WriteString("Cache:       ")
-	buff.WriteString(inf.CPU[0].CacheSize)
-	buff.WriteRune('\n')
-	buff.WriteString("Memory:      ")
-	buff.WriteString(human.Bytes(m.MemTotal * 1000))
-	buff.WriteRune('\n')
-	// release info
-	info := r.PrettyName
-	if info == "" {
-		info = r.Version
-		if info == "" {
-			info = r.VersionID
-		}
-	}
-	buff.WriteString(fmt.Sprintf("OS:          %s %s\n", strings.Title(r.ID), info))
-	// os kernel info
-	if v.Version != "" {
-		buff.WriteString(fmt.Sprintf("Kernel:      %s\n", v.Version))
-		buff.WriteRune('\n')
-	}
-	return buff.String(), nil
-}
-
-// Add adds a Bench to the slice of Benchmarks
-func (b *Benches) Append(benches ...Bench) {
-	b.Benchmarks = append(b.Benchmarks, benches...)
+	return append(info, b.Meta...)
+}
+
+// SetMeta sets a key value pair, e.g. "dataset", "enwik9", that is included
+// with the information about the run in the output.  If the key has already
+// been set, its value is replaced.
+func (b *Benches) SetMeta(key, value string) {
+	for i := range b.Meta {
+		if b.Meta[i][0] == key {
+			b.Meta[i][1] = value
+			return
+		}
+	}
+	b.Meta = append(b.Meta, [2]string{key, value})
+}
+
+// AddWarning adds a warning about the conditions the benchmarks were run
+// under, e.g. the CPU not being in performance mode, to the output.
+func (b *Benches) AddWarning(s string) {
+	b.Warnings = append(b.Warnings, s)
 }
 
 // IncludeOpsColumnDesc: if true, the ops information will be included in each
@@ -281,6 +347,31 @@ func (b *Benches) IncludeDetailedSystemInfo(v bool) {
 	b.includeDetailedSystemInfo = v
 }
 
+// IncludeGPUInfo: if true, the system's GPUs are enumerated as part of the
+// system info and included in the detailed system info output.  This must be
+// set before the system info is first used.
+func (b *Benches) IncludeGPUInfo(v bool) {
+	b.includeGPUInfo = v
+}
+
+// IncludeDiskInfo: if true, the system's block devices, and the filesystem
+// the working directory is on, are included in the system info and the
+// detailed system info output.  This must be set before the system info is
+// first used.
+func (b *Benches) IncludeDiskInfo(v bool) {
+	b.includeDiskInfo = v
+}
+
+// CSVPreamble: if true, the CSV output includes the records that aren't
+// benchmarks: the title, the set's name and description, the run info, the
+// metadata, and the system info are written before the header; the caption
+// just before it; and the Note and footer after the records.  These records
+// don't have the header's number of fields, so the output can't be read by a
+// csv.Reader with the default settings.  Other formats ignore this.
+func (b *Benches) CSVPreamble(v bool) {
+	b.csvPreamble = v
+}
+
 // Sets the sectionPerGroup bool
 func (b *Benches) SectionPerGroup(v bool) {
 	b.sectionPerGroup = v
@@ -301,6 +392,36 @@ func (b *Benches) SetColumnPadding(i int) {
 	b.columnPadding = i
 }
 
+// HideColumns leaves the columns out of the output even if the benches have
+// values for them.  Only the optional columns can be hidden: group,
+// subgroup, name, desc, and note; other names are ignored.
+func (b *Benches) HideColumns(cols ...string) {
+	if b.hidden == nil {
+		b.hidden = make(map[string]bool, len(cols))
+	}
+	for _, c := range cols {
+		b.hidden[c] = true
+		delete(b.shown, c)
+	}
+}
+
+// ForceColumn overrides whether or not the column is output: if v is true,
+// the column is output even if none of the benches have a value for it,
+// e.g. so the columns are the same across runs; if v is false, the column
+// is hidden, see HideColumns.  Only the optional columns can be forced:
+// group, subgroup, name, desc, and note; other names are ignored.
+func (b *Benches) ForceColumn(col string, v bool) {
+	if !v {
+		b.HideColumns(col)
+		return
+	}
+	if b.shown == nil {
+		b.shown = make(map[string]bool)
+	}
+	b.shown[col] = true
+	delete(b.hidden, col)
+}
+
 func (b *Benches) setLength() {
 	// Sets the max length of each Bench value.
 	var maxIters int64
@@ -318,8 +439,8 @@ func (b *Benches) setLength() {
 		if len(v.Desc) > b.length.Desc {
 			b.length.Desc = len(v.Desc)
 		}
-		if len(v.Note) > b.length.Note {
-			b.length.Note = len(v.Note)
+		if len(v.NoteString()) > b.length.Note {
+			b.length.Note = len(v.NoteString())
 		}
 		// result
 		if len(strconv.Itoa(int(v.Result.Ops)*v.Iterations)) > b.length.Ops {
@@ -346,6 +467,21 @@ func (b *Benches) setLength() {
 		b.length.BytesOp += 9
 		b.length.AllocsOp += 10
 	}
+	// forced columns are output even if they're empty.
+	for _, v := range []struct {
+		col    string
+		length *int
+	}{
+		{"group", &b.length.Group},
+		{"subgroup", &b.length.SubGroup},
+		{"name", &b.length.Name},
+		{"desc", &b.length.Desc},
+		{"note", &b.length.Note},
+	} {
+		if b.shown[v.col] && *v.length == 0 {
+			*v.length = 1
+		}
+	}
 	// see if the header column values are > than the contents they hold
 	if b.length.Group > 0 && len(b.header.Group) > b.length.Group {
 		b.length.Group = len(b.header.Group)
@@ -374,105 +510,150 @@ func (b *Benches) setLength() {
 	if len(b.header.AllocsOp) > b.length.AllocsOp {
 		b.length.AllocsOp = len(b.header.AllocsOp)
 	}
+	b.setCellLength()
+	// a column without a length isn't output.
+	if b.hidden["group"] {
+		b.length.Group = 0
+	}
+	if b.hidden["subgroup"] {
+		b.length.SubGroup = 0
+	}
+	if b.hidden["name"] {
+		b.length.Name = 0
+	}
+	if b.hidden["desc"] {
+		b.length.Desc = 0
+	}
+	if b.hidden["note"] {
+		b.length.Note = 0
+	}
 }
 
 // OpsString returns the operations performed by the benchmark as a formatted
 // string.
 func (b *Benches) OpsString(v Bench) string {
+	v = b.aggregate(v)
 	if b.includeOpsColumnDesc {
-		return fmt.Sprintf("%d ops", v.Ops*int64(v.Iterations))
+		return b.localize(strconv.FormatInt(v.Ops*int64(v.Iterations), 10)) + " ops"
 	}
-	return fmt.Sprintf("%d", v.Ops*int64(v.Iterations))
+	return b.localize(strconv.FormatInt(v.Ops*int64(v.Iterations), 10))
 }
 
 // NsOpString returns the nanoseconds each operation took as a formatted
 // string.
 func (b *Benches) NsOpString(v Bench) string {
+	v = b.aggregate(v)
+	if b.nsOpDuration {
+		if b.includeOpsColumnDesc {
+			return b.durationString(v.NsOp, v.Iterations) + "/op"
+		}
+		return b.durationString(v.NsOp, v.Iterations)
+	}
 	if b.includeOpsColumnDesc {
-		return fmt.Sprintf("%s ns/op", b.perOpsString(v.NsOp, v.Iterations))
+		return b.perOpsString(NsOpColumn, v.NsOp, v.Iterations) + " ns/op"
 	}
-	return b.perOpsString(v.NsOp, v.Iterations)
+	return b.perOpsString(NsOpColumn, v.NsOp, v.Iterations)
 }
 
 // BytesOpString returns the bytes allocated for each operation as a formatted
 // string.
 func (b *Benches) BytesOpString(v Bench) string {
+	v = b.aggregate(v)
 	if b.includeOpsColumnDesc {
-		return fmt.Sprintf("%s bytes/op", b.perOpsString(v.BytesOp, v.Iterations))
+		return b.perOpsString(BytesOpColumn, v.BytesOp, v.Iterations) + " bytes/op"
 	}
-	return b.perOpsString(v.BytesOp, v.Iterations)
+	return b.perOpsString(BytesOpColumn, v.BytesOp, v.Iterations)
 }
 
 // AllocsOpString returns the allocations per operation as a formatted string.
 func (b *Benches) AllocsOpString(v Bench) string {
+	v = b.aggregate(v)
 	if b.includeOpsColumnDesc {
-		return fmt.Sprintf("%s allocs/op", b.perOpsString(v.AllocsOp, v.Iterations))
+		return b.perOpsString(AllocsOpColumn, v.AllocsOp, v.Iterations) + " allocs/op"
 	}
-	return b.perOpsString(v.AllocsOp, v.Iterations)
+	return b.perOpsString(AllocsOpColumn, v.AllocsOp, v.Iterations)
 }
 
 // perOpsString takes a value and uses it to calculate the per operation value,
-// which is returned as a string.
-func (b *Benches) perOpsString(v int64, it int) string {
+// which is returned as a string.  Unless a precision is set for the column,
+// the value is truncated to an integer.
+func (b *Benches) perOpsString(c Column, v int64, it int) string {
+	if p, ok := b.precision[c]; ok {
+		return b.localize(strconv.FormatFloat(float64(v)/float64(it), 'f', p, 64))
+	}
 	if v == 0 {
 		return "0"
 	}
-	return fmt.Sprintf("%d", v/int64(it))
+	return b.localize(strconv.FormatInt(v/int64(it), 10))
 }
 
-// columnR returns a right justified string of width w.
-func (b *Benches) columnR(w int, s string) string {
-	pad := w - len(s)
-	if pad < 0 {
-		pad = 0
-	}
-	rpadding := make([]byte, pad)
-	for i := range rpadding {
-		rpadding[i] = 0x20
+// spaces is sliced for the padding of the columns, so padding doesn't need
+// to be allocated.
+const spaces = "                                                                "
+
+// padding returns n spaces; if n < 1, an empty string is returned.
+func padding(n int) string {
+	if n < 1 {
+		return ""
 	}
-	lpadding := make([]byte, b.columnPadding)
-	for i := range lpadding {
-		lpadding[i] = 0x20
+	if n <= len(spaces) {
+		return spaces[:n]
 	}
-	return fmt.Sprintf("%s%s%s", rpadding, s, lpadding)
+	return strings.Repeat(" ", n)
+}
+
+// columnR returns a right justified string of width w.
+func (b *Benches) columnR(w int, s string) string {
+	return padding(w-utf8.RuneCountInString(s)) + s + padding(b.columnPadding)
 }
 
 // columnL returns a left justified string of width w.
 func (b *Benches) columnL(w int, s string) string {
-	pad := w + b.columnPadding - len(s)
+	return s + padding(b.columnLPadding(w, s))
+}
+
+// columnLPadding returns the padding that follows s in a left justified
+// column of width w.
+func (b *Benches) columnLPadding(w int, s string) int {
+	pad := w + b.columnPadding - utf8.RuneCountInString(s)
 	if pad < 0 {
-		pad = b.columnPadding
+		return b.columnPadding
 	}
-	padding := make([]byte, pad)
-	for i := range padding {
-		padding[i] = 0x20
-	}
-	return fmt.Sprintf("%s%s", s, padding)
+	return pad
 }
 
-// resultCSV returns the benchmark results as []string.
-func (b *Benches) resultCSV(i int) []string {
-	return []string{b.OpsString(b.Benchmarks[i]), b.NsOpString(b.Benchmarks[i]), b.BytesOpString(b.Benchmarks[i]), b.AllocsOpString(b.Benchmarks[i])}
+// writeColumnR writes s, right justified in a column of width w, to buf.
+func (b *Benches) writeColumnR(buf *bytes.Buffer, w int, s string) {
+	buf.WriteString(padding(w - utf8.RuneCountInString(s)))
+	buf.WriteString(s)
+	buf.WriteString(padding(b.columnPadding))
+}
+
+// writeColumnL writes s, left justified in a column of width w, to buf.
+func (b *Benches) writeColumnL(buf *bytes.Buffer, w int, s string) {
+	buf.WriteString(s)
+	buf.WriteString(padding(b.columnLPadding(w, s)))
 }
 
 // csv returns the info of the benchmark at index i as []string.
-func (b Benches) csv(i int) []string {
-	var s []string
+func (b *Benches) csv(i int) []string {
+	s := make([]string, 0, 9)
+	v := b.Benchmarks[i]
 	if b.length.Group > 0 {
-		s = append(s, b.Benchmarks[i].Group)
+		s = append(s, b.Cell(GroupColumn, v))
 	}
 	if b.length.SubGroup > 0 {
-		s = append(s, b.Benchmarks[i].SubGroup)
+		s = append(s, b.Cell(SubGroupColumn, v))
 	}
 	if b.length.Name > 0 {
-		s = append(s, b.Benchmarks[i].Name)
+		s = append(s, b.Cell(NameColumn, v))
 	}
 	if b.length.Desc > 0 {
-		s = append(s, b.Benchmarks[i].Desc)
+		s = append(s, b.Cell(DescColumn, v))
 	}
-	s = append(s, b.resultCSV(i)...)
+	s = append(s, b.Cell(OpsColumn, v), b.Cell(NsOpColumn, v), b.Cell(BytesOpColumn, v), b.Cell(AllocsOpColumn, v))
 	if b.length.Note > 0 {
-		s = append(s, b.Benchmarks[i].Note)
+		s = append(s, b.Cell(NoteColumn, v))
 	}
 	return s
 }
@@ -481,83 +662,164 @@ func (b Benches) csv(i int) []string {
 type StringBench struct {
 	w io.Writer
 	Benches
+	stream
+	wrapWidth int                  // the width Desc and Note cells are wrapped at; 0 is no wrapping.
+	align     map[Column]Alignment // the columns' alignment overrides; see SetAlignment.
+	row       bytes.Buffer         // the buffer the rows are built in; see rowBuffer.
 }
 
-func NewStringBench(w io.Writer) *StringBench {
-	return &StringBench{
+// NewStringBench returns a StringBench that writes to w, configured by the
+// options.
+func NewStringBench(w io.Writer, opts ...Option) *StringBench {
+	b := &StringBench{
 		w: w,
 		Benches: Benches{
 			header:        newHeader(),
 			columnPadding: defaultPadding,
 		},
 	}
+	b.apply(opts)
+	return b
+}
+
+// Append adds Benches to the slice of Benchmarks.  When streaming, the rows
+// for the benches are written immediately.
+func (b *StringBench) Append(benches ...Bench) {
+	b.add(b.written, benches)
+	if b.streaming {
+		b.flush()
+		b.flushWriter(b.w)
+	}
+}
+
+// flush writes the table's preamble and header, if they haven't been
+// written, and any rows that haven't been written.
+func (b *StringBench) flush() {
+	if b.err != nil || len(b.Benchmarks) == 0 {
+		return
+	}
+	if !b.started {
+		b.started = true
+		b.setLength()
+		b.setWrapLength()
+		b.err = b.writePreamble()
+		if b.err != nil {
+			return
+		}
+		b.WriteHeader()
+		b.WriteSeparatorLine()
+	}
+	for ; b.written < len(b.Benchmarks); b.written++ {
+		b.writeRow(b.written)
+	}
+	b.dropWritten(&b.Benches)
 }
 
 // Out writes the benchmark results.
 func (b *StringBench) Out() error {
+	if b.optErr != nil {
+		return b.optErr
+	}
+	if b.streaming {
+		b.flush()
+		if b.err != nil {
+			return b.err
+		}
+		goto footer
+	}
 	b.setLength()
+	b.setWrapLength()
+	if err := b.writePreamble(); err != nil {
+		return err
+	}
+	// Write the headers
+	b.WriteHeader()
+	// Write the separator line
+	b.WriteSeparatorLine()
+	b.WriteResults()
+footer:
+	// The set's note follows the table.
+	if len(b.Note) > 0 {
+		fmt.Fprintf(b.w, "\n%s\n", b.Note)
+	}
+	if len(b.footer) > 0 {
+		fmt.Fprintf(b.w, "\n%s\n", b.footer)
+	}
+	return nil
+}
+
+// writePreamble writes everything that precedes the table: the name,
+// description, system info, and warnings.
+func (b *StringBench) writePreamble() error {
+	if len(b.title) > 0 {
+		fmt.Fprintf(b.w, "%s\n\n", underline(b.title, "="))
+	}
 	if len(b.Name) > 0 {
 		fmt.Fprintln(b.w, b.Name)
 	}
 	// If this has a desc, output that next.
 	if len(b.Desc) > 0 {
-		fmt.Fprintln(b.w, b.Name)
+		fmt.Fprintln(b.w, b.Desc)
 	}
-	// Write the detailed system info; if applicable.
-	if b.includeDetailedSystemInfo {
-		inf, err := b.SystemInfo()
-		if err != nil {
-			return err
-		}
-		fmt.Fprintln(b.w, inf)
-		goto writeTable
+	if len(b.Name) > 0 || len(b.Desc) > 0 {
+		fmt.Fprintln(b.w)
+	}
+	// Write the run info
+	info := b.runInfo()
+	for _, v := range info {
+		fmt.Fprintf(b.w, "%-13s%s\n", v[0]+":", v[1])
+	}
+	if len(info) > 0 {
+		fmt.Fprintln(b.w)
 	}
 	// Write the system info; if applicable.
-	if b.includeSystemInfo {
-		inf, err := b.SystemInfo()
-		if err != nil {
-			return err
+	kv, err := b.sysInfoKeyValues()
+	if err != nil {
+		return err
+	}
+	if kv != nil {
+		w := 13
+		if b.includeDetailedSystemInfo {
+			w = 12
 		}
-		fmt.Fprintln(b.w, inf)
+		fmt.Fprintln(b.w, formatKeyValues(kv, w))
 	}
-writeTable:
-
-	// Write the headers
-	b.WriteHeader()
-	// Write the separator line
-	b.WriteSeparatorLine()
-	// set it so that the first section doesn't get an extraneous line break.
-	b.WriteResults()
-	// If this has a note, output that.
-	if len(b.Desc) > 0 {
-		fmt.Fprintln(b.w, b.Name)
+	for _, v := range b.Warnings {
+		fmt.Fprintf(b.w, "Warning: %s\n", v)
+	}
+	if len(b.Warnings) > 0 {
+		fmt.Fprintln(b.w)
+	}
+	if len(b.caption) > 0 {
+		fmt.Fprintln(b.w, b.caption)
 	}
 	return nil
 }
 
 // WriteHeader writes the table header to the writer.
 func (b *StringBench) WriteHeader() {
-	var buf bytes.Buffer
+	buf := b.rowBuffer()
 	if b.length.Group > 0 {
-		buf.WriteString(b.columnL(b.length.Group, b.header.Group))
+		b.writeColumnL(buf, b.length.Group, b.header.Group)
 	}
 	if b.length.SubGroup > 0 {
-		buf.WriteString(b.columnL(b.length.SubGroup, b.header.SubGroup))
+		b.writeColumnL(buf, b.length.SubGroup, b.header.SubGroup)
 	}
 	if b.length.Name > 0 {
-		buf.WriteString(b.columnL(b.length.Name, b.header.Name))
+		b.writeColumnL(buf, b.length.Name, b.header.Name)
 	}
 	if b.length.Desc > 0 {
-		buf.WriteString(b.columnL(b.length.Desc, b.header.Desc))
+		b.writeColumnL(buf, b.length.Desc, b.header.Desc)
 	}
-	buf.WriteString(b.columnL(b.length.Ops, b.header.Ops))
-	buf.WriteString(b.columnL(b.length.NsOp, b.header.NsOp))
-	buf.WriteString(b.columnL(b.length.BytesOp, b.header.BytesOp))
-	buf.WriteString(b.columnL(b.length.AllocsOp, b.header.AllocsOp))
+	b.writeColumnL(buf, b.length.Ops, b.header.Ops)
+	b.writeColumnL(buf, b.length.NsOp, b.header.NsOp)
+	b.writeColumnL(buf, b.length.BytesOp, b.header.BytesOp)
+	b.writeColumnL(buf, b.length.AllocsOp, b.header.AllocsOp)
 	if b.length.Note > 0 {
 		buf.WriteString(b.header.Note)
 	}
-	fmt.Fprintln(b.w, buf.String())
+	buf.WriteByte('\n')
+	b.w.Write(buf.Bytes())
 }
 
 // WriteSeparatorLine writes a line consisting of dashes to the writer.
@@ -588,64 +850,287 @@ func (b *StringBench) WriteSeparatorLine() {
 	fmt.Fprintln(b.w, buf.String())
 }
 
-// WriteResults writes the benchmark results to the writer.
+// WriteResults writes the benchmark results to the writer.  The rows are
+// formatted concurrently if the parallelism is set; see SetParallelism.
 func (b *StringBench) WriteResults() {
-	var buf bytes.Buffer
-	priorGroup := b.Benchmarks[0].Group
-	for i, bench := range b.Benchmarks {
-		buf.Reset()
-		if b.sectionPerGroup && bench.Group != priorGroup {
-			buf.WriteRune('\n')
-		}
-		priorGroup = bench.Group
+	formatRows(len(b.Benchmarks), b.parallelism, func(buf *bytes.Buffer, i int) error {
+		b.formatRow(buf, i)
+		return nil
+	}, func(p []byte) error {
+		_, err := b.w.Write(p)
+		return err
+	})
+}
 
+// writeRow writes the row for the benchmark at index i to the writer.
+func (b *StringBench) writeRow(i int) {
+	buf := b.rowBuffer()
+	b.formatRow(buf, i)
+	b.w.Write(buf.Bytes())
+}
+
+// formatRow writes the row for the benchmark at index i, and the lines of
+// its wrapped cells, to buf.  If there is a section per group and the
+// benchmark's group is different than the prior benchmark's, the row is
+// preceded by an empty line.  It only reads from b, so rows can be
+// formatted concurrently, each into its own buffer.
+func (b *StringBench) formatRow(buf *bytes.Buffer, i int) {
+	bench := b.Benchmarks[i]
+	if b.sectionPerGroup && i > 0 && bench.Group != b.Benchmarks[i-1].Group {
+		buf.WriteByte('\n')
+	}
+	if b.length.Group > 0 {
+		b.writeColumn(buf, GroupColumn, b.length.Group, b.Cell(GroupColumn, bench))
+	}
+	if b.length.SubGroup > 0 {
+		b.writeColumn(buf, SubGroupColumn, b.length.SubGroup, b.Cell(SubGroupColumn, bench))
+	}
+	if b.length.Name > 0 {
+		b.writeColumn(buf, NameColumn, b.length.Name, b.Cell(NameColumn, bench))
+	}
+	// the cells are only split into lines when they're being wrapped.
+	var desc, note []string
+	if b.length.Desc > 0 {
+		d := b.Cell(DescColumn, bench)
+		if b.wrapWidth > 0 {
+			desc = wrapCell(d, b.wrapWidth)
+			d = desc[0]
+		}
+		b.writeColumn(buf, DescColumn, b.length.Desc, d)
+	}
+	b.writeBenchColumns(buf, bench)
+	if b.length.Note > 0 {
+		n := b.Cell(NoteColumn, bench)
+		if b.wrapWidth > 0 {
+			note = wrapCell(n, b.wrapWidth)
+			n = note[0]
+		}
+		b.writeColumn(buf, NoteColumn, b.length.Note, n)
+	}
+	buf.WriteByte('\n')
+	// the rest of the wrapped cells are written on their own lines, with
+	// the other columns empty.
+	for j := 1; j < len(desc) || j < len(note); j++ {
+		off := buf.Len()
 		if b.length.Group > 0 {
-			buf.WriteString(b.columnL(b.length.Group, bench.Group))
+			b.writeColumnL(buf, b.length.Group, "")
 		}
 		if b.length.SubGroup > 0 {
-			buf.WriteString(b.columnL(b.length.SubGroup, bench.SubGroup))
+			b.writeColumnL(buf, b.length.SubGroup, "")
 		}
 		if b.length.Name > 0 {
-			buf.WriteString(b.columnL(b.length.Name, bench.Name))
+			b.writeColumnL(buf, b.length.Name, "")
 		}
 		if b.length.Desc > 0 {
-			buf.WriteString(b.columnL(b.length.Desc, bench.Desc))
+			var s string
+			if j < len(desc) {
+				s = desc[j]
+			}
+			b.writeColumn(buf, DescColumn, b.length.Desc, s)
 		}
-		buf.WriteString(b.BenchString(i))
-		if b.length.Note > 0 {
-			buf.WriteString(b.Note)
+		if b.length.Note > 0 && j < len(note) {
+			for _, l := range []int{b.length.Ops, b.length.NsOp, b.length.BytesOp, b.length.AllocsOp} {
+				b.writeColumnL(buf, l, "")
+			}
+			b.writeColumn(buf, NoteColumn, b.length.Note, note[j])
 		}
-		fmt.Fprintln(b.w, buf.String())
+		buf.Truncate(off + len(bytes.TrimRight(buf.Bytes()[off:], " ")))
+		buf.WriteByte('\n')
 	}
 }
 
+// rowBuffer returns the StringBench's row buffer, reset.  The buffer is
+// reused for each row so the rows don't allocate their own.
+func (b *StringBench) rowBuffer() *bytes.Buffer {
+	b.row.Reset()
+	return &b.row
+}
+
 // BenchString generates the Ops, ns/Ops, B/Ops, and Allocs/Op string for a
 // given benchmark result.
 func (b *StringBench) BenchString(i int) string {
-	return fmt.Sprintf("%s%s%s%s", b.columnR(b.length.Ops, b.OpsString(b.Benchmarks[i])), b.columnR(b.length.NsOp, b.NsOpString(b.Benchmarks[i])), b.columnR(b.length.BytesOp, b.BytesOpString(b.Benchmarks[i])), b.columnR(b.length.AllocsOp, b.AllocsOpString(b.Benchmarks[i])))
+	var buf bytes.Buffer
+	b.writeBenchColumns(&buf, b.Benchmarks[i])
+	return buf.String()
+}
+
+// writeBenchColumns writes the Ops, ns/Ops, B/Ops, and Allocs/Op columns for
+// v to buf.
+func (b *StringBench) writeBenchColumns(buf *bytes.Buffer, v Bench) {
+	b.writeColumn(buf, OpsColumn, b.length.Ops, b.Cell(OpsColumn, v))
+	b.writeColumn(buf, NsOpColumn, b.length.NsOp, b.Cell(NsOpColumn, v))
+	b.writeColumn(buf, BytesOpColumn, b.length.BytesOp, b.Cell(BytesOpColumn, v))
+	b.writeColumn(buf, AllocsOpColumn, b.length.AllocsOp, b.Cell(AllocsOpColumn, v))
 }
 
 // CSVBench Benches is a collection of benchmark informtion and their results.
-// The output is written as CSV to the writer.  The Name, Desc, and Note
-// fields are ignored
+// The output is written as CSV to the writer.  By default the header is the
+// first record and only the benchmarks' records follow it, so the output can
+// be read by a csv.Reader.  With CSVPreamble, the set's Name, Desc, run info,
+// and Meta are written as key value records before the header, and its Note
+// after the records.
 type CSVBench struct {
 	Benches
-	w *csv.Writer
+	w   *csv.Writer
+	out io.Writer // the writer w writes to.
+	stream
+	hdr []string // the header row; set when streaming.
 }
 
-func NewCSVBench(w io.Writer) *CSVBench {
-	return &CSVBench{
-		w: csv.NewWriter(w),
+// NewCSVBench returns a CSVBench that writes to w, configured by the
+// options.
+func NewCSVBench(w io.Writer, opts ...Option) *CSVBench {
+	b := &CSVBench{
+		w:   csv.NewWriter(w),
+		out: w,
 		Benches: Benches{
 			header:        newHeader(),
 			columnPadding: defaultPadding,
 		},
 	}
+	b.apply(opts)
+	return b
+}
+
+// Append adds Benches to the slice of Benchmarks.  When streaming, the
+// records for the benches are written, and flushed, immediately.
+func (b *CSVBench) Append(benches ...Bench) {
+	b.add(b.written, benches)
+	if b.streaming {
+		b.flush()
+		b.flushWriter(b.out)
+	}
+}
+
+// flush writes the header record, if it hasn't been written, and any records
+// that haven't been written.
+func (b *CSVBench) flush() {
+	if b.err != nil || len(b.Benchmarks) == 0 {
+		return
+	}
+	defer b.w.Flush()
+	// CSV isn't localized so it can be parsed.
+	b.locale = nil
+	if !b.started {
+		b.started = true
+		b.err = csvPreamble(b.w, &b.Benches)
+		if b.err != nil {
+			return
+		}
+		b.setLength()
+		b.hdr = csvHeader(&b.Benches)
+		b.err = b.w.Write(b.hdr)
+		if b.err != nil {
+			return
+		}
+	}
+	for ; b.written < len(b.Benchmarks); b.written++ {
+		b.err = csvRecord(b.w, &b.Benches, b.hdr, b.written)
+		if b.err != nil {
+			return
+		}
+	}
+	b.dropWritten(&b.Benches)
 }
 
 // Out writes the benchmark results to the writer as strings.
 func (b *CSVBench) Out() error {
-	return csvOut(b.w, b.Benches)
+	if b.optErr != nil {
+		return b.optErr
+	}
+	if b.streaming {
+		b.flush()
+		if b.err != nil {
+			return b.err
+		}
+		err := csvFooter(b.w, &b.Benches)
+		if err != nil {
+			return err
+		}
+		b.w.Flush()
+		return b.w.Error()
+	}
+	return csvOut(b.w, b.out, b.Benches)
+}
+
+// stream holds the state of a Benchmarker that writes its rows as benches
+// are appended, instead of waiting for Out.
+type stream struct {
+	streaming bool  // whether or not the rows are written as benches are appended.
+	autoFlush bool  // whether or not the writer is flushed after the rows are written.
+	discard   bool  // whether or not the benches are dropped once their rows are written.
+	started   bool  // whether or not the header has been written.
+	written   int   // the number of benches that have been written.
+	err       error // the first write error; it is returned by Out.
+}
+
+// Stream sets whether or not rows are written as benches are appended.  When
+// streaming, the columns, and their widths, are determined by the first
+// benches appended, so later values that are wider won't be aligned.  Out
+// writes anything that hasn't been written along with any footer.  Any
+// error that occurs while streaming is returned by Out.
+func (s *stream) Stream(v bool) {
+	s.streaming = v
+}
+
+// AutoFlush sets whether or not each bench is written, and the writer
+// flushed, as it's appended; Out only writes what follows the rows.  It's
+// Stream with the writer, if it's buffered, e.g. a bufio.Writer or an
+// http.ResponseWriter, flushed after every Append so the rows are seen as
+// they're written.
+func (s *stream) AutoFlush(v bool) {
+	s.streaming = v
+	s.autoFlush = v
+}
+
+// DiscardWritten sets whether or not the benches are dropped from
+// Benchmarks once their rows have been written; setting it also sets
+// Stream.  This is for large sets, e.g. parameter sweeps with hundreds of
+// thousands of benches: the output is written in a single pass and only the
+// benches that haven't been written are kept, so memory is bounded by the
+// largest Append instead of growing with the set.  The rows are built in a
+// buffer that's reused, see Stream for how the column widths are
+// determined.  After Out, Benchmarks only has the last bench.
+func (s *stream) DiscardWritten(v bool) {
+	if v {
+		s.streaming = true
+	}
+	s.discard = v
+}
+
+// dropWritten drops the benches that have been written, if discarding,
+// except for the last one, which the next row's section break is decided
+// with.
+func (s *stream) dropWritten(b *Benches) {
+	if !s.discard || s.written < 2 {
+		return
+	}
+	n := copy(b.Benchmarks, b.Benchmarks[s.written-1:])
+	// clear the dropped benches so their strings and samples can be freed.
+	for i := n; i < len(b.Benchmarks); i++ {
+		b.Benchmarks[i] = Bench{}
+	}
+	b.Benchmarks = b.Benchmarks[:n]
+	s.written = 1
+}
+
+// flushWriter flushes w, if auto flushing and w has a Flush method.
+func (s *stream) flushWriter(w io.Writer) {
+	if !s.autoFlush || s.err != nil {
+		return
+	}
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		s.err = f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+}
+
+// Streamer is implemented by Benchmarkers that can write their rows as
+// benches are appended.
+type Streamer interface {
+	Stream(bool)
 }
 
 // MDBench Benches is a collection of benchmark informtion and their results.
@@ -657,150 +1142,175 @@ type MDBench struct {
 	SectionHeaderHash string // the markdown header hash for section names, when applicable
 }
 
-func NewMDBench(w io.Writer) *MDBench {
-	return &MDBench{
+// defaultSectionHeaderHash is the markdown header hash for section names.
+const defaultSectionHeaderHash = "####"
+
+// NewMDBench returns an MDBench that writes to w, configured by the options.
+func NewMDBench(w io.Writer, opts ...Option) *MDBench {
+	b := &MDBench{
 		w: w,
 		Benches: Benches{
 			header:        newHeader(),
 			columnPadding: defaultPadding,
 		},
-		SectionHeaderHash: "####",
+		SectionHeaderHash: defaultSectionHeaderHash,
 	}
+	b.apply(opts)
+	return b
 }
 
 // Out writes the benchmark results to the writer as a Markdown Table.
 func (b *MDBench) Out() error {
-	// Write the detailed system info; if applicable.
-	if b.includeDetailedSystemInfo {
-		inf, err := b.SystemInfo()
-		if err != nil {
-			return err
-		}
-		fmt.Fprintln(b.w, inf)
-		goto output
+	if b.optErr != nil {
+		return b.optErr
 	}
-	// Write the system info; if applicable.
-	if b.includeSystemInfo {
-		inf, err := b.SystemInfo()
-		if err != nil {
-			return err
-		}
-		fmt.Fprintln(b.w, inf)
+	if len(b.title) > 0 {
+		fmt.Fprintf(b.w, "# %s\n\n", b.title)
+	}
+	if len(b.Name) > 0 {
+		fmt.Fprintf(b.w, "## %s\n\n", b.Name)
+	}
+	if len(b.Desc) > 0 {
+		fmt.Fprintf(b.w, "%s\n\n", b.Desc)
+	}
+	// Write the run info
+	info := b.runInfo()
+	for _, v := range info {
+		fmt.Fprintf(b.w, "%s: %s  \n", v[0], v[1])
+	}
+	if len(info) > 0 {
+		fmt.Fprintln(b.w)
+	}
+	// Write the system info, as a definition list; if applicable.
+	kv, err := b.sysInfoKeyValues()
+	if err != nil {
+		return err
+	}
+	for _, v := range kv {
+		fmt.Fprintf(b.w, "%s\n: %s\n", v[0], v[1])
+	}
+	if len(kv) > 0 {
+		fmt.Fprintln(b.w)
+	}
+	for _, v := range b.Warnings {
+		fmt.Fprintf(b.w, "__Warning:__ %s  \n", v)
+	}
+	if len(b.Warnings) > 0 {
+		fmt.Fprintln(b.w)
+	}
+	if len(b.caption) > 0 {
+		fmt.Fprintf(b.w, "_%s_\n\n", b.caption)
 	}
-
-output:
 	b.setLength()
-	// Each section may end up as it's own table so we really have a slice
-	// of csv, e.g. [][][]string
 	// build the alignment & header row
 	var hdr, align []string
 	// Don't add a group column if groups aren't used or if the group is used as section name
 	// and output is being split into sections.
 	if b.length.Group > 0 && !b.nameSection() {
-		align = append(align, "l")
+		align = append(align, ":--")
 		hdr = append(hdr, b.header.Group)
 	}
 	if b.length.SubGroup > 0 {
-		align = append(align, "l")
+		align = append(align, ":--")
 		hdr = append(hdr, b.header.SubGroup)
 	}
 	if b.length.Name > 0 {
-		align = append(align, "l")
+		align = append(align, ":--")
 		hdr = append(hdr, b.header.Name)
 	}
 	if b.length.Desc > 0 {
-		align = append(align, "l")
+		align = append(align, ":--")
 		hdr = append(hdr, b.header.Desc)
 	}
-	align = append(align, []string{"r", "r", "r", "r"}...)
+	align = append(align, []string{"--:", "--:", "--:", "--:"}...)
 	hdr = append(hdr, []string{b.header.Ops, b.header.NsOp, b.header.BytesOp, b.header.AllocsOp}...)
 	if b.length.Note > 0 {
-		align = append(align, "l")
+		align = append(align, ":--")
 		hdr = append(hdr, b.header.Note)
 	}
-	empty := make([]string, len(hdr))
-	// get a csv writer
-	var buff bytes.Buffer // holds the generated CSV
-	w := csv.NewWriter(&buff)
-	// CSV to Markdown table transmogrifier:
-	t := csv2md.NewTransmogrifier(&buff, b.w)
-
-	// Configure the transmogrifier.
-	// The header isn't part of the data; it's set explicitly.
-	t.HasHeaderRecord = false
-	t.SetFieldNames(hdr)
-	t.SetFieldAlignment(align)
-	var priorGroup string
-	if b.sectionHeaders {
-		priorGroup = b.Benchmarks[0].Group
-	}
-	for i, v := range b.Benchmarks {
-		if priorGroup != v.Group && b.sectionPerGroup {
-			// if each section doesn't get it's own header row, just add an
-			// empty row instead of creating a new table
-			if !b.sectionHeaders {
-				// If there aren't section headers but sections are named,
-				// make the first cell of the empty row the name.
-				if b.nameSection() {
-					empty[0] = b.SectionName(v.Group)
-				}
-				if i > 0 || !b.sectionHeaders {
-					err := w.Write(empty)
-					if err != nil {
-						return err
-					}
-				}
-				goto process
-			}
-			// If the sections are being named and there are section headers,
-			// the section name is a MD header.
-			if b.nameSection() {
-				_, err := b.w.Write([]byte(b.SectionName(priorGroup)))
-				if err != nil {
-					return err
-				}
-			}
-			// Get a markdown table transmogrifier and configure
-			w.Flush()
-			err := t.MDTable()
-			if err != nil {
-				return err
-			}
-			_, err = b.w.Write([]byte{'\n'})
-			if err != nil {
-				return err
-			}
-			buff.Reset()
-		}
-	process:
-		line := b.csv(i)
-		if b.nameSection() {
-			//fmt.Printf("%#v\n", line)
-			line = line[1:]
-			//fmt.Printf("%#v\n", line)
-		}
-		err := w.Write(line)
+	// The rows are written as they're generated, or, if the parallelism is
+	// set, a chunk at a time.  When each section gets its own header row,
+	// each section is its own table.
+	if len(b.Benchmarks) == 0 {
+		err = mdHeader(b.w, hdr, align)
+	} else {
+		err = formatRows(len(b.Benchmarks), b.parallelism, func(buf *bytes.Buffer, i int) error {
+			b.formatRow(buf, i, hdr, align)
+			return nil
+		}, func(p []byte) error {
+			_, err := b.w.Write(p)
+			return err
+		})
+	}
+	if err != nil {
+		return err
+	}
+	// The set's note follows the table.
+	if len(b.Note) > 0 {
+		_, err = fmt.Fprintf(b.w, "\n%s\n", b.Note)
 		if err != nil {
 			return err
 		}
-		priorGroup = v.Group
+	}
+	if len(b.footer) > 0 {
+		_, err = fmt.Fprintf(b.w, "\n%s\n", b.footer)
+	}
+	return err
+}
+
+// formatRow writes the row for the benchmark at index i to buf, preceded by
+// the table's header row, for the first row, and by whatever starts the
+// benchmark's section, if it starts one.  It only reads from b, so rows can
+// be formatted concurrently, each into its own buffer.
+func (b *MDBench) formatRow(buf *bytes.Buffer, i int, hdr, align []string) {
+	v := b.Benchmarks[i]
+	var priorGroup string
+	if i > 0 {
+		priorGroup = b.Benchmarks[i-1].Group
+	}
+	if i == 0 || b.sectionPerGroup && b.sectionHeaders && v.Group != priorGroup {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		// If the sections are being named and there are section headers,
+		// the section name is a MD header.
+		if b.sectionHeaders && b.nameSection() {
+			buf.WriteString(b.SectionName(v.Group))
+		}
+		mdHeader(buf, hdr, align)
 	}
 	// if each section doesn't get it's own header row, just add an
 	// empty row instead of creating a new table
-	if !b.sectionHeaders {
-		goto finish
-	}
-	// If the sections are being named and there are section headers,
-	// the section name is a MD header.
-	if b.nameSection() {
-		_, err := b.w.Write([]byte(b.SectionName(priorGroup)))
-		if err != nil {
-			return err
+	if b.sectionPerGroup && !b.sectionHeaders && v.Group != priorGroup {
+		empty := make([]string, len(hdr))
+		// If sections are named, make the first cell of the empty row
+		// the name.
+		if b.nameSection() {
+			empty[0] = b.SectionName(v.Group)
 		}
+		mdRow(buf, empty)
 	}
-finish:
-	w.Flush()
-	return t.MDTable()
+	line := b.csv(i)
+	if b.nameSection() && b.length.Group > 0 {
+		line = line[1:]
+	}
+	mdRow(buf, line)
+}
+
+// mdHeader writes a Markdown table's header row, followed by its alignment
+// row.
+func mdHeader(w io.Writer, hdr, align []string) error {
+	err := mdRow(w, hdr)
+	if err != nil {
+		return err
+	}
+	return mdRow(w, align)
+}
+
+// mdRow writes a Markdown table row.
+func mdRow(w io.Writer, cells []string) error {
+	_, err := io.WriteString(w, "|"+strings.Join(cells, "|")+"|\n")
+	return err
 }
 
 // Whether or not the section should be named
@@ -841,19 +1351,60 @@ type length struct {
 // Bench holds information about a benchmark.  If there is a value for Group,
 // the output will have a break between the groups.
 type Bench struct {
-	Group      string // the Grouping of benchmarks this bench belongs to.
-	SubGroup   string // the Sub-Group this bench belongs to; mainly for additional sort options.
-	Name       string // Name of the bench.
-	Desc       string // Description of the bench; optional.
-	Note       string // Additional note about the bench; optional.
-	Iterations int    // number of test iterations; default 1
-	Result            // A map of Result keyed by something.
+	Group       string   // the Grouping of benchmarks this bench belongs to.
+	SubGroup    string   // the Sub-Group this bench belongs to; mainly for additional sort options.
+	Name        string   // Name of the bench.
+	Desc        string   // Description of the bench; optional.
+	Note        string   // Additional note about the bench; optional.
+	Iterations  int      // number of test iterations; default 1
+	CPUProfile  string   // path to the bench's CPU profile; if one was captured.
+	HeapProfile string   // path to the heap profile taken after the bench ran; if one was captured.
+	Err         string   // the error, or panic, text if the bench failed.
+	Samples     []Result // the Result of each run of the bench; optional, see AddSample.
+	Result               // A map of Result keyed by something.
 }
 
 func NewBench(s string) Bench {
 	return Bench{Name: s, Iterations: 1}
 }
 
+// ID returns the bench's Group, SubGroup, and Name joined by a '/', e.g.
+// "json/decode/small"; empty parts are skipped.  It identifies the bench
+// across runs.
+func (b Bench) ID() string {
+	return benchID(b)
+}
+
+// Failed returns whether or not the bench failed.
+func (b Bench) Failed() bool {
+	return b.Err != ""
+}
+
+// String returns a one line summary of the bench, e.g.
+// "json/decode/small: 1000 ops  100 ns/op  16 bytes/op  1 allocs/op".
+func (b Bench) String() string {
+	if b.Failed() {
+		return b.ID() + ": FAILED: " + b.Err
+	}
+	if b.Iterations < 1 {
+		b.Iterations = 1
+	}
+	v := Benches{includeOpsColumnDesc: true}
+	return fmt.Sprintf("%s: %s  %s  %s  %s", b.ID(), v.OpsString(b), v.NsOpString(b), v.BytesOpString(b), v.AllocsOpString(b))
+}
+
+// NoteString returns the bench's note for output.  If the bench failed, the
+// note is prefixed with the failure so failed rows stand out.
+func (b Bench) NoteString() string {
+	if !b.Failed() {
+		return b.Note
+	}
+	if b.Note == "" {
+		return "FAILED: " + b.Err
+	}
+	return "FAILED: " + b.Err + "; " + b.Note
+}
+
 // Result holds information about a benchmark's results.
 type Result struct {
 	Ops      int64 // the number of operations performed
@@ -862,9 +1413,23 @@ type Result struct {
 	AllocsOp int64 // The number of Allocations per Op.
 }
 
+// add returns the sum of r and v.  This is used to accumulate the Results of
+// multiple iterations of a benchmark.
+func (r Result) add(v Result) Result {
+	r.Ops += v.Ops
+	r.NsOp += v.NsOp
+	r.BytesOp += v.BytesOp
+	r.AllocsOp += v.AllocsOp
+	return r
+}
+
 // ResultFromBenchmarkResult creates a Result{} from a testing.BenchmarkResult.
 func ResultFromBenchmarkResult(br testing.BenchmarkResult) Result {
 	var r Result
+	// A benchmark that failed, or was skipped, has no ops.
+	if br.N == 0 {
+		return r
+	}
 	r.Ops = int64(br.N)
 	r.NsOp = br.T.Nanoseconds() / r.Ops
 	r.BytesOp = int64(br.MemBytes) / r.Ops
@@ -872,67 +1437,133 @@ func ResultFromBenchmarkResult(br testing.BenchmarkResult) Result {
 	return r
 }
 
-const alphanum = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-
-var alen = uint32(len(alphanum))
-
-// NewSeed gets a random int64 to use for a seed value.
-func NewSeed() int64 {
-	bi := big.NewInt(1<<63 - 1)
-	r, err := crand.Int(crand.Reader, bi)
+// csvOut generates the CSV from a slice of Benches; out is the writer w
+// writes to.
+func csvOut(w *csv.Writer, out io.Writer, benches Benches) error {
+	defer w.Flush()
+	// CSV isn't localized so it can be parsed.
+	benches.locale = nil
+	err := csvPreamble(w, &benches)
 	if err != nil {
-		panic(fmt.Sprintf("entropy read error: %s\n", err))
+		return err
 	}
-	return (r.Int64())
-}
-
-// RandString returns a randomly generated string of length l.
-func RandString(l uint32) string {
-	return string(RandBytes(l))
-}
-
-// RandBytes returns a randomly generated []byte of length l.  The values of
-// these bytes are restricted to the ASCII alphanum range; that doesn't matter
-// for the purposes of these benchmarks.
-func RandBytes(l uint32) []byte {
-	b := make([]byte, l)
-	for i := 0; i < int(l); i++ {
-		b[i] = alphanum[int(prng.Bound(alen))]
+	benches.setLength()
+	hdr := csvHeader(&benches)
+	err = w.Write(hdr)
+	if err != nil {
+		return err
 	}
-	return b
+	if benches.parallelism < 2 {
+		for i := range benches.Benchmarks {
+			err := csvRecord(w, &benches, hdr, i)
+			if err != nil {
+				return err
+			}
+		}
+		return csvFooter(w, &benches)
+	}
+	// The records are formatted concurrently, each chunk by its own csv
+	// writer, and written to out, in order, after what w has buffered.
+	w.Flush()
+	err = w.Error()
+	if err != nil {
+		return err
+	}
+	err = formatRows(len(benches.Benchmarks), benches.parallelism, func(buf *bytes.Buffer, i int) error {
+		cw := csv.NewWriter(buf)
+		cw.Comma, cw.UseCRLF = w.Comma, w.UseCRLF
+		err := csvRecord(cw, &benches, hdr, i)
+		if err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	}, func(p []byte) error {
+		_, err := out.Write(p)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return csvFooter(w, &benches)
 }
 
-// RandBool returns a pseudo-random bool value.
-func RandBool() bool {
-	if prng.Int63()%2 == 0 {
-		return false
+// csvFooter writes the set's note, if there is one, after an empty record,
+// if the preamble is written.
+func csvFooter(w *csv.Writer, benches *Benches) error {
+	if !benches.csvPreamble {
+		return nil
+	}
+	if benches.Note != "" {
+		err := w.Write(nil)
+		if err != nil {
+			return err
+		}
+		err = w.Write([]string{"Note", benches.Note})
+		if err != nil {
+			return err
+		}
+	}
+	if benches.footer != "" {
+		err := w.Write(nil)
+		if err != nil {
+			return err
+		}
+		return w.Write([]string{"# " + benches.footer})
 	}
-	return true
+	return nil
 }
 
-// Dot prints a . every second to os.StdOut.
-func Dot(done chan struct{}) {
-	var i int
-	t := time.NewTicker(time.Second)
-	defer t.Stop()
-	for {
-		select {
-		case <-done:
-			return
-		case <-t.C:
-			i++
-			fmt.Fprint(os.Stderr, ".")
-			if i%60 == 0 {
-				fmt.Fprint(os.Stderr, "\n")
-			}
+// csvPreamble writes the title as a comment record, the set's name and
+// description, the run info, e.g. the host and timestamp, and the metadata,
+// and the system info, if applicable, as key,
+// value records followed by an empty record, and the caption as a comment
+// record.  Nothing is written unless CSVPreamble is set.
+func csvPreamble(w *csv.Writer, benches *Benches) error {
+	if !benches.csvPreamble {
+		return nil
+	}
+	kv, err := benches.sysInfoKeyValues()
+	if err != nil {
+		return err
+	}
+	// the run info, which ends with the metadata, precedes the system info.
+	kv = append(benches.runInfo(), kv...)
+	// the set's name and description are first.
+	var set [][2]string
+	if benches.Name != "" {
+		set = append(set, [2]string{"Name", benches.Name})
+	}
+	if benches.Desc != "" {
+		set = append(set, [2]string{"Desc", benches.Desc})
+	}
+	kv = append(set, kv...)
+	if benches.title != "" {
+		err := w.Write([]string{"# " + benches.title})
+		if err != nil {
+			return err
+		}
+	}
+	for _, v := range kv {
+		err := w.Write(v[:])
+		if err != nil {
+			return err
 		}
 	}
+	if len(kv) > 0 || benches.title != "" {
+		err := w.Write(nil)
+		if err != nil {
+			return err
+		}
+	}
+	if benches.caption != "" {
+		return w.Write([]string{"# " + benches.caption})
+	}
+	return nil
 }
 
-// csvOut generates the CSV from a slice of Benches.
-func csvOut(w *csv.Writer, benches Benches) error {
-	defer w.Flush()
-	benches.setLength()
+// csvHeader returns the header record for the benches.
+func csvHeader(benches *Benches) []string {
 	var hdr []string
 	if benches.length.Group > 0 {
 		hdr = append(hdr, "Group")
@@ -950,35 +1581,25 @@ func csvOut(w *csv.Writer, benches Benches) error {
 	if benches.length.Note > 0 {
 		hdr = append(hdr, "Note")
 	}
-	err := w.Write(hdr)
-	if err != nil {
-		return err
-	}
-	var empty []string
-	// if there are sections, make a slice for the empty line between sections
-	if benches.sectionPerGroup {
-		empty = make([]string, len(hdr))
-	}
-	// set it so that the first section doesn't get an extraneous line break.
-	priorGroup := benches.Benchmarks[0].Group
-	for i, v := range benches.Benchmarks {
-		if v.Group != priorGroup && benches.sectionPerGroup {
-			err := w.Write(empty)
+	return hdr
+}
+
+// csvRecord writes the record for the benchmark at index i.  If there is a
+// section per group and the benchmark's group is different than the prior
+// benchmark's, it is preceded by an empty record and, if each section has
+// headers, the header record.
+func csvRecord(w *csv.Writer, benches *Benches, hdr []string, i int) error {
+	if benches.sectionPerGroup && i > 0 && benches.Benchmarks[i].Group != benches.Benchmarks[i-1].Group {
+		err := w.Write(make([]string, len(hdr)))
+		if err != nil {
+			return err
+		}
+		if benches.sectionHeaders {
+			err := w.Write(hdr)
 			if err != nil {
 				return err
 			}
-			if benches.sectionHeaders {
-				err := w.Write(hdr)
-				if err != nil {
-					return err
-				}
-			}
 		}
-		err := w.Write(benches.csv(i))
-		if err != nil {
-			return err
-		}
-		priorGroup = v.Group
 	}
-	return nil
+	return w.Write(benches.csv(i))
 }
@@ -4,7 +4,18 @@
 
 package benchutil
 
-import "testing"
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestSystemInfo(t *testing.T) {
 	b := Benches{}
@@ -45,3 +56,514 @@ func TestAppend(t *testing.T) {
 		t.Errorf("expected Benchmarks len to be 3; got %d", len(b.Benchmarks))
 	}
 }
+
+func testBenches() []Bench {
+	var benches []Bench
+	for i, v := range []string{"a", "b", "c"} {
+		b := NewBench(v)
+		b.Group = "group"
+		b.Ops = int64(1000 * (i + 1))
+		b.NsOp = int64(100 * (i + 1))
+		b.BytesOp = 16
+		b.AllocsOp = 1
+		benches = append(benches, b)
+	}
+	return benches
+}
+
+func TestStringBenchStream(t *testing.T) {
+	var want bytes.Buffer
+	b := NewStringBench(&want)
+	b.Hostname = "host"
+	b.Timestamp = time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+	b.Append(testBenches()...)
+	err := b.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var got bytes.Buffer
+	s := NewStringBench(&got)
+	s.Hostname = b.Hostname
+	s.Timestamp = b.Timestamp
+	s.Stream(true)
+	s.Append(testBenches()...)
+	// the rows are written as they are appended.
+	if got.String() != want.String() {
+		t.Errorf("got %q; want %q", got.String(), want.String())
+	}
+	err = s.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("got %q after Out; want %q", got.String(), want.String())
+	}
+}
+
+func TestCSVBenchParse(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewCSVBench(&buf)
+	b.Name, b.Note = "json", "run on battery"
+	b.SetMeta("dataset", "enwik9")
+	b.SetTitle("Encoding")
+	b.Append(testBenches()...)
+	err := b.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	recs, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(recs) != 4 {
+		t.Fatalf("got %d records; want 4", len(recs))
+	}
+	if recs[0][0] != "Group" {
+		t.Errorf("got %q; want the header first", recs[0])
+	}
+
+	// with the preamble, the records that aren't benchmarks are written.
+	buf.Reset()
+	b = NewCSVBench(&buf, WithCSVPreamble())
+	b.Name = "json"
+	b.Append(testBenches()...)
+	err = b.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.HasPrefix(buf.String(), "Name,json\nHost,") {
+		t.Errorf("got %q; want the preamble", buf.String())
+	}
+}
+
+func TestCSVBenchStream(t *testing.T) {
+	var got bytes.Buffer
+	b := NewCSVBench(&got)
+	b.Stream(true)
+	benches := testBenches()
+	b.Append(benches[0])
+	want := "Group,Name,Operations,Ns/Op,Bytes/Op,Allocs/Op\ngroup,a,1000,100,16,1\n"
+	if got.String() != want {
+		t.Errorf("got %q; want %q", got.String(), want)
+	}
+	b.Append(benches[1:]...)
+	err := b.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want += "group,b,2000,200,16,1\ngroup,c,3000,300,16,1\n"
+	if got.String() != want {
+		t.Errorf("got %q; want %q", got.String(), want)
+	}
+}
+
+func TestAutoFlush(t *testing.T) {
+	type autoFlusher interface {
+		Benchmarker
+		AutoFlush(bool)
+	}
+	for _, test := range []struct {
+		name string
+		new  func(w *bufio.Writer) autoFlusher
+	}{
+		{"txt", func(w *bufio.Writer) autoFlusher { return NewStringBench(w) }},
+		{"csv", func(w *bufio.Writer) autoFlusher { return NewCSVBench(w) }},
+	} {
+		var got bytes.Buffer
+		w := bufio.NewWriter(&got)
+		b := test.new(w)
+		b.AutoFlush(true)
+		b.Append(testBenches()[0])
+		if !strings.Contains(got.String(), "100") {
+			t.Errorf("%s: got %q; want the row flushed through the buffered writer", test.name, got.String())
+		}
+		n := got.Len()
+		b.Append(testBenches()[1])
+		if got.Len() <= n {
+			t.Errorf("%s: got %q; want the second row flushed", test.name, got.String())
+		}
+	}
+}
+
+func TestRunInfo(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewMDBench(&buf)
+	b.Timestamp = time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+	b.Append(testBenches()...)
+	if b.Hostname == "" {
+		t.Error("got an empty hostname; want it set on Append")
+	}
+	err := b.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(buf.String(), "Timestamp: 2016-06-01T12:00:00Z  \n") {
+		t.Errorf("got %q; want the timestamp", buf.String())
+	}
+
+	// CSV has the run info as key, value records before the header.
+	buf.Reset()
+	c := NewCSVBench(&buf, WithCSVPreamble())
+	c.Hostname = "host"
+	c.Timestamp = b.Timestamp
+	c.Git = &GitInfo{Commit: "3f7a2c1"}
+	c.Env = map[string]string{"GOGC": "off"}
+	c.Append(testBenches()...)
+	err = c.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := "Host,host\nTimestamp,2016-06-01T12:00:00Z\nCommit," + c.Git.String() + "\nEnv,GOGC=off\n\nGroup,"
+	if !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("got %q; want the run info as records", buf.String())
+	}
+}
+
+func TestSetMeta(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewStringBench(&buf)
+	b.SetMeta("dataset", "enwik8")
+	b.SetMeta("build flags", "-tags purego")
+	b.SetMeta("dataset", "enwik9")
+	b.Append(testBenches()...)
+	err := b.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(buf.String(), "dataset:     enwik9\nbuild flags: -tags purego\n") {
+		t.Errorf("got %q; want the metadata", buf.String())
+	}
+	buf.Reset()
+	c := NewCSVBench(&buf, WithCSVPreamble())
+	c.SetMeta("dataset", "enwik9")
+	c.Append(testBenches()...)
+	err = c.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(buf.String(), "\ndataset,enwik9\n\nGroup,") {
+		t.Errorf("got %q; want the metadata before the header", buf.String())
+	}
+}
+
+// appendOnly implements only Appender.
+type appendOnly struct{ n int }
+
+func (a *appendOnly) Append(b ...Bench) { a.n += len(b) }
+
+// baseline implements Benchmarker with only its methods, as an
+// implementation outside of this package would.
+type baseline struct {
+	header
+	appendOnly
+}
+
+func (baseline) Out() error                          { return nil }
+func (baseline) IncludeOpsColumnDesc(bool)           {}
+func (baseline) IncludeSystemInfo(bool)              {}
+func (baseline) IncludeDetailedSystemInfo(bool)      {}
+func (baseline) SystemInfo() (string, error)         { return "", nil }
+func (baseline) DetailedSystemInfo() (string, error) { return "", nil }
+func (baseline) SetColumnPadding(int)                {}
+func (baseline) SectionPerGroup(bool)                {}
+func (baseline) SectionHeaders(bool)                 {}
+func (baseline) NameSections(bool)                   {}
+
+func TestInterfaces(t *testing.T) {
+	for _, b := range []Benchmarker{NewStringBench(nil), NewCSVBench(nil), NewMDBench(nil), NewHTMLBench(nil)} {
+		var _ Appender = b
+		var _ Outputter = b
+		var _ HeaderConfigurer = b
+		var _ SectionConfigurer = b
+		// the optional interfaces.
+		var _ Annotator = b.(Annotator)
+		var _ DocumentConfigurer = b.(DocumentConfigurer)
+		var _ ColumnSelector = b.(ColumnSelector)
+	}
+	var a Appender = &appendOnly{}
+	a.Append(testBenches()...)
+	if n := a.(*appendOnly).n; n != len(testBenches()) {
+		t.Errorf("got %d appended; want %d", n, len(testBenches()))
+	}
+	var b Benchmarker = &baseline{}
+	if _, ok := b.(Annotator); ok {
+		t.Error("got an Annotator; want only the Benchmarker methods")
+	}
+
+	// the wrappers forward the optional interfaces to what they wrap.
+	s := NewStringBench(nil)
+	c := NewConcurrentBenches(s)
+	c.SetMeta("dataset", "enwik9")
+	c.HideColumns("group")
+	c.SetTitle("Encoding")
+	if len(s.Meta) != 1 || !s.hidden["group"] || s.title != "Encoding" {
+		t.Errorf("got meta %v, hidden %v, title %q; want them set on the wrapped Benchmarker", s.Meta, s.hidden, s.title)
+	}
+	// a wrapped Benchmarker without them is left as it is.
+	c = NewConcurrentBenches(&baseline{})
+	c.SetMeta("dataset", "enwik9")
+	if _, err := c.Info(); err == nil {
+		t.Error("got no error; want an error for a Benchmarker without system info")
+	}
+}
+
+func TestForceColumn(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewCSVBench(&buf)
+	b.ForceColumn("note", true)
+	b.ForceColumn("desc", true)
+	b.HideColumns("desc")
+	b.ForceColumn("group", false)
+	b.ForceColumn("ops", true)
+	b.Append(testBenches()[0])
+	err := b.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := "Name,Operations,Ns/Op,Bytes/Op,Allocs/Op,Note\na,1000,100,16,1,\n"
+	if buf.String() != want {
+		t.Errorf("got %q; want %q", buf.String(), want)
+	}
+	// a forced column is as wide as its header.
+	var txt bytes.Buffer
+	s := NewStringBench(&txt)
+	s.ForceColumn("note", true)
+	s.Append(testBenches()[0])
+	s.Out()
+	if !strings.Contains(txt.String(), "Allocs/Op  Note") {
+		t.Errorf("got %q; want the empty note column", txt.String())
+	}
+}
+
+func TestSetNameDescNote(t *testing.T) {
+	newBenches := func() *Benches {
+		return &Benches{Name: "json", Desc: "encoding/json benchmarks", Note: "run on battery", Hostname: "host", Benchmarks: testBenches()[:1]}
+	}
+	var txt bytes.Buffer
+	s := NewStringBench(&txt)
+	SetBenches(s, newBenches())
+	if err := s.Out(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.HasPrefix(txt.String(), "json\nencoding/json benchmarks\n\nHost:") {
+		t.Errorf("got %q; want the name and desc first", txt.String())
+	}
+	if !strings.HasSuffix(txt.String(), "\n\nrun on battery\n") {
+		t.Errorf("got %q; want the note last", txt.String())
+	}
+
+	var md bytes.Buffer
+	m := NewMDBench(&md)
+	SetBenches(m, newBenches())
+	if err := m.Out(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.HasPrefix(md.String(), "## json\n\nencoding/json benchmarks\n\nHost: host") {
+		t.Errorf("got %q; want the name and desc first", md.String())
+	}
+	if !strings.HasSuffix(md.String(), "|\n\nrun on battery\n") {
+		t.Errorf("got %q; want the note after the table", md.String())
+	}
+
+	var csv bytes.Buffer
+	c := NewCSVBench(&csv, WithCSVPreamble())
+	SetBenches(c, newBenches())
+	if err := c.Out(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := "Name,json\nDesc,encoding/json benchmarks\nHost,host\n\nGroup,Name,Operations,Ns/Op,Bytes/Op,Allocs/Op\ngroup,a,1000,100,16,1\n\nNote,run on battery\n"
+	if csv.String() != want {
+		t.Errorf("got %q; want %q", csv.String(), want)
+	}
+}
+
+func TestMDSections(t *testing.T) {
+	newBenches := func() *MDBench {
+		var buf bytes.Buffer
+		b := NewMDBench(&buf)
+		// no run info is set, so the output is just the table.
+		for i, g := range []string{"x", "x", "y"} {
+			v := NewBench(string(rune('a' + i)))
+			v.Group = g
+			v.Ops = 10
+			b.Benchmarks = append(b.Benchmarks, v)
+		}
+		return b
+	}
+	tests := []struct {
+		headers, names bool
+		want           string
+	}{
+		{false, false, "|Group|Name|Ops|ns/Op|B/Op|Allocs/Op|\n|:--|:--|--:|--:|--:|--:|\n|||||||\n|x|a|10|0|0|0|\n|x|b|10|0|0|0|\n|||||||\n|y|c|10|0|0|0|\n"},
+		{false, true, "|Name|Ops|ns/Op|B/Op|Allocs/Op|\n|:--|--:|--:|--:|--:|\n|__x__|||||\n|a|10|0|0|0|\n|b|10|0|0|0|\n|__y__|||||\n|c|10|0|0|0|\n"},
+		{true, false, "|Group|Name|Ops|ns/Op|B/Op|Allocs/Op|\n|:--|:--|--:|--:|--:|--:|\n|x|a|10|0|0|0|\n|x|b|10|0|0|0|\n\n|Group|Name|Ops|ns/Op|B/Op|Allocs/Op|\n|:--|:--|--:|--:|--:|--:|\n|y|c|10|0|0|0|\n"},
+		{true, true, "#### x  \n|Name|Ops|ns/Op|B/Op|Allocs/Op|\n|:--|--:|--:|--:|--:|\n|a|10|0|0|0|\n|b|10|0|0|0|\n\n#### y  \n|Name|Ops|ns/Op|B/Op|Allocs/Op|\n|:--|--:|--:|--:|--:|\n|c|10|0|0|0|\n"},
+	}
+	for _, test := range tests {
+		b := newBenches()
+		b.SectionPerGroup(true)
+		b.SectionHeaders(test.headers)
+		b.NameSections(test.names)
+		err := b.Out()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		got := b.w.(*bytes.Buffer).String()
+		if got != test.want {
+			t.Errorf("headers %t, names %t: got %q; want %q", test.headers, test.names, got, test.want)
+		}
+	}
+
+	// the table is streamed: writing stops at the first error, after the
+	// rows before it were written.
+	b := newBenches()
+	w := &limitWriter{n: 2}
+	b.w = w
+	if err := b.Out(); err == nil {
+		t.Error("got no error; want the write error")
+	}
+	if w.writes != 2 {
+		t.Errorf("got %d writes; want 2", w.writes)
+	}
+
+	// an empty set with section headers is just the table's header.
+	var buf bytes.Buffer
+	m := NewMDBench(&buf, WithSections(), WithSectionHeaders())
+	if err := m.Out(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if buf.String() != "|Ops|ns/Op|B/Op|Allocs/Op|\n|--:|--:|--:|--:|\n" {
+		t.Errorf("got %q", buf.String())
+	}
+
+	// with the group column hidden, named sections don't drop another cell.
+	b = newBenches()
+	b.Benchmarks[0].SubGroup = "small"
+	b.SectionPerGroup(true)
+	b.NameSections(true)
+	b.HideColumns("group")
+	if err := b.Out(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := "|Sub-Group|Name|Ops|ns/Op|B/Op|Allocs/Op|\n|:--|:--|--:|--:|--:|--:|\n|__x__||||||\n|small|a|10|0|0|0|\n||b|10|0|0|0|\n|__y__||||||\n||c|10|0|0|0|\n"
+	if got := b.w.(*bytes.Buffer).String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+// limitWriter fails the writes after the first n.
+type limitWriter struct {
+	n      int
+	writes int
+}
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	if w.writes >= w.n {
+		return 0, errors.New("limit reached")
+	}
+	w.writes++
+	return len(p), nil
+}
+
+func TestStringBenchAllocs(t *testing.T) {
+	b := NewStringBench(ioutil.Discard)
+	b.Append(testBenches()...)
+	b.setLength()
+	// the row buffer grows on the first use.
+	b.WriteHeader()
+	if n := testing.AllocsPerRun(100, b.WriteHeader); n != 0 {
+		t.Errorf("got %v allocs for the header; want 0", n)
+	}
+	// only the formatted numbers are allocated; the padding and the row
+	// aren't.
+	if n := testing.AllocsPerRun(100, func() { b.writeRow(1) }); n > 4 {
+		t.Errorf("got %v allocs per row; want at most 4", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { b.columnR(10, "abc") }); n > 1 {
+		t.Errorf("got %v allocs per column; want at most 1", n)
+	}
+}
+
+func TestDiscardWritten(t *testing.T) {
+	var want, got bytes.Buffer
+	// the output is the same as streaming without discarding.
+	s := NewStringBench(&want, WithSections())
+	s.Stream(true)
+	d := NewStringBench(&got, WithSections())
+	d.DiscardWritten(true)
+	var c, dc bytes.Buffer
+	cb := NewCSVBench(&c, WithSections())
+	cb.Stream(true)
+	dcb := NewCSVBench(&dc, WithSections())
+	dcb.DiscardWritten(true)
+	for i := 0; i < 1000; i++ {
+		v := NewBench(fmt.Sprintf("bench%03d", i))
+		v.Group = fmt.Sprintf("g%d", i/100)
+		v.Ops = int64(i)
+		v.NsOp = 100
+		for _, b := range []Appender{s, d, cb, dcb} {
+			b.Append(v)
+		}
+		if len(d.Benchmarks) > 1 || len(dcb.Benchmarks) > 1 {
+			t.Fatalf("%d: got %d and %d benches kept; want at most 1", i, len(d.Benchmarks), len(dcb.Benchmarks))
+		}
+	}
+	for _, b := range []Outputter{s, d, cb, dcb} {
+		if err := b.Out(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if got.String() != want.String() {
+		t.Errorf("got %q; want %q", got.String(), want.String())
+	}
+	if !strings.Contains(got.String(), "\n\ng1     bench100") {
+		t.Errorf("got %q; want a section per group", got.String())
+	}
+	if dc.String() != c.String() {
+		t.Errorf("got %q; want %q", dc.String(), c.String())
+	}
+	if len(d.Benchmarks) != 1 || d.Benchmarks[0].Name != "bench999" {
+		t.Errorf("got %v; want the last bench", d.Benchmarks)
+	}
+}
+
+// benchmarkLarge renders a set of n benches, as a parameter sweep would.
+func benchmarkLarge(b *testing.B, n int, newBench func(io.Writer) Benchmarker) {
+	b.ReportAllocs()
+	v := NewBench("sweep")
+	v.Group = "params"
+	v.Ops, v.NsOp, v.BytesOp, v.AllocsOp = 1000000, 1234, 64, 2
+	for i := 0; i < b.N; i++ {
+		bm := newBench(ioutil.Discard)
+		for j := 0; j < n; j++ {
+			bm.Append(v)
+		}
+		if err := bm.Out(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLargeTxt(b *testing.B) {
+	benchmarkLarge(b, 100000, func(w io.Writer) Benchmarker { return NewStringBench(w) })
+}
+
+func BenchmarkLargeTxtDiscard(b *testing.B) {
+	benchmarkLarge(b, 100000, func(w io.Writer) Benchmarker {
+		s := NewStringBench(w)
+		s.DiscardWritten(true)
+		return s
+	})
+}
+
+func BenchmarkLargeCSV(b *testing.B) {
+	benchmarkLarge(b, 100000, func(w io.Writer) Benchmarker { return NewCSVBench(w) })
+}
+
+func BenchmarkLargeCSVDiscard(b *testing.B) {
+	benchmarkLarge(b, 100000, func(w io.Writer) Benchmarker {
+		c := NewCSVBench(w)
+		c.DiscardWritten(true)
+		return c
+	})
+}
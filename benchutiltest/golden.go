@@ -0,0 +1,135 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+// Package benchutiltest provides helpers for testing the reports generated
+// with benchutil.  Reports are rendered with fixed, fake, system and run
+// information so their output is the same on every system, and compared with
+// golden files:
+//
+//	func TestReport(t *testing.T) {
+//		b := benchutil.NewBenches()
+//		b.Append(results()...)
+//		benchutiltest.Golden(t, b, "md", "testdata/report.md", benchutil.WithSystemInfo())
+//	}
+//
+// Run the tests with -benchutiltest.update to write the golden files.
+package benchutiltest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mohae/benchutil"
+)
+
+var update = flag.Bool("benchutiltest.update", false, "write the golden files instead of comparing with them")
+
+// Hostname is the Hostname of the rendered reports.
+const Hostname = "benchutiltest"
+
+// Timestamp is the Timestamp of the rendered reports.
+var Timestamp = time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// SysInfo returns the fake system info of the rendered reports.
+func SysInfo() *benchutil.SysInfo {
+	return &benchutil.SysInfo{
+		CPUModel:   "Test CPU",
+		Cores:      2,
+		CPUMHz:     2400,
+		Cache:      "1024 KB",
+		CPUVendor:  "GenuineIntel",
+		MemTotal:   8000000000,
+		OS:         "Test OS 1.0",
+		Kernel:     "4.4.0",
+		GoVersion:  "go1.7",
+		GOOS:       "linux",
+		GOARCH:     "amd64",
+		Compiler:   "gc",
+		NumCPU:     2,
+		GOMAXPROCS: 2,
+		Processors: []benchutil.Processor{
+			{ID: 0, Model: "Test CPU", MHz: 2400, Cache: "1024 KB"},
+			{ID: 1, Model: "Test CPU", MHz: 2400, Cache: "1024 KB"},
+		},
+	}
+}
+
+// Render returns the report for a copy of b, in the named format, e.g. "txt"
+// or "md", with the fake SysInfo, Hostname, and Timestamp.  The report's
+// output settings are the options; b's aren't used.  b isn't changed.
+func Render(b *benchutil.Benches, format string, opts ...benchutil.Option) ([]byte, error) {
+	c := b.Clone()
+	c.SysInfo = SysInfo()
+	c.Hostname = Hostname
+	c.Timestamp = Timestamp
+	var buf bytes.Buffer
+	d, err := benchutil.NewFormat(format, &buf, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = benchutil.SetBenches(d, c)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Out()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Golden renders b, see Render, and compares the report with the golden
+// file at path.  If they differ, the first line that differs is reported as
+// an error.  If the tests are run with -benchutiltest.update, the golden file
+// is written, with any missing directories, instead.
+func Golden(t testing.TB, b *benchutil.Benches, format, path string, opts ...benchutil.Option) {
+	t.Helper()
+	got, err := Render(b, format, opts...)
+	if err != nil {
+		t.Fatalf("render %s: %s", format, err)
+	}
+	if *update {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(path, got, 0644)
+		}
+		if err != nil {
+			t.Fatalf("update golden file: %s", err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file: %s; run with -benchutiltest.update to create it", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s: report doesn't match the golden file: %s", path, diffLine(string(got), string(want)))
+	}
+}
+
+// diffLine describes the first line that differs between got and want.
+func diffLine(got, want string) string {
+	g := strings.Split(got, "\n")
+	w := strings.Split(want, "\n")
+	for i := 0; i < len(g) || i < len(w); i++ {
+		var gl, wl string
+		if i < len(g) {
+			gl = g[i]
+		}
+		if i < len(w) {
+			wl = w[i]
+		}
+		if gl != wl || i >= len(g) || i >= len(w) {
+			return fmt.Sprintf("line %d: got %q; want %q", i+1, gl, wl)
+		}
+	}
+	return "they differ"
+}
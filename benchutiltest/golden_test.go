@@ -0,0 +1,95 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutiltest
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/mohae/benchutil"
+)
+
+func testBenches() *benchutil.Benches {
+	b := benchutil.NewBenches()
+	for i, v := range []string{"a", "b", "c"} {
+		bench := benchutil.NewBench(v)
+		bench.Group = "group"
+		bench.Ops = int64(1000 * (i + 1))
+		bench.NsOp = int64(100 * (i + 1))
+		bench.BytesOp = 16
+		bench.AllocsOp = 1
+		b.Append(bench)
+	}
+	return b
+}
+
+func TestGolden(t *testing.T) {
+	b := testBenches()
+	Golden(t, b, "txt", "testdata/report.txt", benchutil.WithSystemInfo())
+	Golden(t, b, "md", "testdata/report.md", benchutil.WithSystemInfo())
+	if b.SysInfo != nil || b.Hostname == Hostname {
+		t.Error("got b changed; want it unchanged")
+	}
+}
+
+func TestRender(t *testing.T) {
+	b := testBenches()
+	first, err := Render(b, "txt", benchutil.WithSystemInfo())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	second, err := Render(b, "txt", benchutil.WithSystemInfo())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Errorf("got %q; want %q", second, first)
+	}
+	if !bytes.Contains(first, []byte(Hostname)) {
+		t.Errorf("got %q; want the fake hostname", first)
+	}
+	_, err = Render(b, "unknown")
+	if err == nil {
+		t.Error("got no error for an unknown format")
+	}
+}
+
+// recorder records the errors reported by Golden.
+type recorder struct {
+	testing.TB
+	errs []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func TestGoldenMismatch(t *testing.T) {
+	b := testBenches()
+	b.Benchmarks[0].Name = "changed"
+	r := &recorder{TB: t}
+	Golden(r, b, "txt", "testdata/report.txt", benchutil.WithSystemInfo())
+	if len(r.errs) != 1 {
+		t.Fatalf("got %d errors; want 1: %q", len(r.errs), r.errs)
+	}
+	t.Log(r.errs[0])
+}
+
+func TestDiffLine(t *testing.T) {
+	tests := []struct {
+		got, want, diff string
+	}{
+		{"a\nb\n", "a\nc\n", `line 2: got "b"; want "c"`},
+		{"a\n", "a\nb\n", `line 2: got ""; want "b"`},
+	}
+	for _, test := range tests {
+		if d := diffLine(test.got, test.want); d != test.diff {
+			t.Errorf("got %q; want %q", d, test.diff)
+		}
+	}
+}
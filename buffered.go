@@ -0,0 +1,115 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bufio"
+	"io"
+)
+
+// BufferedBench is a Benchmarker whose output is buffered, so the rows are
+// written to the underlying writer, e.g. a file or a socket, a buffer at a
+// time instead of a line, or cell, at a time.  Out flushes the buffer.  Use
+// Close, e.g. deferred, to make sure everything, including the footer, is
+// written even if the program stops before Out is called.
+type BufferedBench struct {
+	Benchmarker
+	forward
+	w      *bufferedWriter
+	done   bool // whether or not Out has been called.
+	closed bool
+}
+
+// NewBufferedBench returns a Benchmarker of the named format, see
+// RegisterFormat, whose output is written to w through a buffer of size
+// bytes, configured by the options.  If size is <= 0, bufio's default size
+// is used.  When streaming with AutoFlush, the buffer, and w, if it has a
+// Flush method, are flushed after every Append.
+func NewBufferedBench(w io.Writer, format string, size int, opts ...Option) (*BufferedBench, error) {
+	var bw *bufio.Writer
+	if size > 0 {
+		bw = bufio.NewWriterSize(w, size)
+	} else {
+		bw = bufio.NewWriter(w)
+	}
+	fw := &bufferedWriter{Writer: bw, w: w}
+	b, err := NewFormat(format, fw, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &BufferedBench{Benchmarker: b, forward: forward{b}, w: fw}, nil
+}
+
+// Out writes the output and flushes the buffer.  What was written before an
+// error is flushed too.
+func (b *BufferedBench) Out() error {
+	b.done = true
+	err := b.Benchmarker.Out()
+	ferr := b.Flush()
+	if err != nil {
+		return err
+	}
+	return ferr
+}
+
+// Flush writes anything that's buffered to the underlying writer, and
+// flushes it if it has a Flush method.
+func (b *BufferedBench) Flush() error {
+	return b.w.Flush()
+}
+
+// Close writes the output, if Out hasn't been called, flushes the buffer,
+// and closes the underlying writer if it's an io.Closer; nothing can be
+// written after Close.  Closing more than once does nothing.
+func (b *BufferedBench) Close() error {
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	var err error
+	if b.done {
+		err = b.Flush()
+	} else {
+		err = b.Out()
+	}
+	if c, ok := b.w.w.(io.Closer); ok {
+		cerr := c.Close()
+		if err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// benches returns the wrapped Benchmarker's Benches, so SetBenches works
+// with a BufferedBench.
+func (b *BufferedBench) benches() *Benches {
+	d, ok := b.Benchmarker.(interface{ benches() *Benches })
+	if !ok {
+		return nil
+	}
+	return d.benches()
+}
+
+// bufferedWriter is a bufio.Writer whose Flush also flushes the writer it
+// writes to, if it can be flushed, e.g. an http.ResponseWriter.
+type bufferedWriter struct {
+	*bufio.Writer
+	w io.Writer // the writer the buffer writes to.
+}
+
+func (f *bufferedWriter) Flush() error {
+	err := f.Writer.Flush()
+	if err != nil {
+		return err
+	}
+	switch w := f.w.(type) {
+	case interface{ Flush() error }:
+		return w.Flush()
+	case interface{ Flush() }:
+		w.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// flushCloser counts the writes, and flushes, to it; it can be closed.
+type flushCloser struct {
+	bytes.Buffer
+	writes  int
+	flushes int
+	closed  bool
+}
+
+func (w *flushCloser) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func (w *flushCloser) Flush() { w.flushes++ }
+
+func (w *flushCloser) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestBufferedBench(t *testing.T) {
+	for _, format := range []string{"txt", "csv", "md"} {
+		src := &Benches{Hostname: "host", Timestamp: time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC), Benchmarks: testBenches()}
+		var want flushCloser
+		s, err := NewFormat(format, &want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		SetBenches(s, src)
+		if err := s.Out(); err != nil {
+			t.Fatalf("%s: unexpected error: %s", format, err)
+		}
+
+		var got flushCloser
+		b, err := NewBufferedBench(&got, format, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := SetBenches(b, src); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Out(); err != nil {
+			t.Fatalf("%s: unexpected error: %s", format, err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("%s: got %q; want %q", format, got.String(), want.String())
+		}
+		// the CSV is buffered by its csv.Writer; the others are written a
+		// line, or less, at a time.
+		if got.writes != 1 || format != "csv" && want.writes < 4 {
+			t.Errorf("%s: got %d writes, and %d unbuffered; want 1 and more", format, got.writes, want.writes)
+		}
+		if got.flushes != 1 {
+			t.Errorf("%s: got %d flushes; want 1", format, got.flushes)
+		}
+		// Out has written everything; Close only closes.
+		if err := b.Close(); err != nil {
+			t.Fatalf("%s: unexpected error: %s", format, err)
+		}
+		if !got.closed || got.String() != want.String() {
+			t.Errorf("%s: got closed %t with %q; want the output unchanged, closed", format, got.closed, got.String())
+		}
+	}
+	if _, err := NewBufferedBench(&flushCloser{}, "nope", 0); err == nil {
+		t.Error("got no error for an unknown format")
+	}
+}
+
+func TestBufferedBenchSize(t *testing.T) {
+	var w flushCloser
+	b, err := NewBufferedBench(&w, "txt", 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Append(testBenches()...)
+	if err := b.Out(); err != nil {
+		t.Fatal(err)
+	}
+	if w.writes < 2 {
+		t.Errorf("got %d writes; want the output written a 64 byte buffer at a time", w.writes)
+	}
+}
+
+// Close writes the output, including the footer, if Out wasn't called.
+func TestBufferedBenchClose(t *testing.T) {
+	var w flushCloser
+	b, err := NewBufferedBench(&w, "txt", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.SetFooter("the end")
+	b.Benchmarker.(Streamer).Stream(true)
+	b.Append(testBenches()...)
+	// nothing reaches the underlying writer until the buffer is flushed.
+	if w.writes != 0 {
+		t.Errorf("got %d writes; want 0", w.writes)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(w.String(), "\nthe end\n") || !strings.Contains(w.String(), "group  c") {
+		t.Errorf("got %q; want the rows and the footer", w.String())
+	}
+	if !w.closed {
+		t.Error("got the writer open; want it closed")
+	}
+	n := w.Len()
+	if err := b.Close(); err != nil {
+		t.Errorf("got %s closing twice; want nil", err)
+	}
+	if w.Len() != n {
+		t.Error("got output written by the second Close")
+	}
+}
+
+// With AutoFlush, the rows reach the underlying writer as they're appended.
+func TestBufferedBenchAutoFlush(t *testing.T) {
+	var w flushCloser
+	b, err := NewBufferedBench(&w, "csv", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Benchmarker.(*CSVBench).AutoFlush(true)
+	b.Append(testBenches()[0])
+	if !strings.Contains(w.String(), "group,a,") || w.flushes != 1 {
+		t.Errorf("got %q and %d flushes; want the first row, flushed", w.String(), w.flushes)
+	}
+}
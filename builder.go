@@ -0,0 +1,81 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "testing"
+
+// BenchBuilder builds a Bench, e.g.
+//
+//	b := NewBenchBuilder("decode").Group("json").Desc("1KB payload").FromResult(br)
+//
+// Bench's fields are still usable directly; the builder saves populating
+// them one at a time.
+type BenchBuilder struct {
+	b Bench
+}
+
+// NewBenchBuilder returns a builder for a Bench with the name and 1
+// iteration; see NewBench.
+func NewBenchBuilder(name string) *BenchBuilder {
+	return &BenchBuilder{b: NewBench(name)}
+}
+
+// Group sets the bench's Group.
+func (bb *BenchBuilder) Group(s string) *BenchBuilder {
+	bb.b.Group = s
+	return bb
+}
+
+// SubGroup sets the bench's SubGroup.
+func (bb *BenchBuilder) SubGroup(s string) *BenchBuilder {
+	bb.b.SubGroup = s
+	return bb
+}
+
+// Desc sets the bench's Desc.
+func (bb *BenchBuilder) Desc(s string) *BenchBuilder {
+	bb.b.Desc = s
+	return bb
+}
+
+// Note sets the bench's Note.
+func (bb *BenchBuilder) Note(s string) *BenchBuilder {
+	bb.b.Note = s
+	return bb
+}
+
+// Iterations sets the bench's Iterations.
+func (bb *BenchBuilder) Iterations(n int) *BenchBuilder {
+	bb.b.Iterations = n
+	return bb
+}
+
+// Err sets the bench's Err, marking it as failed.
+func (bb *BenchBuilder) Err(s string) *BenchBuilder {
+	bb.b.Err = s
+	return bb
+}
+
+// Result sets the bench's Result.
+func (bb *BenchBuilder) Result(r Result) *BenchBuilder {
+	bb.b.Result = r
+	return bb
+}
+
+// Bench returns the Bench.
+func (bb *BenchBuilder) Bench() Bench {
+	return bb.b
+}
+
+// FromResult returns the Bench with its Result from the
+// testing.BenchmarkResult; see ResultFromBenchmarkResult.  A result without
+// any ops, e.g. from a benchmark that failed, is a failed bench.
+func (bb *BenchBuilder) FromResult(br testing.BenchmarkResult) Bench {
+	bb.b.Result = ResultFromBenchmarkResult(br)
+	if br.N == 0 && bb.b.Err == "" {
+		bb.b.Err = "benchmark failed"
+	}
+	return bb.b
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBenchBuilder(t *testing.T) {
+	br := testing.BenchmarkResult{N: 100, T: time.Millisecond, MemBytes: 1600, MemAllocs: 100}
+	b := NewBenchBuilder("decode").Group("json").SubGroup("small").Desc("1KB payload").Note("cold cache").FromResult(br)
+	want := Bench{Group: "json", SubGroup: "small", Name: "decode", Desc: "1KB payload", Note: "cold cache", Iterations: 1, Result: Result{Ops: 100, NsOp: 10000, BytesOp: 16, AllocsOp: 1}}
+	if !reflect.DeepEqual(b, want) {
+		t.Errorf("got %+v; want %+v", b, want)
+	}
+	b = NewBenchBuilder("encode").FromResult(testing.BenchmarkResult{})
+	if !b.Failed() {
+		t.Errorf("got %+v; want a failed bench", b)
+	}
+	b = NewBenchBuilder("encode").Iterations(3).Result(Result{Ops: 1}).Err("boom").Bench()
+	if b.Iterations != 3 || b.Ops != 1 || b.Err != "boom" {
+		t.Errorf("got %+v", b)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// ResultCache stores benchmark results on disk, keyed by the build of the
+// running binary, the benchmark's Group/SubGroup/Name, and the settings it
+// was measured with, e.g. its benchtime.  A Runner with a ResultCache reuses
+// the stored results of benchmarks that haven't changed, the binary and the
+// settings are the same, instead of re-running them.
+type ResultCache struct {
+	dir     string
+	buildID string
+}
+
+// NewResultCache returns a ResultCache that stores results in dir, which is
+// created if it doesn't exist.  The build ID is the SHA-256 of the running
+// executable.
+func NewResultCache(dir string) (*ResultCache, error) {
+	id, err := executableHash()
+	if err != nil {
+		return nil, err
+	}
+	return NewResultCacheWithID(dir, id)
+}
+
+// NewResultCacheWithID returns a ResultCache that stores results in dir,
+// which is created if it doesn't exist, using id to identify the build.
+func NewResultCacheWithID(dir, id string) (*ResultCache, error) {
+	err := os.MkdirAll(filepath.Join(dir, id), 0755)
+	if err != nil {
+		return nil, err
+	}
+	return &ResultCache{dir: dir, buildID: id}, nil
+}
+
+// BuildID returns the ID of the build the cache's results are for.
+func (c *ResultCache) BuildID() string {
+	return c.buildID
+}
+
+// path returns the path of b's cache file for the settings, key.
+func (c *ResultCache) path(b Bench, key string) string {
+	name := fileName(benchID(b))
+	if key != "" {
+		h := sha256.Sum256([]byte(key))
+		name += "-" + hex.EncodeToString(h[:8])
+	}
+	return filepath.Join(c.dir, c.buildID, name+".json")
+}
+
+// Get returns the cached Bench for b that was measured with the settings,
+// key.  If there isn't one, false is returned.
+func (c *ResultCache) Get(b Bench, key string) (Bench, bool) {
+	data, err := ioutil.ReadFile(c.path(b, key))
+	if err != nil {
+		return b, false
+	}
+	var cached Bench
+	err = json.Unmarshal(data, &cached)
+	if err != nil || benchID(cached) != benchID(b) {
+		return b, false
+	}
+	return cached, true
+}
+
+// Put stores b, measured with the settings, key, in the cache.
+func (c *ResultCache) Put(b Bench, key string) error {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path(b, key), data, 0644)
+}
+
+// executableHash returns the hex encoded SHA-256 of the running executable.
+func executableHash() (string, error) {
+	p, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
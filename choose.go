@@ -0,0 +1,126 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidWeights is returned when a set of weights can't be chosen from:
+// it's empty, a weight is negative, NaN, or infinite, or they sum to 0.
+var ErrInvalidWeights = errors.New("weights must be non-negative, finite, and sum to more than 0")
+
+// sumWeights returns the sum of the weights, or an error if they are
+// invalid.
+func sumWeights(weights []float64) (float64, error) {
+	var sum float64
+	for _, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return 0, ErrInvalidWeights
+		}
+		sum += w
+	}
+	if !(sum > 0) || math.IsInf(sum, 0) {
+		return 0, ErrInvalidWeights
+	}
+	return sum, nil
+}
+
+// Choose returns a pseudo-random index into weights, with each index chosen
+// with a probability proportional to its weight, e.g. weights of 90 and 10
+// for a 90% read / 10% write mix.  It is O(n); use a Chooser when choosing
+// repeatedly from the same weights.  It panics if the weights are invalid.
+func Choose(weights []float64) int {
+	return defaultGen.Choose(weights)
+}
+
+// Choose returns a pseudo-random index into weights, with each index chosen
+// with a probability proportional to its weight.  See the Choose function.
+func (g *Gen) Choose(weights []float64) int {
+	sum, err := sumWeights(weights)
+	if err != nil {
+		panic("Choose: " + err.Error())
+	}
+	r := g.RandFloat64() * sum
+	last := 0
+	for i, w := range weights {
+		if w == 0 {
+			continue
+		}
+		if r < w {
+			return i
+		}
+		r -= w
+		last = i
+	}
+	// rounding can leave r just past the end; use the last non-zero weight.
+	return last
+}
+
+// Chooser chooses indexes with probabilities proportional to a set of
+// weights in O(1), using Vose's alias method.  A Chooser is not safe for
+// concurrent use.
+type Chooser struct {
+	g     *Gen
+	prob  []float64
+	alias []int
+}
+
+// NewChooser returns a Chooser, using the package's Gen, for the weights.
+func NewChooser(weights []float64) (*Chooser, error) {
+	return defaultGen.NewChooser(weights)
+}
+
+// NewChooser returns a Chooser, using g, for the weights.  If the weights
+// are invalid, ErrInvalidWeights is returned.
+func (g *Gen) NewChooser(weights []float64) (*Chooser, error) {
+	sum, err := sumWeights(weights)
+	if err != nil {
+		return nil, err
+	}
+	n := len(weights)
+	c := &Chooser{g: g, prob: make([]float64, n), alias: make([]int, n)}
+	// scale the weights so the average is 1, then pair each small, < 1,
+	// entry with a large one that donates the rest of its column.
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, w := range weights {
+		scaled[i] = w * float64(n) / sum
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+		c.prob[s] = scaled[s]
+		c.alias[s] = l
+		scaled[l] -= 1 - scaled[s]
+		if scaled[l] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+	// what's left is 1, give or take rounding.
+	for _, i := range large {
+		c.prob[i] = 1
+	}
+	for _, i := range small {
+		c.prob[i] = 1
+	}
+	return c, nil
+}
+
+// Next returns the next pseudo-randomly chosen index.
+func (c *Chooser) Next() int {
+	i := c.g.RandIntn(len(c.prob))
+	if c.g.RandFloat64() < c.prob[i] {
+		return i
+	}
+	return c.alias[i]
+}
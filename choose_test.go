@@ -0,0 +1,41 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"math"
+	"testing"
+)
+
+func TestChoose(t *testing.T) {
+	weights := []float64{90, 0, 7, 3}
+	c, err := NewGen(6).NewChooser(weights)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	g := NewGen(6)
+	const n = 100000
+	var choose, chooser [4]int
+	for i := 0; i < n; i++ {
+		choose[g.Choose(weights)]++
+		chooser[c.Next()]++
+	}
+	for i, w := range weights {
+		want := w / 100 * n
+		for _, got := range []int{choose[i], chooser[i]} {
+			if math.Abs(float64(got)-want) > 0.05*want+50 {
+				t.Errorf("%d: got %d; want about %.0f", i, got, want)
+			}
+		}
+	}
+	if choose[1] != 0 || chooser[1] != 0 {
+		t.Errorf("got %d and %d choices of a 0 weight; want 0", choose[1], chooser[1])
+	}
+	for _, w := range [][]float64{nil, {0, 0}, {1, -1}, {math.NaN()}} {
+		if _, err := NewChooser(w); err != ErrInvalidWeights {
+			t.Errorf("%v: got %v; want ErrInvalidWeights", w, err)
+		}
+	}
+}
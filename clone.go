@@ -0,0 +1,101 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+// Clone returns a deep copy of b: its benchmarks, and their samples,
+// warnings, system info, run info, column headers, and output settings.
+// Changes to the copy, e.g. filtering or sorting its Benchmarks or changing
+// its columns, don't affect b, so one set of results can be output in
+// different ways.  The row formatter, if one is set, is shared.
+func (b *Benches) Clone() *Benches {
+	c := *b
+	if b.Benchmarks != nil {
+		c.Benchmarks = append([]Bench(nil), b.Benchmarks...)
+		for i := range c.Benchmarks {
+			if c.Benchmarks[i].Samples != nil {
+				c.Benchmarks[i].Samples = append([]Result(nil), c.Benchmarks[i].Samples...)
+			}
+		}
+	}
+	if b.Warnings != nil {
+		c.Warnings = append([]string(nil), b.Warnings...)
+	}
+	if b.Meta != nil {
+		c.Meta = append([][2]string(nil), b.Meta...)
+	}
+	c.SysInfo = b.SysInfo.clone()
+	if b.Git != nil {
+		g := *b.Git
+		c.Git = &g
+	}
+	if b.locale != nil {
+		l := *b.locale
+		c.locale = &l
+	}
+	c.Env = cloneStrings(b.Env)
+	c.hidden = cloneColumns(b.hidden)
+	c.shown = cloneColumns(b.shown)
+	if b.precision != nil {
+		c.precision = make(map[Column]int, len(b.precision))
+		for k, v := range b.precision {
+			c.precision[k] = v
+		}
+	}
+	return &c
+}
+
+// clone returns a deep copy of s; nil is returned if s is nil.
+func (s *SysInfo) clone() *SysInfo {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	if s.CPUFeatures != nil {
+		c.CPUFeatures = append([]string(nil), s.CPUFeatures...)
+	}
+	if s.Processors != nil {
+		c.Processors = append([]Processor(nil), s.Processors...)
+	}
+	if s.NUMANodes != nil {
+		c.NUMANodes = append([]NUMANode(nil), s.NUMANodes...)
+	}
+	if s.Disks != nil {
+		c.Disks = append([]Disk(nil), s.Disks...)
+	}
+	if s.GPUs != nil {
+		c.GPUs = append([]GPU(nil), s.GPUs...)
+	}
+	if s.Thermal != nil {
+		t := *s.Thermal
+		c.Thermal = &t
+	}
+	c.GoEnv = cloneStrings(s.GoEnv)
+	return &c
+}
+
+// cloneStrings returns a copy of m; nil is returned if m is nil.
+func cloneStrings(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// cloneColumns returns a copy of the column set m; nil is returned if m is
+// nil.
+func cloneColumns(m map[string]bool) map[string]bool {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]bool, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	b := NewBenches()
+	b.Append(testBenches()...)
+	b.Benchmarks[0].AddSample(Result{Ops: 10, NsOp: 20})
+	b.AddWarning("on battery")
+	b.SetMeta("dataset", "small")
+	b.Env = map[string]string{"GOGC": "100"}
+	b.Git = &GitInfo{Commit: "3f7a2c1"}
+	b.SysInfo = &SysInfo{
+		CPUModel:    "cpu",
+		CPUFeatures: []string{"avx2"},
+		Processors:  []Processor{{ID: 0, Model: "cpu"}},
+		GoEnv:       map[string]string{"GOAMD64": "v3"},
+		Thermal:     &Thermal{StartTemp: 40},
+	}
+	b.HideColumns("group")
+	b.SetPrecision(NsOpColumn, 2)
+	b.SetTitle("title")
+	if err := b.SetLocale("de"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	b.setLength()
+
+	c := b.Clone()
+	if !reflect.DeepEqual(c, b) {
+		t.Fatalf("got %+v; want %+v", c, b)
+	}
+
+	// change everything in the copy; b must not change.
+	c.Benchmarks[0].Name = "z"
+	c.Benchmarks[0].Samples[0].NsOp = 40
+	c.Benchmarks = c.Benchmarks[1:]
+	c.Warnings[0] = "changed"
+	c.Meta[0][1] = "large"
+	c.Env["GOGC"] = "off"
+	c.Git.Commit = "changed"
+	c.SysInfo.CPUFeatures[0] = "sve"
+	c.SysInfo.Processors[0].Model = "changed"
+	c.SysInfo.GoEnv["GOAMD64"] = "v1"
+	c.SysInfo.Thermal.StartTemp = 90
+	c.HideColumns("note")
+	c.SetPrecision(NsOpColumn, 4)
+	c.locale.Decimal = "?"
+	c.header.Name = "Bench"
+	c.SetTitle("other")
+	c.Name = "copy"
+
+	if len(b.Benchmarks) != 3 || b.Benchmarks[0].Name != "a" {
+		t.Errorf("got %v; want the original benchmarks", b.Benchmarks)
+	}
+	if b.Benchmarks[0].Samples[0].NsOp != 20 {
+		t.Errorf("got sample ns/op %d; want 20", b.Benchmarks[0].Samples[0].NsOp)
+	}
+	if b.Warnings[0] != "on battery" {
+		t.Errorf("got warning %q; want %q", b.Warnings[0], "on battery")
+	}
+	if b.Meta[0][1] != "small" {
+		t.Errorf("got meta %q; want %q", b.Meta[0][1], "small")
+	}
+	if b.Env["GOGC"] != "100" {
+		t.Errorf("got GOGC %q; want %q", b.Env["GOGC"], "100")
+	}
+	if b.Git.Commit != "3f7a2c1" {
+		t.Errorf("got commit %q; want %q", b.Git.Commit, "3f7a2c1")
+	}
+	if b.SysInfo.CPUFeatures[0] != "avx2" || b.SysInfo.Processors[0].Model != "cpu" || b.SysInfo.GoEnv["GOAMD64"] != "v3" || b.SysInfo.Thermal.StartTemp != 40 {
+		t.Errorf("got %+v; want the original system info", b.SysInfo)
+	}
+	if b.hidden["note"] {
+		t.Error("got note hidden; want it shown")
+	}
+	if b.precision[NsOpColumn] != 2 {
+		t.Errorf("got precision %d; want 2", b.precision[NsOpColumn])
+	}
+	if b.locale.Decimal != "," {
+		t.Errorf("got decimal %q; want %q", b.locale.Decimal, ",")
+	}
+	if b.header.Name != "Name" {
+		t.Errorf("got name header %q; want %q", b.header.Name, "Name")
+	}
+	if b.title != "title" || b.Name != "" {
+		t.Errorf("got title %q and name %q; want %q and %q", b.title, b.Name, "title", "")
+	}
+}
+
+func TestCloneEmpty(t *testing.T) {
+	b := NewBenches()
+	c := b.Clone()
+	if !reflect.DeepEqual(c, b) {
+		t.Errorf("got %+v; want %+v", c, b)
+	}
+	c.Append(NewBench("a"))
+	if len(b.Benchmarks) != 0 {
+		t.Errorf("got %d benchmarks; want 0", len(b.Benchmarks))
+	}
+}
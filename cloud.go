@@ -0,0 +1,130 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// The instance metadata endpoints; they are vars so they can be changed for
+// testing.
+var (
+	awsMetadataURL   = "http://169.254.169.254"
+	gcpMetadataURL   = "http://metadata.google.internal"
+	azureMetadataURL = "http://169.254.169.254"
+)
+
+// metadataTimeout is how long a metadata request can take.  The endpoints are
+// link-local so anything more than this means they aren't there.
+var metadataTimeout = 500 * time.Millisecond
+
+// virtInfo holds the virtualization and cloud information.
+type virtInfo struct {
+	Virtualization string // the hypervisor, e.g. kvm, xen; empty on bare metal or if unknown.
+	Cloud          string // the cloud provider: aws, gcp, or azure; empty if not in a known cloud.
+	InstanceType   string // the cloud instance type, e.g. c5.xlarge; if it could be determined.
+}
+
+// getVirtInfo detects, on a best effort basis, the hypervisor and cloud
+// instance type using the DMI information.  The cloud provider's metadata
+// endpoint is only queried when the DMI information identifies the provider
+// and doesn't include the instance type.
+func getVirtInfo() virtInfo {
+	var v virtInfo
+	vendor, _ := readSysfsValue("class/dmi/id/sys_vendor")
+	product, _ := readSysfsValue("class/dmi/id/product_name")
+	switch {
+	case vendor == "Amazon EC2":
+		v.Cloud = "aws"
+		v.Virtualization = "nitro"
+		// Nitro instances report the instance type as the product name.
+		if strings.Contains(product, ".") {
+			v.InstanceType = product
+		}
+	case vendor == "Google" || product == "Google Compute Engine":
+		v.Cloud = "gcp"
+		v.Virtualization = "kvm"
+	case vendor == "Microsoft Corporation" && product == "Virtual Machine":
+		v.Cloud = "azure"
+		v.Virtualization = "hyper-v"
+	case vendor == "QEMU" || strings.HasPrefix(product, "KVM"):
+		v.Virtualization = "kvm"
+	case strings.HasPrefix(vendor, "VMware"):
+		v.Virtualization = "vmware"
+	case vendor == "innotek GmbH":
+		v.Virtualization = "virtualbox"
+	case vendor == "Xen":
+		v.Virtualization = "xen"
+	}
+	if v.Virtualization == "" {
+		// xen guests may not have a DMI vendor.
+		if t, ok := readSysfsValue("hypervisor/type"); ok {
+			v.Virtualization = t
+		}
+	}
+	if v.Cloud == "" && v.Virtualization == "xen" {
+		// older EC2 instances are xen guests with an amazon BIOS.
+		bios, _ := readSysfsValue("class/dmi/id/bios_version")
+		if strings.Contains(bios, "amazon") {
+			v.Cloud = "aws"
+		}
+	}
+	if v.Cloud != "" && v.InstanceType == "" {
+		v.InstanceType = metadataInstanceType(v.Cloud)
+	}
+	return v
+}
+
+// metadataInstanceType queries the cloud provider's metadata endpoint for the
+// instance type.  If it can't be determined, an empty string is returned.
+func metadataInstanceType(cloud string) string {
+	client := &http.Client{Timeout: metadataTimeout}
+	switch cloud {
+	case "aws":
+		// IMDSv2 requires a session token.
+		req, _ := http.NewRequest("PUT", awsMetadataURL+"/latest/api/token", nil)
+		req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+		token, ok := metadataGet(client, req)
+		req, _ = http.NewRequest("GET", awsMetadataURL+"/latest/meta-data/instance-type", nil)
+		if ok {
+			req.Header.Set("X-aws-ec2-metadata-token", token)
+		}
+		t, _ := metadataGet(client, req)
+		return t
+	case "gcp":
+		req, _ := http.NewRequest("GET", gcpMetadataURL+"/computeMetadata/v1/instance/machine-type", nil)
+		req.Header.Set("Metadata-Flavor", "Google")
+		t, _ := metadataGet(client, req)
+		// the machine type is a path: projects/<n>/machineTypes/<type>
+		return t[strings.LastIndex(t, "/")+1:]
+	case "azure":
+		req, _ := http.NewRequest("GET", azureMetadataURL+"/metadata/instance/compute/vmSize?api-version=2021-02-01&format=text", nil)
+		req.Header.Set("Metadata", "true")
+		t, _ := metadataGet(client, req)
+		return t
+	}
+	return ""
+}
+
+// metadataGet makes the request and returns the trimmed body.  If the request
+// fails, or the status isn't 200, false is returned.
+func metadataGet(client *http.Client, req *http.Request) (string, bool) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", false
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(b)), true
+}
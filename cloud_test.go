@@ -0,0 +1,52 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetVirtInfo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "missing header", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("projects/123/machineTypes/n2-standard-4"))
+	}))
+	defer srv.Close()
+	orig := gcpMetadataURL
+	gcpMetadataURL = srv.URL
+	defer func() { gcpMetadataURL = orig }()
+
+	tests := []struct {
+		files map[string]string
+		want  virtInfo
+	}{
+		{nil, virtInfo{}},
+		{
+			map[string]string{"class/dmi/id/sys_vendor": "Amazon EC2\n", "class/dmi/id/product_name": "c5.xlarge\n"},
+			virtInfo{Virtualization: "nitro", Cloud: "aws", InstanceType: "c5.xlarge"},
+		},
+		{
+			map[string]string{"class/dmi/id/sys_vendor": "Google\n", "class/dmi/id/product_name": "Google Compute Engine\n"},
+			virtInfo{Virtualization: "kvm", Cloud: "gcp", InstanceType: "n2-standard-4"},
+		},
+		{
+			map[string]string{"class/dmi/id/sys_vendor": "QEMU\n", "class/dmi/id/product_name": "Standard PC\n"},
+			virtInfo{Virtualization: "kvm"},
+		},
+	}
+	for i, test := range tests {
+		restore := fakeSysfs(t, test.files)
+		v := getVirtInfo()
+		restore()
+		if v != test.want {
+			t.Errorf("%d: got %+v; want %+v", i, v, test.want)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+// Command benchutil converts, compares, and checks benchmark results and
+// reports system information, so scripts and CI steps can use benchutil
+// without writing a harness.
+//
+// Usage:
+//
+//	benchutil convert [-format md] [-options file] [-o file] [file]
+//	benchutil compare [-metric ns/op] old new
+//	benchutil check -baseline file [-threshold 0.1] [-metric ns/op] [file]
+//	benchutil sysinfo [-detailed] [-gpu] [-disk] [-json]
+//
+// The results are read from the file, or stdin if it's omitted or "-".
+// They can be Go benchmark output, e.g. from go test -bench, or Benches as
+// JSON, e.g. from convert -format json.
+//
+// convert's formats are json and the formats registered with
+// benchutil.RegisterFormat: txt, csv, md, html, and any added by packages
+// linked into the binary.  The output settings of the registered formats can
+// be set with -options: a benchutil.Options file, as JSON, YAML, or TOML.
+//
+// check exits with a status of 1 if any of the benchmarks regressed by more
+// than the threshold compared to the baseline.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mohae/benchutil"
+	"github.com/mohae/benchutil/history"
+	"github.com/mohae/benchutil/perf"
+)
+
+const usage = `usage: benchutil <command> [flags] [args]
+
+commands:
+  convert  convert results to another format
+  compare  compare two sets of results
+  check    check results for regressions against a baseline
+  sysinfo  print the system information
+
+Run 'benchutil <command> -h' for the command's flags.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command in args and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	cmds := map[string]func([]string, io.Reader, io.Writer, io.Writer) (int, error){
+		"convert": convert,
+		"compare": compare,
+		"check":   check,
+		"sysinfo": sysinfo,
+	}
+	cmd, ok := cmds[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "benchutil: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+	status, err := cmd(args[1:], stdin, stdout, stderr)
+	if err == flag.ErrHelp {
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "benchutil %s: %s\n", args[0], err)
+		if status == 0 {
+			status = 1
+		}
+	}
+	return status
+}
+
+// newFlagSet returns a flag set for the command that returns errors
+// instead of exiting; its usage and errors are written to stderr.
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+func convert(args []string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	fs := newFlagSet("convert", stderr)
+	format := fs.String("format", "md", fmt.Sprintf("the output format: json or a registered format (%s)", strings.Join(benchutil.Formats(), ", ")))
+	optsFile := fs.String("options", "", "the output settings file: JSON, YAML, or TOML benchutil.Options")
+	out := fs.String("o", "", "the output file; default is stdout")
+	err := fs.Parse(args)
+	if err != nil {
+		return 2, err
+	}
+	if *format != "json" && !benchutil.HasFormat(*format) {
+		return 2, fmt.Errorf("unknown format %q", *format)
+	}
+	var opts *benchutil.Options
+	if *optsFile != "" {
+		opts, err = benchutil.LoadOptions(*optsFile)
+		if err != nil {
+			return 2, err
+		}
+	}
+	b, err := readBenches(fs.Arg(0), stdin)
+	if err != nil {
+		return 1, err
+	}
+	if *out == "" {
+		return 0, write(stdout, b, *format, opts)
+	}
+	w, err := os.Create(*out)
+	if err != nil {
+		return 1, err
+	}
+	err = write(w, b, *format, opts)
+	if err != nil {
+		w.Close()
+		return 1, err
+	}
+	return 0, w.Close()
+}
+
+// write writes the benches to w in the format; if opts isn't nil, they are
+// applied to the Benchmarker.
+func write(w io.Writer, b *benchutil.Benches, format string, opts *benchutil.Options) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(b)
+	}
+	// the output is buffered so a file isn't written a line at a time.
+	bm, err := benchutil.NewBufferedBench(w, format, 0)
+	if err != nil {
+		return err
+	}
+	if opts != nil {
+		err = opts.Apply(bm)
+		if err != nil {
+			return err
+		}
+	}
+	err = benchutil.SetBenches(bm, b)
+	if err != nil {
+		return err
+	}
+	return bm.Out()
+}
+
+var metrics = map[string]history.Metric{
+	"ns/op":     history.NsOp,
+	"B/op":      history.BytesOp,
+	"bytes/op":  history.BytesOp,
+	"allocs/op": history.AllocsOp,
+	"ops":       history.Ops,
+}
+
+func compare(args []string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	fs := newFlagSet("compare", stderr)
+	metric := fs.String("metric", "ns/op", "the metric to compare: ns/op, B/op, allocs/op, or ops")
+	err := fs.Parse(args)
+	if err != nil {
+		return 2, err
+	}
+	m, ok := metrics[*metric]
+	if !ok {
+		return 2, fmt.Errorf("unknown metric %q", *metric)
+	}
+	if fs.NArg() != 2 {
+		return 2, fmt.Errorf("want the old and new results; got %d args", fs.NArg())
+	}
+	old, err := readBenches(fs.Arg(0), stdin)
+	if err != nil {
+		return 1, err
+	}
+	cur, err := readBenches(fs.Arg(1), stdin)
+	if err != nil {
+		return 1, err
+	}
+	vals := map[string]float64{}
+	for _, v := range old.Benchmarks {
+		vals[v.ID()] = m.Value(v)
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "benchmark\told %s\tnew %s\tdelta\t\n", m, m)
+	for _, v := range cur.Benchmarks {
+		o, ok := vals[v.ID()]
+		if !ok {
+			continue
+		}
+		n := m.Value(v)
+		delta := "~"
+		if o != 0 {
+			delta = fmt.Sprintf("%+.2f%%", (n-o)/o*100)
+		}
+		fmt.Fprintf(tw, "%s\t%g\t%g\t%s\t\n", v.ID(), o, n, delta)
+	}
+	return 0, tw.Flush()
+}
+
+func check(args []string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	fs := newFlagSet("check", stderr)
+	baseline := fs.String("baseline", "", "the baseline results; required")
+	threshold := fs.Float64("threshold", 0.1, "the relative change that's a regression, e.g. 0.1 is 10%")
+	metric := fs.String("metric", "ns/op", "the metric to check: ns/op, B/op, allocs/op, or ops")
+	err := fs.Parse(args)
+	if err != nil {
+		return 2, err
+	}
+	if *baseline == "" {
+		return 2, fmt.Errorf("-baseline is required")
+	}
+	m, ok := metrics[*metric]
+	if !ok {
+		return 2, fmt.Errorf("unknown metric %q", *metric)
+	}
+	base, err := readBenches(*baseline, stdin)
+	if err != nil {
+		return 1, err
+	}
+	cur, err := readBenches(fs.Arg(0), stdin)
+	if err != nil {
+		return 1, err
+	}
+	s := history.NewMemStore()
+	_, err = s.SaveRun(base)
+	if err != nil {
+		return 1, err
+	}
+	regs, err := history.CheckRegressions(s, cur, 0, *threshold, m)
+	if err != nil {
+		return 1, err
+	}
+	for _, r := range regs {
+		fmt.Fprintf(stdout, "regression: %s\n", r)
+	}
+	if len(regs) > 0 {
+		return 1, fmt.Errorf("%d of %d benchmarks regressed", len(regs), len(cur.Benchmarks))
+	}
+	fmt.Fprintf(stdout, "ok: %d benchmarks checked\n", len(cur.Benchmarks))
+	return 0, nil
+}
+
+func sysinfo(args []string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	fs := newFlagSet("sysinfo", stderr)
+	detailed := fs.Bool("detailed", false, "include every processor")
+	gpu := fs.Bool("gpu", false, "include the GPUs")
+	disk := fs.Bool("disk", false, "include the block devices")
+	asJSON := fs.Bool("json", false, "output JSON")
+	err := fs.Parse(args)
+	if err != nil {
+		return 2, err
+	}
+	b := benchutil.NewBenches()
+	b.IncludeGPUInfo(*gpu)
+	b.IncludeDiskInfo(*disk)
+	inf, err := b.Info()
+	if err != nil {
+		return 1, err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return 0, enc.Encode(inf)
+	}
+	if *detailed {
+		_, err = fmt.Fprintln(stdout, inf.DetailedString())
+	} else {
+		_, err = fmt.Fprintln(stdout, inf.String())
+	}
+	return 0, err
+}
+
+// readBenches reads the results in the file; if path is empty or "-", stdin
+// is read.  JSON is decoded as Benches, anything else is parsed as Go
+// benchmark output.
+func readBenches(path string, stdin io.Reader) (*benchutil.Benches, error) {
+	r := stdin
+	if path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	br := bufio.NewReader(r)
+	peek, _ := br.Peek(512)
+	if bytes.HasPrefix(bytes.TrimSpace(peek), []byte("{")) {
+		b := benchutil.NewBenches()
+		err := json.NewDecoder(br).Decode(b)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+		return b, nil
+	}
+	return perf.ParseGoBench(br)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mohae/benchutil"
+)
+
+const oldBench = `goos: linux
+BenchmarkJSON/decode-8   	1000000	      1000 ns/op	     240 B/op	       3 allocs/op
+BenchmarkJSON/encode-8   	1000000	      2000 ns/op	     240 B/op	       3 allocs/op
+`
+
+const newBench = `goos: linux
+BenchmarkJSON/decode-8   	1000000	      1500 ns/op	     240 B/op	       3 allocs/op
+BenchmarkJSON/encode-8   	1000000	      2050 ns/op	     240 B/op	       2 allocs/op
+`
+
+func writeFile(t *testing.T, name, s string) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := ioutil.WriteFile(path, []byte(s), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func runCmd(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	status := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+func TestConvert(t *testing.T) {
+	status, out, errs := runCmd(oldBench, "convert", "-format", "csv")
+	if status != 0 {
+		t.Fatalf("got status %d: %s", status, errs)
+	}
+	if !strings.Contains(out, "JSON,decode,1000000,1000") {
+		t.Errorf("got %q; want the CSV output", out)
+	}
+	status, out, _ = runCmd(oldBench, "convert", "-format", "json")
+	if status != 0 {
+		t.Fatalf("got status %d", status)
+	}
+	// the JSON can be read back.
+	status, md, errs := runCmd(out, "convert", "-format", "md")
+	if status != 0 {
+		t.Fatalf("got status %d: %s", status, errs)
+	}
+	if !strings.Contains(md, "Group") || !strings.Contains(md, "decode") {
+		t.Errorf("got %q; want the markdown output", md)
+	}
+	var b benchutil.Benches
+	if err := json.Unmarshal([]byte(out), &b); err != nil || len(b.Benchmarks) != 2 {
+		t.Errorf("got %v, %d benchmarks", err, len(b.Benchmarks))
+	}
+	status, out, errs = runCmd(oldBench, "convert", "-format", "html")
+	if status != 0 {
+		t.Fatalf("got status %d: %s", status, errs)
+	}
+	if !strings.Contains(out, "<td>decode</td>") {
+		t.Errorf("got %q; want the HTML output", out)
+	}
+	status, _, errs = runCmd(oldBench, "convert", "-format", "xml")
+	if status != 2 || !strings.Contains(errs, "unknown format") {
+		t.Errorf("got status %d: %s", status, errs)
+	}
+}
+
+func TestConvertOptions(t *testing.T) {
+	path := writeFile(t, "opts.json", `{"hide_columns": ["group"], "title": "encoding", "precision": {"ns_op": 1}}`)
+	status, out, errs := runCmd(oldBench, "convert", "-format", "md", "-options", path)
+	if status != 0 {
+		t.Fatalf("got status %d: %s", status, errs)
+	}
+	if !strings.Contains(out, "# encoding") || strings.Contains(out, "|Group|") || !strings.Contains(out, "|1000.0|") {
+		t.Errorf("got %q; want the options applied", out)
+	}
+	path = writeFile(t, "bad.json", `{"hide_columns": ["ops"]}`)
+	status, _, errs = runCmd(oldBench, "convert", "-options", path)
+	if status != 2 || !strings.Contains(errs, "can't be hidden") {
+		t.Errorf("got status %d: %s", status, errs)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	old, cur := writeFile(t, "old.txt", oldBench), writeFile(t, "new.txt", newBench)
+	status, out, errs := runCmd("", "compare", old, cur)
+	if status != 0 {
+		t.Fatalf("got status %d: %s", status, errs)
+	}
+	for _, want := range []string{"JSON/decode", "+50.00%", "+2.50%"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("got %q; want it to contain %q", out, want)
+		}
+	}
+	_, out, _ = runCmd("", "compare", "-metric", "allocs/op", old, cur)
+	if !strings.Contains(out, "-33.33%") {
+		t.Errorf("got %q; want the allocs/op delta", out)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	old := writeFile(t, "old.txt", oldBench)
+	status, out, errs := runCmd(newBench, "check", "-baseline", old)
+	if status != 1 {
+		t.Errorf("got status %d; want 1", status)
+	}
+	if !strings.Contains(out, "regression: JSON/decode: ns/op 1000 -> 1500 (+50.0%)") || strings.Contains(out, "encode") {
+		t.Errorf("got %q; want only the decode regression", out)
+	}
+	if !strings.Contains(errs, "1 of 2 benchmarks regressed") {
+		t.Errorf("got %q", errs)
+	}
+	status, out, _ = runCmd(newBench, "check", "-baseline", old, "-threshold", "0.6")
+	if status != 0 || !strings.Contains(out, "ok: 2 benchmarks checked") {
+		t.Errorf("got status %d: %q", status, out)
+	}
+	status, _, _ = runCmd(newBench, "check")
+	if status != 2 {
+		t.Errorf("got status %d; want 2 without a baseline", status)
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	status, _, errs := runCmd("", "nope")
+	if status != 2 || !strings.Contains(errs, "unknown command") {
+		t.Errorf("got status %d: %s", status, errs)
+	}
+	status, _, _ = runCmd("")
+	if status != 2 {
+		t.Errorf("got status %d; want 2", status)
+	}
+	// the flags' usage is written to stderr.
+	status, _, errs = runCmd("", "convert", "-h")
+	if status != 2 || !strings.Contains(errs, "-format") {
+		t.Errorf("got status %d: %q; want the usage", status, errs)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+// compressSegment is the length of the segments compressible data is built
+// from.  Each segment is either random data or a copy of earlier data.
+const compressSegment = 256
+
+// compressWindow is how far back a copied segment can come from; it fits in
+// the window of every common compressor, e.g. deflate's 32 KiB.
+const compressWindow = 16 << 10
+
+// matchCost is the approximate size, relative to the segment, that a
+// compressor needs to encode a copied segment; it was measured using deflate
+// at its default compression level.
+const matchCost = 0.03
+
+// RandCompressible returns l pseudo-random bytes that compress with a ratio,
+// original size / compressed size, of about ratio with typical LZ based
+// compressors, e.g. deflate, zstd, or snappy.  The data is a mix of random,
+// incompressible, segments and copies of earlier segments; the proportion
+// of each is chosen to hit the ratio.  A ratio <= 1 returns incompressible
+// data.  This approximates the target; the exact ratio depends on the
+// compressor and its settings.  Ratios above about 25 can't be reached; the
+// data is as compressible as it gets at that point.
+func RandCompressible(l uint32, ratio float64) []byte {
+	return defaultGen.RandCompressible(l, ratio)
+}
+
+// RandCompressible returns l pseudo-random bytes that compress with a ratio
+// of about ratio.  See the RandCompressible function.
+func (g *Gen) RandCompressible(l uint32, ratio float64) []byte {
+	b := make([]byte, l)
+	if ratio <= 1 {
+		g.fill(b)
+		return b
+	}
+	// the fraction of random segments, p, solves:
+	// 1/ratio = p + (1-p) * matchCost
+	p := (1/ratio - matchCost) / (1 - matchCost)
+	if p < 0 {
+		p = 0
+	}
+	for i := 0; i < len(b); i += compressSegment {
+		end := i + compressSegment
+		if end > len(b) {
+			end = len(b)
+		}
+		if i == 0 || g.RandFloat64() < p {
+			g.fill(b[i:end])
+			continue
+		}
+		// copy an earlier segment, from within the window; the offset is
+		// byte aligned so copies aren't just repeats of whole segments.
+		back := i
+		if back > compressWindow {
+			back = compressWindow
+		}
+		src := i - 1 - g.RandIntn(back)
+		for j := i; j < end; j++ {
+			b[j] = b[src]
+			src++
+		}
+	}
+	return b
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"compress/flate"
+	"testing"
+)
+
+func TestRandCompressible(t *testing.T) {
+	for _, ratio := range []float64{1, 2, 4, 8} {
+		b := NewGen(1).RandCompressible(1<<20, ratio)
+		if len(b) != 1<<20 {
+			t.Fatalf("%v: got %d bytes; want %d", ratio, len(b), 1<<20)
+		}
+		var buf bytes.Buffer
+		w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		w.Write(b)
+		w.Close()
+		got := float64(len(b)) / float64(buf.Len())
+		if got < ratio*0.85 || got > ratio*1.15 {
+			t.Errorf("got a compression ratio of %.2f; want about %v", got, ratio)
+		}
+	}
+}
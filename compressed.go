@@ -0,0 +1,101 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// GzipBench is a Benchmarker whose output is gzip compressed.
+type GzipBench struct {
+	Benchmarker
+	forward
+	gz *gzip.Writer
+}
+
+// NewGzipBench returns a Benchmarker of the named format, see RegisterFormat,
+// whose output is gzip compressed and written to w, configured by the
+// options.
+func NewGzipBench(w io.Writer, format string, opts ...Option) (*GzipBench, error) {
+	gz := gzip.NewWriter(w)
+	b, err := NewFormat(format, gz, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &GzipBench{Benchmarker: b, forward: forward{b}, gz: gz}, nil
+}
+
+// Out writes the output and closes the gzip stream; nothing can be written
+// after Out.
+func (g *GzipBench) Out() error {
+	err := g.Benchmarker.Out()
+	if err != nil {
+		return err
+	}
+	return g.gz.Close()
+}
+
+// benches returns the wrapped Benchmarker's Benches, so SetBenches works
+// with a GzipBench.
+func (g *GzipBench) benches() *Benches {
+	b, ok := g.Benchmarker.(interface{ benches() *Benches })
+	if !ok {
+		return nil
+	}
+	return b.benches()
+}
+
+// ZipFile is a file in a zip archive of reports.
+type ZipFile struct {
+	Name   string // the file's name in the archive, e.g. results.csv.
+	Format string // the report's format: a registered format, see RegisterFormat, or json.
+}
+
+// WriteZip writes a zip archive, to w, with a file for each of the reports.
+// Each report is of src, using src's output settings, e.g. its headers and
+// whether or not system info is included, in the file's format; json is
+// src as JSON.  The files' modified times are src's Timestamp.
+func WriteZip(w io.Writer, src *Benches, files ...ZipFile) error {
+	zw := zip.NewWriter(w)
+	ts := src.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.Name, Method: zip.Deflate, Modified: ts})
+		if err != nil {
+			return err
+		}
+		if f.Format == "json" {
+			enc := json.NewEncoder(fw)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(src)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		b, err := NewFormat(f.Format, fw)
+		if err != nil {
+			return err
+		}
+		d, ok := b.(interface{ benches() *Benches })
+		if !ok || d.benches() == nil {
+			return fmt.Errorf("benchutil: zip %s: %T doesn't embed Benches", f.Name, b)
+		}
+		// the whole Benches is copied so the output settings are src's.
+		*d.benches() = *src
+		err = b.Out()
+		if err != nil {
+			return fmt.Errorf("benchutil: zip %s: %s", f.Name, err)
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGzipBench(t *testing.T) {
+	var buf bytes.Buffer
+	b, err := NewGzipBench(&buf, "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := NewBenches()
+	src.Hostname = "test"
+	src.Append(Bench{Name: "decode", Iterations: 1, Result: Result{Ops: 10, NsOp: 5}})
+	err = SetBenches(b, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.Out()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := ioutil.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "decode,10,5,0,0") {
+		t.Errorf("got %q; want the CSV output", out)
+	}
+	_, err = NewGzipBench(&buf, "nope")
+	if err == nil {
+		t.Error("got no error for an unknown format")
+	}
+}
+
+func TestWriteZip(t *testing.T) {
+	m := NewMDBench(ioutil.Discard)
+	m.SetNsOpColumnHeader("ns per op")
+	m.Timestamp = time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	m.Append(Bench{Name: "decode", Iterations: 1, Result: Result{Ops: 10, NsOp: 5}})
+	var buf bytes.Buffer
+	err := WriteZip(&buf, &m.Benches, ZipFile{"report.md", "md"}, ZipFile{"results.csv", "csv"}, ZipFile{"results.json", "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		if !f.Modified.Equal(m.Timestamp) {
+			t.Errorf("%s: got modified %s; want %s", f.Name, f.Modified, m.Timestamp)
+		}
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := ioutil.ReadAll(r)
+		r.Close()
+		files[f.Name] = string(b)
+	}
+	if len(files) != 3 {
+		t.Fatalf("got %d files; want 3", len(files))
+	}
+	if !strings.Contains(files["report.md"], "ns per op") {
+		t.Errorf("got %q; want the markdown with the source's headers", files["report.md"])
+	}
+	if !strings.Contains(files["results.csv"], "decode,10,5,0,0") {
+		t.Errorf("got %q; want the CSV", files["results.csv"])
+	}
+	var dec Benches
+	if err := json.Unmarshal([]byte(files["results.json"]), &dec); err != nil || len(dec.Benchmarks) != 1 {
+		t.Errorf("got %v, %+v; want the JSON", err, dec)
+	}
+	err = WriteZip(&buf, &m.Benches, ZipFile{"a.xml", "xml"})
+	if err == nil {
+		t.Error("got no error for an unknown format")
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"context"
+	"sync"
+)
+
+// ConcurrentBenches is a Benchmarker that is safe for concurrent use, e.g.
+// by benchmarks that are run in parallel and append their results as they
+// complete.  The Benchmarker it wraps shouldn't be used directly while the
+// ConcurrentBenches is in use.
+type ConcurrentBenches struct {
+	mu sync.Mutex
+	Benchmarker
+	forward
+}
+
+// NewConcurrentBenches returns a ConcurrentBenches that wraps b.
+func NewConcurrentBenches(b Benchmarker) *ConcurrentBenches {
+	return &ConcurrentBenches{Benchmarker: b, forward: forward{b}}
+}
+
+// Append adds the benches to the Benchmarker.
+func (c *ConcurrentBenches) Append(benches ...Bench) {
+	c.mu.Lock()
+	c.Benchmarker.Append(benches...)
+	c.mu.Unlock()
+}
+
+// AddWarning adds a warning to the Benchmarker, if it's an Annotator.
+func (c *ConcurrentBenches) AddWarning(s string) {
+	c.mu.Lock()
+	c.forward.AddWarning(s)
+	c.mu.Unlock()
+}
+
+// SetMeta sets a key value pair on the Benchmarker, if it's an Annotator.
+func (c *ConcurrentBenches) SetMeta(key, value string) {
+	c.mu.Lock()
+	c.forward.SetMeta(key, value)
+	c.mu.Unlock()
+}
+
+// Out writes the Benchmarker's output.  Benches appended while Out is
+// writing wait until it's done.
+func (c *ConcurrentBenches) Out() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Benchmarker.Out()
+}
+
+// OutContext writes the Benchmarker's output, see OutContext.
+func (c *ConcurrentBenches) OutContext(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return OutContext(ctx, c.Benchmarker)
+}
+
+// Collect returns a channel whose benches are appended as they're received,
+// and a func that waits until every bench sent has been appended.  Close the
+// channel when nothing more will be sent, then call the func.  The channel
+// is buffered by n.
+func (c *ConcurrentBenches) Collect(n int) (chan<- Bench, func()) {
+	ch := make(chan Bench, n)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for b := range ch {
+			c.Append(b)
+		}
+	}()
+	return ch, func() { <-done }
+}
+
+// benches returns the wrapped Benchmarker's Benches, so SetBenches works
+// with a ConcurrentBenches.
+func (c *ConcurrentBenches) benches() *Benches {
+	b, ok := c.Benchmarker.(interface{ benches() *Benches })
+	if !ok {
+		return nil
+	}
+	return b.benches()
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentBenches(t *testing.T) {
+	b := NewStringBench(ioutil.Discard)
+	c := NewConcurrentBenches(b)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				c.Append(Bench{Name: fmt.Sprintf("%d-%d", i, j), Iterations: 1})
+			}
+			c.SetMeta(fmt.Sprintf("worker%d", i), "done")
+		}(i)
+	}
+	wg.Wait()
+	if len(b.Benchmarks) != 400 {
+		t.Errorf("got %d benches; want 400", len(b.Benchmarks))
+	}
+	if len(b.Meta) != 8 {
+		t.Errorf("got %d meta; want 8", len(b.Meta))
+	}
+	if err := c.Out(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestConcurrentBenchesCollect(t *testing.T) {
+	b := NewCSVBench(ioutil.Discard)
+	c := NewConcurrentBenches(b)
+	ch, wait := c.Collect(4)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				ch <- Bench{Name: fmt.Sprintf("%d-%d", i, j), Iterations: 1}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(ch)
+	wait()
+	if len(b.Benchmarks) != 100 {
+		t.Errorf("got %d benches; want 100", len(b.Benchmarks))
+	}
+	src := NewBenches()
+	src.Append(Bench{Name: "x"})
+	if err := SetBenches(c, src); err != nil || len(b.Benchmarks) != 1 {
+		t.Errorf("got %v, %d benches; want SetBenches to reach the wrapped Benches", err, len(b.Benchmarks))
+	}
+}
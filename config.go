@@ -0,0 +1,185 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the report settings, its Options, and the runner and output
+// settings so they can be kept in a file, e.g. benchutil.yaml, that is
+// versioned with the code instead of being set in every harness.  The
+// Options are at the top level of the file; Apply applies them.  See
+// LoadConfig.
+//
+// A YAML config looks like:
+//
+//	title: encoding benchmarks
+//	headers:
+//	  ns_op: ns per op
+//	hide_columns: [desc]
+//	include_system_info: true
+//	section_per_group: true
+//	locale: de
+//	regression_threshold: 0.05
+//	runner:
+//	  cpu_scaling: require
+//	  max_load: 1.5
+//	outputs:
+//	  - format: md
+//	    path: bench.md
+//	  - format: csv
+//	    path: bench.csv
+type Config struct {
+	Options             `yaml:",inline"` // the report settings; see Options.
+	RegressionThreshold float64          `yaml:"regression_threshold" toml:"regression_threshold"` // the relative change that's a regression, e.g. 0.05; for use with regression checks.
+	Runner              RunnerConfig     `yaml:"runner" toml:"runner"`                             // the Runner's checks.
+	Outputs             []OutputConfig   `yaml:"outputs" toml:"outputs"`                           // where the reports are written.
+}
+
+// HeaderConfig holds the column headers.
+type HeaderConfig struct {
+	Group    string `json:"group,omitempty" yaml:"group" toml:"group"`
+	SubGroup string `json:"subgroup,omitempty" yaml:"subgroup" toml:"subgroup"`
+	Name     string `json:"name,omitempty" yaml:"name" toml:"name"`
+	Desc     string `json:"desc,omitempty" yaml:"desc" toml:"desc"`
+	Ops      string `json:"ops,omitempty" yaml:"ops" toml:"ops"`
+	NsOp     string `json:"ns_op,omitempty" yaml:"ns_op" toml:"ns_op"`
+	BytesOp  string `json:"bytes_op,omitempty" yaml:"bytes_op" toml:"bytes_op"`
+	AllocsOp string `json:"allocs_op,omitempty" yaml:"allocs_op" toml:"allocs_op"`
+	Note     string `json:"note,omitempty" yaml:"note" toml:"note"`
+}
+
+// RunnerConfig holds the settings for a Runner's pre-run checks.
+type RunnerConfig struct {
+	CPUScaling    string  `yaml:"cpu_scaling" toml:"cpu_scaling"`       // "warn", "require", or empty to not check; see Runner.CheckCPUScaling.
+	MaxLoad       float64 `yaml:"max_load" toml:"max_load"`             // see Runner.CheckLoad.
+	RecordThermal bool    `yaml:"record_thermal" toml:"record_thermal"` // see Runner.RecordThermal.
+}
+
+// OutputConfig is where a report is written.
+type OutputConfig struct {
+	Format string `yaml:"format" toml:"format"` // the name of a registered format, e.g. txt, csv, or md; see RegisterFormat.
+	Path   string `yaml:"path" toml:"path"`     // the file; "-" or empty is stdout.
+}
+
+// hideable are the columns that can be hidden, or forced.
+var hideable = map[string]bool{"group": true, "subgroup": true, "name": true, "desc": true, "note": true}
+
+// LoadConfig loads the config in the file.  The file's format is determined
+// by its extension: .yaml or .yml, or .toml.  Unknown settings are
+// an error, so typos don't go unnoticed.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	err = decodeSettings(filepath.Ext(path), data, &c)
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %s", path, err)
+	}
+	err = c.validate()
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %s", path, err)
+	}
+	return &c, nil
+}
+
+// decodeSettings decodes the settings in data, in the format of the file
+// extension ext: .yaml or .yml, or .toml, into v.  Unknown settings are an
+// error.
+func decodeSettings(ext string, data []byte, v interface{}) error {
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err := dec.Decode(v)
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	case ".toml":
+		md, err := toml.Decode(string(data), v)
+		if err == nil && len(md.Undecoded()) > 0 {
+			return fmt.Errorf("unknown setting %q", md.Undecoded()[0].String())
+		}
+		return err
+	}
+	return fmt.Errorf("unknown format %q", ext)
+}
+
+// validate checks the settings that have a fixed set of values.
+func (c *Config) validate() error {
+	err := c.Options.validate()
+	if err != nil {
+		return err
+	}
+	switch c.Runner.CPUScaling {
+	case "", "warn", "require":
+	default:
+		return fmt.Errorf("runner.cpu_scaling: unknown value %q", c.Runner.CPUScaling)
+	}
+	for i, o := range c.Outputs {
+		if !HasFormat(o.Format) {
+			return fmt.Errorf("outputs[%d]: unknown format %q", i, o.Format)
+		}
+	}
+	return nil
+}
+
+// ApplyRunner applies the runner settings to the Runner.
+func (c *Config) ApplyRunner(r *Runner) {
+	switch c.Runner.CPUScaling {
+	case "warn":
+		r.CheckCPUScaling(false)
+	case "require":
+		r.CheckCPUScaling(true)
+	}
+	r.CheckLoad(c.Runner.MaxLoad)
+	r.RecordThermal(c.Runner.RecordThermal)
+}
+
+// Open returns a Benchmarker for the output, with the config's settings
+// applied, and the file it writes to; the caller must close the file after
+// calling Out.  If the output's path is empty or "-", the Benchmarker writes
+// to stdout and the returned closer does nothing.
+func (c *Config) Open(o OutputConfig) (Benchmarker, io.Closer, error) {
+	if !HasFormat(o.Format) {
+		return nil, nil, fmt.Errorf("unknown output format %q", o.Format)
+	}
+	var w io.WriteCloser = nopCloser{os.Stdout}
+	if o.Path != "" && o.Path != "-" {
+		file, err := os.Create(o.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		w = file
+	}
+	b, err := NewFormat(o.Format, w)
+	if err == nil {
+		err = c.Apply(b)
+	}
+	if err != nil {
+		w.Close()
+		return nil, nil, err
+	}
+	return b, w, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
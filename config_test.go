@@ -0,0 +1,153 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const yamlConfig = `headers:
+  ns_op: ns per op
+hide_columns: [desc]
+column_padding: 4
+section_per_group: true
+regression_threshold: 0.05
+runner:
+  cpu_scaling: require
+  max_load: 1.5
+outputs:
+  - format: md
+    path: bench.md
+  - format: csv
+`
+
+const tomlConfig = `hide_columns = ["desc"]
+column_padding = 4
+section_per_group = true
+regression_threshold = 0.05
+
+[headers]
+ns_op = "ns per op"
+
+[runner]
+cpu_scaling = "require"
+max_load = 1.5
+
+[[outputs]]
+format = "md"
+path = "bench.md"
+
+[[outputs]]
+format = "csv"
+`
+
+func writeConfig(t *testing.T, name, s string) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := ioutil.WriteFile(path, []byte(s), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	for _, test := range []struct{ name, config string }{
+		{"benchutil.yaml", yamlConfig},
+		{"benchutil.toml", tomlConfig},
+	} {
+		c, err := LoadConfig(writeConfig(t, test.name, test.config))
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if c.Headers.NsOp != "ns per op" || len(c.HideColumns) != 1 || c.ColumnPadding != 4 || !c.SectionPerGroup {
+			t.Errorf("%s: got %+v", test.name, c)
+		}
+		if c.RegressionThreshold != 0.05 || c.Runner.CPUScaling != "require" || c.Runner.MaxLoad != 1.5 {
+			t.Errorf("%s: got %+v", test.name, c)
+		}
+		if len(c.Outputs) != 2 || c.Outputs[0] != (OutputConfig{"md", "bench.md"}) || c.Outputs[1].Format != "csv" {
+			t.Errorf("%s: got outputs %+v", test.name, c.Outputs)
+		}
+		r := NewRunner()
+		c.ApplyRunner(r)
+		if !r.checkScaling || !r.requireScaling || r.maxLoad != 1.5 {
+			t.Errorf("%s: got %+v; want the runner configured", test.name, r)
+		}
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	for _, test := range []struct{ name, config, want string }{
+		{"a.yaml", "colum_padding: 2\n", "colum_padding"},
+		{"a.toml", "colum_padding = 2\n", "colum_padding"},
+		{"a.yaml", "hide_columns: [ops]\n", `"ops" can't be hidden`},
+		{"a.yaml", "show_columns: [ns_op]\n", `"ns_op" can't be forced`},
+		{"a.yaml", "runner:\n  cpu_scaling: maybe\n", "cpu_scaling"},
+		{"a.yaml", "outputs:\n  - format: xml\n", `unknown format "xml"`},
+		{"a.ini", "", "unknown format"},
+	} {
+		_, err := LoadConfig(writeConfig(t, test.name, test.config))
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%q: got %v; want an error containing %q", test.config, err, test.want)
+		}
+	}
+	c, err := LoadConfig(writeConfig(t, "empty.yaml", ""))
+	if err != nil || len(c.Outputs) != 0 {
+		t.Errorf("got %+v, %v; want an empty config", c, err)
+	}
+}
+
+func TestConfigApply(t *testing.T) {
+	c, err := LoadConfig(writeConfig(t, "benchutil.yaml", yamlConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	b := NewStringBench(&buf)
+	c.Apply(b)
+	b.Append(Bench{Group: "json", Name: "decode", Desc: "decodes json", Iterations: 1, Result: Result{Ops: 10, NsOp: 100}})
+	err = b.Out()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "ns per op") {
+		t.Errorf("got %q; want the ns/op header set", buf.String())
+	}
+	if !strings.Contains(buf.String(), "json     decode") {
+		t.Errorf("got %q; want the column padding set", buf.String())
+	}
+	if strings.Contains(buf.String(), "decodes json") {
+		t.Errorf("got %q; want the desc column hidden", buf.String())
+	}
+}
+
+// A config's report settings are Options, so everything Options has can be
+// set in a config file.
+func TestConfigOptions(t *testing.T) {
+	c, err := LoadConfig(writeConfig(t, "benchutil.toml", "locale = \"de\"\naggregation = \"median\"\nns_op_as_duration = true\n\n[precision]\nns_op = 2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Locale != "de" || c.Aggregation != "median" || !c.NsOpAsDuration || c.Precision["ns_op"] != 2 {
+		t.Errorf("got %+v; want the options set", c.Options)
+	}
+	b := NewStringBench(ioutil.Discard)
+	if err := c.Apply(b); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Options(); got.Locale != "de" || got.Aggregation != "median" || got.Precision["ns_op"] != 2 {
+		t.Errorf("got %+v; want the config's options applied", got)
+	}
+	for _, s := range []string{"locale: xx\n", "aggregation: max\n", "precision:\n  ops: 2\n"} {
+		if _, err := LoadConfig(writeConfig(t, "a.yaml", s)); err == nil {
+			t.Errorf("%q: got no error; want one", s)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// rootfs is the root of the filesystem; it's a var so it can be changed for
+// testing.
+var rootfs = "/"
+
+// unlimited cgroup v1 memory limits are reported as a very large number,
+// the max int64 rounded down to the page size; anything at, or above, this
+// is treated as no limit.
+const cgroupV1NoLimit = 1 << 62
+
+// containerInfo holds information about the container, if any, the process
+// is running in and its cgroup limits.
+type containerInfo struct {
+	Container string  // the container runtime, e.g. docker; empty if not in a container.
+	CPUQuota  float64 // the cgroup CPU quota, in CPUs; 0 if there's no quota.
+	MemLimit  uint64  // the cgroup memory limit, in bytes; 0 if there's no limit.
+}
+
+// getContainerInfo detects whether or not the process is running in a
+// container and reads the cgroup CPU quota and memory limit.  Anything that
+// can't be determined is left empty.
+func getContainerInfo() containerInfo {
+	var c containerInfo
+	c.Container = detectContainer()
+	c.CPUQuota = cgroupCPUQuota()
+	c.MemLimit = cgroupMemLimit()
+	return c
+}
+
+// detectContainer returns the container runtime the process is running
+// under; if any.
+func detectContainer() string {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return "kubernetes"
+	}
+	if _, err := os.Stat(filepath.Join(rootfs, ".dockerenv")); err == nil {
+		return "docker"
+	}
+	if _, err := os.Stat(filepath.Join(rootfs, "run/.containerenv")); err == nil {
+		return "podman"
+	}
+	b, err := ioutil.ReadFile(filepath.Join(procfs, "1/cgroup"))
+	if err != nil {
+		return ""
+	}
+	s := string(b)
+	for _, v := range []string{"kubepods", "docker", "containerd", "lxc"} {
+		if strings.Contains(s, v) {
+			if v == "kubepods" {
+				return "kubernetes"
+			}
+			return v
+		}
+	}
+	return ""
+}
+
+// cgroupCPUQuota returns the CPU quota, in CPUs, from either the cgroup v2
+// cpu.max or the cgroup v1 cfs quota and period.  If there is no quota, 0 is
+// returned.
+func cgroupCPUQuota() float64 {
+	// cgroup v2: "max 100000" or "200000 100000"
+	if v, ok := readSysfsValue("fs/cgroup/cpu.max"); ok {
+		f := strings.Fields(v)
+		if len(f) != 2 || f[0] == "max" {
+			return 0
+		}
+		return quota(f[0], f[1])
+	}
+	// cgroup v1; a quota of -1 means no quota.
+	q, ok := readSysfsValue("fs/cgroup/cpu/cpu.cfs_quota_us")
+	if !ok {
+		return 0
+	}
+	p, ok := readSysfsValue("fs/cgroup/cpu/cpu.cfs_period_us")
+	if !ok {
+		return 0
+	}
+	return quota(q, p)
+}
+
+// quota returns the quota divided by the period; if either isn't a positive
+// number, 0 is returned.
+func quota(q, p string) float64 {
+	qv, err := strconv.ParseFloat(q, 64)
+	if err != nil || qv <= 0 {
+		return 0
+	}
+	pv, err := strconv.ParseFloat(p, 64)
+	if err != nil || pv <= 0 {
+		return 0
+	}
+	return qv / pv
+}
+
+// cgroupMemLimit returns the memory limit, in bytes, from either the cgroup
+// v2 memory.max or the cgroup v1 memory.limit_in_bytes.  If there is no
+// limit, 0 is returned.
+func cgroupMemLimit() uint64 {
+	v, ok := readSysfsValue("fs/cgroup/memory.max")
+	if !ok {
+		v, ok = readSysfsValue("fs/cgroup/memory/memory.limit_in_bytes")
+		if !ok {
+			return 0
+		}
+	}
+	if v == "max" {
+		return 0
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil || n >= cgroupV1NoLimit {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "testing"
+
+func TestCgroupLimits(t *testing.T) {
+	tests := []struct {
+		files map[string]string
+		quota float64
+		limit uint64
+	}{
+		{nil, 0, 0},
+		{map[string]string{"fs/cgroup/cpu.max": "max 100000\n", "fs/cgroup/memory.max": "max\n"}, 0, 0},
+		{map[string]string{"fs/cgroup/cpu.max": "200000 100000\n", "fs/cgroup/memory.max": "2147483648\n"}, 2, 2147483648},
+		{
+			map[string]string{
+				"fs/cgroup/cpu/cpu.cfs_quota_us":         "-1\n",
+				"fs/cgroup/cpu/cpu.cfs_period_us":        "100000\n",
+				"fs/cgroup/memory/memory.limit_in_bytes": "9223372036854771712\n",
+			},
+			0, 0,
+		},
+		{
+			map[string]string{
+				"fs/cgroup/cpu/cpu.cfs_quota_us":         "50000\n",
+				"fs/cgroup/cpu/cpu.cfs_period_us":        "100000\n",
+				"fs/cgroup/memory/memory.limit_in_bytes": "1073741824\n",
+			},
+			0.5, 1073741824,
+		},
+	}
+	for i, test := range tests {
+		restore := fakeSysfs(t, test.files)
+		q, l := cgroupCPUQuota(), cgroupMemLimit()
+		restore()
+		if q != test.quota {
+			t.Errorf("%d: got quota %v; want %v", i, q, test.quota)
+		}
+		if l != test.limit {
+			t.Errorf("%d: got limit %d; want %d", i, l, test.limit)
+		}
+	}
+}
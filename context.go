@@ -0,0 +1,148 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// ContextOuter is implemented by Benchmarkers whose output can be cancelled.
+type ContextOuter interface {
+	OutContext(ctx context.Context) error
+}
+
+// OutContext writes b's output, stopping if ctx is done.  If b doesn't
+// implement ContextOuter, ctx is only checked before Out is called.
+func OutContext(ctx context.Context, b Outputter) error {
+	if c, ok := b.(ContextOuter); ok {
+		return c.OutContext(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.Out()
+}
+
+// PartialOutputError is returned when the output was stopped, because its
+// context was done, after some of it was written.
+type PartialOutputError struct {
+	Written int64 // the number of bytes that were written.
+	Err     error // the context's error.
+}
+
+func (e *PartialOutputError) Error() string {
+	return fmt.Sprintf("benchutil: output stopped after %d bytes: %s", e.Written, e.Err)
+}
+
+// Unwrap returns the context's error, so errors.Is(err, context.Canceled)
+// works.
+func (e *PartialOutputError) Unwrap() error {
+	return e.Err
+}
+
+// ctxWriter is a writer that stops writing once its context is done.
+type ctxWriter struct {
+	ctx context.Context
+	w   io.Writer
+	n   int64
+}
+
+func (c *ctxWriter) Write(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// err returns the error for output that was stopped by c's context: the
+// context's error if nothing was written, otherwise a PartialOutputError.
+func (c *ctxWriter) err() error {
+	err := c.ctx.Err()
+	if err == nil {
+		return nil
+	}
+	if c.n == 0 {
+		return err
+	}
+	return &PartialOutputError{Written: c.n, Err: err}
+}
+
+// OutContext is Out with a context; once ctx is done nothing more is
+// written.  If any output was written before then, a *PartialOutputError
+// is returned.
+func (b *StringBench) OutContext(ctx context.Context) error {
+	w := b.w
+	cw := &ctxWriter{ctx: ctx, w: w}
+	b.w = cw
+	defer func() { b.w = w }()
+	err := b.Out()
+	if cerr := cw.err(); cerr != nil {
+		return cerr
+	}
+	return err
+}
+
+// OutContext is Out with a context; once ctx is done nothing more is
+// written.  If any output was written before then, a *PartialOutputError
+// is returned.
+func (b *CSVBench) OutContext(ctx context.Context) error {
+	w, out := b.w, b.out
+	cw := &ctxWriter{ctx: ctx, w: out}
+	// both writers are replaced; records formatted in parallel are written
+	// to out directly.
+	b.w, b.out = csv.NewWriter(cw), cw
+	b.w.Comma, b.w.UseCRLF = w.Comma, w.UseCRLF
+	defer func() { b.w, b.out = w, out }()
+	err := b.Out()
+	if cerr := cw.err(); cerr != nil {
+		return cerr
+	}
+	return err
+}
+
+// OutContext is Out with a context; once ctx is done nothing more is
+// written.  If any output was written before then, a *PartialOutputError
+// is returned.
+func (b *MDBench) OutContext(ctx context.Context) error {
+	w := b.w
+	cw := &ctxWriter{ctx: ctx, w: w}
+	b.w = cw
+	defer func() { b.w = w }()
+	err := b.Out()
+	if cerr := cw.err(); cerr != nil {
+		return cerr
+	}
+	return err
+}
+
+// OutContext is Out with a context; once ctx is done nothing more is
+// written.  If any output was written before then, a *PartialOutputError
+// is returned.
+func (b *HTMLBench) OutContext(ctx context.Context) error {
+	w := b.w
+	cw := &ctxWriter{ctx: ctx, w: w}
+	b.w = cw
+	defer func() { b.w = w }()
+	err := b.Out()
+	if cerr := cw.err(); cerr != nil {
+		return cerr
+	}
+	return err
+}
+
+// OutContext is Out with a context, see OutContext.  The gzip stream is
+// only closed if the output is complete.
+func (g *GzipBench) OutContext(ctx context.Context) error {
+	err := OutContext(ctx, g.Benchmarker)
+	if err != nil {
+		return err
+	}
+	return g.gz.Close()
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+// cancelWriter cancels its context after its first write.
+type cancelWriter struct {
+	bytes.Buffer
+	cancel func()
+}
+
+func (w *cancelWriter) Write(p []byte) (int, error) {
+	defer w.cancel()
+	return w.Buffer.Write(p)
+}
+
+func TestOutContext(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		new  func(w io.Writer) Benchmarker
+	}{
+		{"txt", func(w io.Writer) Benchmarker { return NewStringBench(w) }},
+		{"csv", func(w io.Writer) Benchmarker { return NewCSVBench(w) }},
+		{"md", func(w io.Writer) Benchmarker { return NewMDBench(w) }},
+		{"html", func(w io.Writer) Benchmarker { return NewHTMLBench(w) }},
+	} {
+		// a done context writes nothing.
+		var buf bytes.Buffer
+		b := test.new(&buf)
+		b.Append(Bench{Name: "decode", Iterations: 1, Result: Result{Ops: 10, NsOp: 5}})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := OutContext(ctx, b)
+		if err != context.Canceled {
+			t.Errorf("%s: got %v; want %v", test.name, err, context.Canceled)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("%s: got %q; want no output", test.name, buf.String())
+		}
+
+		// a context that's done part way reports what was written.
+		ctx, cancel = context.WithCancel(context.Background())
+		cw := &cancelWriter{cancel: cancel}
+		b = test.new(cw)
+		b.Append(Bench{Name: "decode", Iterations: 1, Result: Result{Ops: 10, NsOp: 5}})
+		err = OutContext(ctx, b)
+		var perr *PartialOutputError
+		if !errors.As(err, &perr) {
+			t.Errorf("%s: got %v; want a PartialOutputError", test.name, err)
+			continue
+		}
+		if perr.Written != int64(cw.Len()) || !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: got %v; want %d bytes written and context.Canceled", test.name, err, cw.Len())
+		}
+
+		// a context that isn't done writes everything.
+		var out bytes.Buffer
+		b = test.new(&out)
+		b.Append(Bench{Name: "decode", Iterations: 1, Result: Result{Ops: 10, NsOp: 5}})
+		err = OutContext(context.Background(), b)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+		}
+		if out.Len() == 0 {
+			t.Errorf("%s: got no output", test.name)
+		}
+	}
+}
+
+// The rows formatted in parallel are written through the context's writer
+// too, so nothing is written once it's done.
+func TestOutContextParallel(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		new  func(w io.Writer) Benchmarker
+	}{
+		{"txt", func(w io.Writer) Benchmarker { return NewStringBench(w, WithParallelism(4)) }},
+		{"csv", func(w io.Writer) Benchmarker { return NewCSVBench(w, WithParallelism(4)) }},
+		{"md", func(w io.Writer) Benchmarker { return NewMDBench(w, WithParallelism(4)) }},
+		{"html", func(w io.Writer) Benchmarker { return NewHTMLBench(w, WithParallelism(4)) }},
+	} {
+		ctx, cancel := context.WithCancel(context.Background())
+		cw := &cancelWriter{cancel: cancel}
+		b := test.new(cw)
+		b.Append(largeBenches(3 * rowsPerChunk)...)
+		err := OutContext(ctx, b)
+		var perr *PartialOutputError
+		if !errors.As(err, &perr) {
+			t.Errorf("%s: got %v; want a PartialOutputError", test.name, err)
+			continue
+		}
+		if perr.Written != int64(cw.Len()) {
+			t.Errorf("%s: got %d bytes written, %d reported; want them the same", test.name, cw.Len(), perr.Written)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// procfs is the mount point of procfs; it's a var so it can be changed for
+// testing.
+var procfs = "/proc"
+
+const defaultCooldownPoll = time.Second
+
+// Cooldown configures the pause a Runner takes between benchmarks.  The Delay
+// is always waited.  If MaxLoad and/or MaxTemp are set, the Runner then waits
+// until the load average and/or the CPU temperature have dropped to, or
+// below, the threshold, checking every Poll, for up to Timeout.
+type Cooldown struct {
+	Delay   time.Duration // fixed pause between benchmarks.
+	MaxLoad float64       // wait until the 1 minute load average is <= MaxLoad; 0 disables.
+	MaxTemp float64       // wait until the CPU temperature, in °C, is <= MaxTemp; 0 disables.
+	Poll    time.Duration // how often the load and temperature are checked; default is 1 second.
+	Timeout time.Duration // maximum time to wait for the thresholds; 0 means no limit.
+}
+
+// Wait pauses for the cooldown.  If the load average or temperature can't be
+// read, that threshold is ignored.
+func (c Cooldown) Wait() {
+	if c.Delay > 0 {
+		time.Sleep(c.Delay)
+	}
+	if c.MaxLoad <= 0 && c.MaxTemp <= 0 {
+		return
+	}
+	poll := c.Poll
+	if poll <= 0 {
+		poll = defaultCooldownPoll
+	}
+	var deadline time.Time
+	if c.Timeout > 0 {
+		deadline = time.Now().Add(c.Timeout)
+	}
+	for !c.cool() {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return
+		}
+		time.Sleep(poll)
+	}
+}
+
+// cool returns whether or not the system is at, or below, the thresholds.
+func (c Cooldown) cool() bool {
+	if c.MaxLoad > 0 {
+		l, err := LoadAvg()
+		if err == nil && l[0] > c.MaxLoad {
+			return false
+		}
+	}
+	if c.MaxTemp > 0 {
+		t, ok := CPUTemp()
+		if ok && t > c.MaxTemp {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadAvg returns the 1, 5, and 15 minute load averages from /proc/loadavg.
+func LoadAvg() ([3]float64, error) {
+	var l [3]float64
+	b, err := ioutil.ReadFile(filepath.Join(procfs, "loadavg"))
+	if err != nil {
+		return l, err
+	}
+	fields := strings.Fields(string(b))
+	if len(fields) < 3 {
+		return l, fmt.Errorf("loadavg: unexpected format: %q", b)
+	}
+	for i := range l {
+		l[i], err = strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return l, fmt.Errorf("loadavg: %s", err)
+		}
+	}
+	return l, nil
+}
+
+// CPUTemp returns the highest temperature, in °C, reported by the system's
+// thermal zones.  If no temperature could be read, false is returned.
+func CPUTemp() (float64, bool) {
+	paths, _ := filepath.Glob(filepath.Join(sysfs, "class/thermal/thermal_zone*/temp"))
+	var max float64
+	var ok bool
+	for _, p := range paths {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		// the temperature is in millidegrees Celsius.
+		v, err := strconv.ParseFloat(strings.TrimSpace(string(b)), 64)
+		if err != nil {
+			continue
+		}
+		v /= 1000
+		if !ok || v > max {
+			max = v
+			ok = true
+		}
+	}
+	return max, ok
+}
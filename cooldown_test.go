@@ -0,0 +1,44 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCPUTemp(t *testing.T) {
+	restore := fakeSysfs(t, map[string]string{
+		"class/thermal/thermal_zone0/temp": "45000\n",
+		"class/thermal/thermal_zone1/temp": "61500\n",
+	})
+	defer restore()
+	v, ok := CPUTemp()
+	if !ok {
+		t.Fatal("got no temperature; want one")
+	}
+	if v != 61.5 {
+		t.Errorf("got %v; want 61.5", v)
+	}
+}
+
+func TestCooldownWait(t *testing.T) {
+	restore := fakeSysfs(t, map[string]string{
+		"class/thermal/thermal_zone0/temp": "90000\n",
+	})
+	defer restore()
+	c := Cooldown{Delay: time.Millisecond, MaxTemp: 80, Poll: time.Millisecond, Timeout: 20 * time.Millisecond}
+	start := time.Now()
+	c.Wait()
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Errorf("waited %s; want at least the timeout", d)
+	}
+	c.MaxTemp = 95
+	start = time.Now()
+	c.Wait()
+	if d := time.Since(start); d >= 20*time.Millisecond {
+		t.Errorf("waited %s; want less than the timeout", d)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+// Corpus is a pool of pre-generated benchmark inputs that are handed out
+// cyclically.  Generating the inputs before the benchmark's timer is reset
+// keeps the generation cost, and its allocations, out of the measured loop:
+//
+//	c := benchutil.NewCorpus(1024, func(i int) interface{} {
+//		return benchutil.RandBytes(64)
+//	})
+//	b.ResetTimer()
+//	for i := 0; i < b.N; i++ {
+//		sink = hash(c.Next().([]byte))
+//	}
+//
+// Handing out the inputs doesn't allocate.  A Corpus is not safe for
+// concurrent use.
+type Corpus struct {
+	items []interface{}
+	i     int
+}
+
+// NewCorpus returns a Corpus of n inputs; the i-th input is the value
+// returned by gen(i).  It panics if n <= 0.
+func NewCorpus(n int, gen func(i int) interface{}) *Corpus {
+	if n <= 0 {
+		panic("invalid argument to NewCorpus")
+	}
+	c := &Corpus{items: make([]interface{}, n)}
+	for i := range c.items {
+		c.items[i] = gen(i)
+	}
+	return c
+}
+
+// NewBytesCorpus returns a Corpus of n []byte inputs, each of size bytes in
+// the full 0-255 range, generated using g.  The values returned by Next are
+// []byte.
+func (g *Gen) NewBytesCorpus(n int, size uint32) *Corpus {
+	return NewCorpus(n, func(int) interface{} { return g.RandRawBytes(size) })
+}
+
+// NewStringCorpus returns a Corpus of n alphanumeric string inputs, each l
+// characters long, generated using g.  The values returned by Next are
+// strings.
+func (g *Gen) NewStringCorpus(n int, l uint32) *Corpus {
+	return NewCorpus(n, func(int) interface{} { return g.RandString(l) })
+}
+
+// Next returns the next input; after the last input, it starts over with the
+// first.
+func (c *Corpus) Next() interface{} {
+	v := c.items[c.i]
+	c.i++
+	if c.i == len(c.items) {
+		c.i = 0
+	}
+	return v
+}
+
+// Len returns the number of inputs in the Corpus.
+func (c *Corpus) Len() int {
+	return len(c.items)
+}
+
+// Get returns the i-th input.
+func (c *Corpus) Get(i int) interface{} {
+	return c.items[i]
+}
+
+// Reset makes the next call to Next return the first input.
+func (c *Corpus) Reset() {
+	c.i = 0
+}
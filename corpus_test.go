@@ -0,0 +1,33 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "testing"
+
+func TestCorpus(t *testing.T) {
+	c := NewCorpus(3, func(i int) interface{} { return i * 10 })
+	var got []int
+	for i := 0; i < 7; i++ {
+		got = append(got, c.Next().(int))
+	}
+	want := []int{0, 10, 20, 0, 10, 20, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	}
+	c.Reset()
+	if v := c.Next().(int); v != 0 {
+		t.Errorf("got %d after Reset; want 0", v)
+	}
+	bc := NewGen(1).NewBytesCorpus(4, 32)
+	if bc.Len() != 4 || len(bc.Get(3).([]byte)) != 32 {
+		t.Errorf("got %d inputs of %d bytes; want 4 of 32", bc.Len(), len(bc.Get(3).([]byte)))
+	}
+	n := testing.AllocsPerRun(100, func() { bc.Next() })
+	if n != 0 {
+		t.Errorf("got %v allocs per Next; want 0", n)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/cpu"
+)
+
+// cpuFeature is an ISA extension and whether the CPU supports it.
+type cpuFeature struct {
+	name string
+	ok   bool
+}
+
+// cpuFeatures returns the ISA extensions, that SIMD dependent code is most
+// likely to use, that the CPU supports.  The names match the ones used in
+// /proc/cpuinfo.  Only amd64, 386, and arm64 are checked; other
+// architectures return nil.
+func cpuFeatures() []string {
+	var features []cpuFeature
+	switch runtime.GOARCH {
+	case "amd64", "386":
+		features = []cpuFeature{
+			{"sse3", cpu.X86.HasSSE3},
+			{"ssse3", cpu.X86.HasSSSE3},
+			{"sse4_1", cpu.X86.HasSSE41},
+			{"sse4_2", cpu.X86.HasSSE42},
+			{"popcnt", cpu.X86.HasPOPCNT},
+			{"aes", cpu.X86.HasAES},
+			{"pclmulqdq", cpu.X86.HasPCLMULQDQ},
+			{"avx", cpu.X86.HasAVX},
+			{"avx2", cpu.X86.HasAVX2},
+			{"fma", cpu.X86.HasFMA},
+			{"bmi1", cpu.X86.HasBMI1},
+			{"bmi2", cpu.X86.HasBMI2},
+			{"avx512f", cpu.X86.HasAVX512F},
+			{"avx512cd", cpu.X86.HasAVX512CD},
+			{"avx512bw", cpu.X86.HasAVX512BW},
+			{"avx512dq", cpu.X86.HasAVX512DQ},
+			{"avx512vl", cpu.X86.HasAVX512VL},
+			{"avx512_vnni", cpu.X86.HasAVX512VNNI},
+			{"avx512_bf16", cpu.X86.HasAVX512BF16},
+			{"amx_tile", cpu.X86.HasAMXTile},
+		}
+	case "arm64":
+		features = []cpuFeature{
+			{"asimd", cpu.ARM64.HasASIMD},
+			{"asimddp", cpu.ARM64.HasASIMDDP},
+			{"aes", cpu.ARM64.HasAES},
+			{"pmull", cpu.ARM64.HasPMULL},
+			{"sha2", cpu.ARM64.HasSHA2},
+			{"sha512", cpu.ARM64.HasSHA512},
+			{"crc32", cpu.ARM64.HasCRC32},
+			{"atomics", cpu.ARM64.HasATOMICS},
+			{"sve", cpu.ARM64.HasSVE},
+			{"sve2", cpu.ARM64.HasSVE2},
+		}
+	}
+	var names []string
+	for _, f := range features {
+		if f.ok {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
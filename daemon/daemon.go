@@ -0,0 +1,120 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+// Package daemon runs benchmarks on a schedule: each run is saved to a
+// history store and checked for regressions, which are sent to notifiers.
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/mohae/benchutil"
+	"github.com/mohae/benchutil/history"
+)
+
+// Notifier is notified of a run's results and regressions; a
+// notify.Webhook is a Notifier.
+type Notifier interface {
+	Notify(b *benchutil.Benches, regressions []history.Regression) error
+}
+
+// Daemon runs a Runner's benchmarks on a schedule.  Each run is saved to the
+// Store; if the run has any regressions, against the Store's prior runs,
+// the Notifiers are notified.
+type Daemon struct {
+	Runner    *benchutil.Runner // the benchmarks that are run.
+	Schedule  Schedule          // when the benchmarks are run.
+	Store     history.Store     // where the runs are saved.
+	Notifiers []Notifier        // notified of regressions; optional.
+	Name      string            // the name of each run; optional.
+	Baseline  int               // the number of prior runs a run is checked against; 0 is all of them.
+	Threshold float64           // the change that is a regression, e.g. 0.1 is 10%.
+	Metrics   []history.Metric  // the metrics checked for regressions; default is NsOp.
+	Always    bool              // notify after every run, not just the ones with regressions.
+	Log       io.Writer         // where errors and run summaries are written; default is os.Stderr.
+}
+
+// RunOnce runs the benchmarks, checks them for regressions, saves them to
+// the store, and notifies the notifiers, if there are regressions or
+// Always is set.  The run's benches and regressions are returned.  A
+// failed notification doesn't stop the others; the first error is
+// returned.
+func (d *Daemon) RunOnce() (*benchutil.Benches, []history.Regression, error) {
+	if d.Runner == nil {
+		return nil, nil, errors.New("daemon: no runner")
+	}
+	if d.Store == nil {
+		return nil, nil, errors.New("daemon: no store")
+	}
+	bench := benchutil.NewStringBench(ioutil.Discard)
+	b := &bench.Benches
+	b.Name = d.Name
+	err := d.Runner.Run(bench)
+	if err != nil {
+		return nil, nil, err
+	}
+	regs, err := history.CheckRegressions(d.Store, b, d.Baseline, d.Threshold, d.Metrics...)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, err = d.Store.SaveRun(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(regs) == 0 && !d.Always {
+		return b, regs, nil
+	}
+	var nerr error
+	for _, n := range d.Notifiers {
+		err := n.Notify(b, regs)
+		if err != nil && nerr == nil {
+			nerr = err
+		}
+	}
+	return b, regs, nerr
+}
+
+// Run runs the benchmarks at each of the schedule's times until done is
+// closed or receives a value, or the schedule has no more runs.  A failed
+// run is logged and doesn't stop the daemon.  A run isn't interrupted by
+// done; Run returns after the run completes.
+func (d *Daemon) Run(done chan struct{}) error {
+	if d.Schedule == nil {
+		return errors.New("daemon: no schedule")
+	}
+	for {
+		next := d.Schedule.Next(time.Now())
+		if next.IsZero() {
+			return nil
+		}
+		t := time.NewTimer(time.Until(next))
+		select {
+		case <-done:
+			t.Stop()
+			return nil
+		case <-t.C:
+		}
+		b, regs, err := d.RunOnce()
+		if err != nil {
+			d.logf("run: %s", err)
+			if b == nil {
+				continue
+			}
+		}
+		d.logf("ran %d benchmarks: %d regressions", len(b.Benchmarks), len(regs))
+	}
+}
+
+func (d *Daemon) logf(format string, v ...interface{}) {
+	w := d.Log
+	if w == nil {
+		w = os.Stderr
+	}
+	fmt.Fprintf(w, "%s daemon: %s\n", time.Now().Format(time.RFC3339), fmt.Sprintf(format, v...))
+}
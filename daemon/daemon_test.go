@@ -0,0 +1,124 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package daemon
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mohae/benchutil"
+	"github.com/mohae/benchutil/history"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	calls int
+	regs  []history.Regression
+}
+
+func (r *recorder) Notify(b *benchutil.Benches, regressions []history.Regression) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	r.regs = regressions
+	return nil
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+func newTestRunner() *benchutil.Runner {
+	r := benchutil.NewRunner()
+	r.BenchTime(10 * time.Millisecond)
+	b := benchutil.NewBench("sleep")
+	b.Group = "time"
+	r.Add(b, func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			time.Sleep(time.Millisecond)
+		}
+	})
+	return r
+}
+
+func TestRunOnce(t *testing.T) {
+	s := history.NewMemStore()
+	rec := &recorder{}
+	d := &Daemon{Runner: newTestRunner(), Store: s, Notifiers: []Notifier{rec}, Threshold: 0.1}
+	b, regs, err := d.RunOnce()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(b.Benchmarks) != 1 || len(regs) != 0 {
+		t.Fatalf("got %d benchmarks and %d regressions; want 1 and 0", len(b.Benchmarks), len(regs))
+	}
+	if rec.count() != 0 {
+		t.Errorf("got %d notifications; want 0", rec.count())
+	}
+	// a prior run that was much faster makes the next run a regression.
+	fast := benchutil.NewBenches()
+	fast.Timestamp = time.Now().Add(-time.Hour)
+	fast.Append(benchutil.Bench{Group: "time", Name: "sleep", Iterations: 1, Result: benchutil.Result{NsOp: 1}})
+	s2 := history.NewMemStore()
+	s2.SaveRun(fast)
+	d.Store = s2
+	_, regs, err = d.RunOnce()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(regs) != 1 || regs[0].Name != "time/sleep" {
+		t.Fatalf("got %v; want a time/sleep regression", regs)
+	}
+	if rec.count() != 1 || len(rec.regs) != 1 {
+		t.Errorf("got %d notifications of %d regressions; want 1 of 1", rec.count(), len(rec.regs))
+	}
+	runs, err := s2.ListRuns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 {
+		t.Errorf("got %d runs in the store; want 2", len(runs))
+	}
+}
+
+func TestRun(t *testing.T) {
+	rec := &recorder{}
+	var log bytes.Buffer
+	d := &Daemon{
+		Runner:    newTestRunner(),
+		Schedule:  Every(time.Millisecond),
+		Store:     history.NewMemStore(),
+		Notifiers: []Notifier{rec},
+		Always:    true,
+		Log:       &log,
+	}
+	done := make(chan struct{})
+	stopped := make(chan error)
+	go func() {
+		stopped <- d.Run(done)
+	}()
+	for i := 0; rec.count() < 2 && i < 500; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(done)
+	err := <-stopped
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if rec.count() < 2 {
+		t.Errorf("got %d notifications; want at least 2", rec.count())
+	}
+	if !strings.Contains(log.String(), "ran 1 benchmarks") {
+		t.Errorf("got log %q; want run summaries", log.String())
+	}
+	if err := (&Daemon{}).Run(done); err == nil {
+		t.Error("got no error without a schedule")
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package daemon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is when a Daemon runs its benchmarks.
+type Schedule interface {
+	// Next returns the first time after t that the benchmarks should be
+	// run.  A zero time means there are no more runs.
+	Next(t time.Time) time.Time
+}
+
+// Every returns a Schedule of runs d apart.
+func Every(d time.Duration) Schedule {
+	return every(d)
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	if e <= 0 {
+		return time.Time{}
+	}
+	return t.Add(time.Duration(e))
+}
+
+// Cron is a Schedule from a cron expression.
+type Cron struct {
+	spec                          string
+	minute, hour, dom, month, dow uint64 // bit n is set if n matches.
+	domAny, dowAny                bool
+}
+
+// ParseCron parses a standard five field cron expression: minute, hour, day
+// of month, month, and day of week, e.g. "30 2 * * 1-5" is 02:30 every
+// weekday.  Each field is a *, a value, a range, or a comma separated list of
+// them; each of which can have a step, e.g. "*/15" or "0-30/10".  Days
+// of the week are 0-6, Sunday is 0, and both 7 and the first three letters
+// of their names are also accepted, as are the first three letters of the
+// months' names.  If both the day of month and day of week are restricted,
+// either matching is a match.  The expressions @hourly, @daily, @weekly,
+// @monthly, and @yearly are also accepted.  The times are in the location
+// of the time passed to Next.
+func ParseCron(spec string) (*Cron, error) {
+	s := strings.TrimSpace(spec)
+	switch s {
+	case "@hourly":
+		s = "0 * * * *"
+	case "@daily", "@midnight":
+		s = "0 0 * * *"
+	case "@weekly":
+		s = "0 0 * * 0"
+	case "@monthly":
+		s = "0 0 1 * *"
+	case "@yearly", "@annually":
+		s = "0 0 1 1 *"
+	}
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("daemon: cron %q: got %d fields; want 5", spec, len(fields))
+	}
+	c := &Cron{spec: spec}
+	var err error
+	c.minute, err = parseField(fields[0], 0, 59, nil)
+	if err != nil {
+		return nil, fmt.Errorf("daemon: cron %q: minute: %s", spec, err)
+	}
+	c.hour, err = parseField(fields[1], 0, 23, nil)
+	if err != nil {
+		return nil, fmt.Errorf("daemon: cron %q: hour: %s", spec, err)
+	}
+	c.dom, err = parseField(fields[2], 1, 31, nil)
+	if err != nil {
+		return nil, fmt.Errorf("daemon: cron %q: day of month: %s", spec, err)
+	}
+	c.month, err = parseField(fields[3], 1, 12, months)
+	if err != nil {
+		return nil, fmt.Errorf("daemon: cron %q: month: %s", spec, err)
+	}
+	c.dow, err = parseField(fields[4], 0, 7, days)
+	if err != nil {
+		return nil, fmt.Errorf("daemon: cron %q: day of week: %s", spec, err)
+	}
+	// 7 is also Sunday.
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domAny = fields[2] == "*" || strings.HasPrefix(fields[2], "*/")
+	c.dowAny = fields[4] == "*" || strings.HasPrefix(fields[4], "*/")
+	return c, nil
+}
+
+var months = []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+var days = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// parseField returns the bits of the values the field matches.
+func parseField(f string, min, max int, names []string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(f, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+			part = part[:i]
+		}
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			i := strings.Index(part, "-")
+			var err error
+			lo, err = parseValue(part[:i], min, max, names)
+			if err != nil {
+				return 0, err
+			}
+			hi, err = parseValue(part[i+1:], min, max, names)
+			if err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			var err error
+			lo, err = parseValue(part, min, max, names)
+			if err != nil {
+				return 0, err
+			}
+			hi = lo
+			// a value with a step, e.g. 5/15, is from the value to max.
+			if step > 1 {
+				hi = max
+			}
+		}
+		for i := lo; i <= hi; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, min, max int, names []string) (int, error) {
+	for i, n := range names {
+		if n != "" && strings.EqualFold(s, n) {
+			return i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("%d is out of range: %d-%d", v, min, max)
+	}
+	return v, nil
+}
+
+// String returns the expression the Cron was parsed from.
+func (c *Cron) String() string {
+	return c.spec
+}
+
+// Next returns the first minute after t that matches the expression.  If
+// nothing matches within 5 years, e.g. for Feb 30, a zero time is returned.
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	end := t.AddDate(5, 0, 0)
+	for t.Before(end) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.day(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// day returns whether or not t's day matches.
+func (c *Cron) day(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCron(t *testing.T) {
+	// a Wednesday.
+	from := time.Date(2016, 6, 1, 10, 17, 30, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2016, 6, 1, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2016, 6, 1, 10, 30, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2016, 6, 1, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2016, 6, 2, 0, 0, 0, 0, time.UTC)},
+		{"30 2 * * 1-5", time.Date(2016, 6, 2, 2, 30, 0, 0, time.UTC)},
+		{"0 3 * * sat,sun", time.Date(2016, 6, 4, 3, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2016, 6, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"5,10 9 * * *", time.Date(2016, 6, 2, 9, 5, 0, 0, time.UTC)},
+		// either the day of month or week matches: the 15th or Mondays.
+		{"0 0 15 * 1", time.Date(2016, 6, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, test := range tests {
+		c, err := ParseCron(test.spec)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.spec, err)
+			continue
+		}
+		got := c.Next(from)
+		if !got.Equal(test.want) {
+			t.Errorf("%s: got %s; want %s", test.spec, got, test.want)
+		}
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "x * * * *"} {
+		_, err := ParseCron(spec)
+		if err == nil {
+			t.Errorf("%q: got no error", spec)
+		}
+	}
+}
+
+func TestEvery(t *testing.T) {
+	from := time.Date(2016, 6, 1, 10, 17, 30, 0, time.UTC)
+	got := Every(time.Hour).Next(from)
+	if want := from.Add(time.Hour); !got.Equal(want) {
+		t.Errorf("got %s; want %s", got, want)
+	}
+	if got := Every(0).Next(from); !got.IsZero() {
+		t.Errorf("got %s; want a zero time", got)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Disk holds information about a block device.
+type Disk struct {
+	Name  string `json:"name"`            // the device name, e.g. sda, nvme0n1.
+	Model string `json:"model,omitempty"` // the device's model; empty if unknown.
+	Type  string `json:"type"`            // the type of device: hdd, ssd, or nvme.
+	Size  uint64 `json:"size"`            // the size of the device, in bytes.
+}
+
+// GetDisks enumerates the system's block devices from /sys/block.  Virtual
+// devices, e.g. loop, ram, and device-mapper devices, are skipped.  If no
+// devices are found, nil is returned.
+func GetDisks() ([]Disk, error) {
+	devs, err := filepath.Glob(filepath.Join(sysfs, "block/*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(devs)
+	var disks []Disk
+	for _, dev := range devs {
+		name := filepath.Base(dev)
+		// virtual devices don't have a backing device.
+		if _, err := os.Stat(filepath.Join(dev, "device")); err != nil {
+			continue
+		}
+		rel := filepath.Join("block", name)
+		d := Disk{Name: name, Type: "ssd"}
+		d.Model, _ = readSysfsValue(filepath.Join(rel, "device/model"))
+		if strings.HasPrefix(name, "nvme") {
+			d.Type = "nvme"
+		} else if v, _ := readSysfsValue(filepath.Join(rel, "queue/rotational")); v == "1" {
+			d.Type = "hdd"
+		}
+		// the size is in 512 byte sectors, regardless of the device's
+		// sector size.
+		if v, ok := readSysfsValue(filepath.Join(rel, "size")); ok {
+			n, _ := strconv.ParseUint(v, 10, 64)
+			d.Size = n * 512
+		}
+		disks = append(disks, d)
+	}
+	return disks, nil
+}
+
+// workingFilesystem returns the type of the filesystem, e.g. ext4, that the
+// working directory is on, and the device it's mounted from.  The mount with
+// the longest mount point containing the working directory is used.
+func workingFilesystem() (fs, dev string) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", ""
+	}
+	f, err := os.Open(filepath.Join(procfs, "self/mounts"))
+	if err != nil {
+		return "", ""
+	}
+	defer f.Close()
+	var best string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		// device mountpoint fstype options dump pass
+		fields := strings.Fields(s.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		mnt := fields[1]
+		if mnt != "/" && wd != mnt && !strings.HasPrefix(wd, mnt+"/") {
+			continue
+		}
+		if len(mnt) >= len(best) {
+			best, fs, dev = mnt, fields[2], fields[0]
+		}
+	}
+	return fs, dev
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "testing"
+
+func TestGetDisks(t *testing.T) {
+	restore := fakeSysfs(t, map[string]string{
+		"block/loop0/size":               "2048\n",
+		"block/nvme0n1/device/model":     "Samsung SSD 970 EVO\n",
+		"block/nvme0n1/queue/rotational": "0\n",
+		"block/nvme0n1/size":             "1953525168\n",
+		"block/sda/device/model":         "WDC WD40EFRX\n",
+		"block/sda/queue/rotational":     "1\n",
+		"block/sda/size":                 "7814037168\n",
+		"block/sdb/device/model":         "INTEL SSDSC2KB48\n",
+		"block/sdb/queue/rotational":     "0\n",
+		"block/sdb/size":                 "937703088\n",
+	})
+	defer restore()
+	disks, err := GetDisks()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []Disk{
+		{Name: "nvme0n1", Model: "Samsung SSD 970 EVO", Type: "nvme", Size: 1953525168 * 512},
+		{Name: "sda", Model: "WDC WD40EFRX", Type: "hdd", Size: 7814037168 * 512},
+		{Name: "sdb", Model: "INTEL SSDSC2KB48", Type: "ssd", Size: 937703088 * 512},
+	}
+	if len(disks) != len(want) {
+		t.Fatalf("got %d disks; want %d: %+v", len(disks), len(want), disks)
+	}
+	for i := range want {
+		if disks[i] != want[i] {
+			t.Errorf("%d: got %+v; want %+v", i, disks[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+// setDMIInfo sets the system vendor, product name, and BIOS information from
+// the DMI information in sysfs.  Values that aren't available, or were
+// already set by the platform's getSysInfo, are left alone.
+func (s *SysInfo) setDMIInfo() {
+	for _, v := range []struct {
+		path string
+		dst  *string
+	}{
+		{"class/dmi/id/sys_vendor", &s.SystemVendor},
+		{"class/dmi/id/product_name", &s.ProductName},
+		{"class/dmi/id/bios_vendor", &s.BIOSVendor},
+		{"class/dmi/id/bios_version", &s.BIOSVersion},
+		{"class/dmi/id/bios_date", &s.BIOSDate},
+	} {
+		if *v.dst != "" {
+			continue
+		}
+		*v.dst, _ = readSysfsValue(v.path)
+	}
+}
+
+// systemString returns the system vendor and product name, e.g. "Dell Inc.
+// PowerEdge R740".
+func (s *SysInfo) systemString() string {
+	if s.SystemVendor == "" || s.ProductName == "" {
+		return s.SystemVendor + s.ProductName
+	}
+	return s.SystemVendor + " " + s.ProductName
+}
+
+// biosString returns the BIOS vendor, version, and date, e.g. "Dell Inc.
+// 2.12.2 (07/09/2021)".  Whatever isn't known is left out.
+func (s *SysInfo) biosString() string {
+	b := s.BIOSVendor
+	if s.BIOSVersion != "" {
+		if b != "" {
+			b += " "
+		}
+		b += s.BIOSVersion
+	}
+	if s.BIOSDate != "" {
+		if b != "" {
+			b += " "
+		}
+		b += "(" + s.BIOSDate + ")"
+	}
+	return b
+}
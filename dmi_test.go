@@ -0,0 +1,27 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetDMIInfo(t *testing.T) {
+	restore := fakeSysfs(t, map[string]string{
+		"class/dmi/id/sys_vendor":   "Dell Inc.\n",
+		"class/dmi/id/product_name": "PowerEdge R740\n",
+		"class/dmi/id/bios_vendor":  "Dell Inc.\n",
+		"class/dmi/id/bios_version": "2.12.2\n",
+		"class/dmi/id/bios_date":    "07/09/2021\n",
+	})
+	defer restore()
+	var s SysInfo
+	s.setDMIInfo()
+	d := s.DetailedString()
+	if !strings.Contains(d, "System:     Dell Inc. PowerEdge R740\nBIOS:       Dell Inc. 2.12.2 (07/09/2021)\n") {
+		t.Errorf("got %q; want the system and BIOS info", d)
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "strings"
+
+// SetTitle sets the report's title; it's the first thing in the output: an
+// underlined line in txt, an H1 in md, and a comment record, e.g.
+// "# title", in csv, if CSVPreamble is set.
+func (b *Benches) SetTitle(s string) {
+	b.title = s
+}
+
+// SetCaption sets the table's caption; it's written just before the table:
+// a line in txt, an emphasized line in md, and a comment record in csv, if
+// CSVPreamble is set.
+func (b *Benches) SetCaption(s string) {
+	b.caption = s
+}
+
+// SetFooter sets the report's footer; it's the last thing in the output,
+// after the set's Note: a line in txt, a paragraph in md, and a comment
+// record in csv, if CSVPreamble is set.
+func (b *Benches) SetFooter(s string) {
+	b.footer = s
+}
+
+// underline returns s underlined with c.
+func underline(s string, c string) string {
+	return s + "\n" + strings.Repeat(c, len(s))
+}
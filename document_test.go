@@ -0,0 +1,63 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTitleCaptionFooter(t *testing.T) {
+	set := func(b Benchmarker) {
+		SetBenches(b, &Benches{Hostname: "host", Note: "note", Benchmarks: testBenches()[:1]})
+		d := b.(DocumentConfigurer)
+		d.SetTitle("Encoding")
+		d.SetCaption("ns per op, lower is better")
+		d.SetFooter("generated by benchutil")
+	}
+	var txt bytes.Buffer
+	s := NewStringBench(&txt)
+	set(s)
+	if err := s.Out(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.HasPrefix(txt.String(), "Encoding\n========\n\nHost:") {
+		t.Errorf("got %q; want the title first", txt.String())
+	}
+	if !strings.Contains(txt.String(), "\nns per op, lower is better\nGroup") {
+		t.Errorf("got %q; want the caption before the table", txt.String())
+	}
+	if !strings.HasSuffix(txt.String(), "\nnote\n\ngenerated by benchutil\n") {
+		t.Errorf("got %q; want the footer last", txt.String())
+	}
+
+	var md bytes.Buffer
+	m := NewMDBench(&md)
+	set(m)
+	if err := m.Out(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.HasPrefix(md.String(), "# Encoding\n\nHost: host") {
+		t.Errorf("got %q; want the title as an H1", md.String())
+	}
+	if !strings.Contains(md.String(), "_ns per op, lower is better_\n\n") {
+		t.Errorf("got %q; want the caption emphasized", md.String())
+	}
+	if !strings.HasSuffix(md.String(), "\nnote\n\ngenerated by benchutil\n") {
+		t.Errorf("got %q; want the footer last", md.String())
+	}
+
+	var csv bytes.Buffer
+	c := NewCSVBench(&csv, WithCSVPreamble())
+	set(c)
+	if err := c.Out(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := "# Encoding\nHost,host\n\n\"# ns per op, lower is better\"\nGroup,Name,Operations,Ns/Op,Bytes/Op,Allocs/Op\ngroup,a,1000,100,16,1\n\nNote,note\n\n# generated by benchutil\n"
+	if csv.String() != want {
+		t.Errorf("got %q; want %q", csv.String(), want)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"strconv"
+	"time"
+)
+
+// SetNsOp sets NsOp to d, e.g. to a time.Since of an op.
+func (r *Result) SetNsOp(d time.Duration) {
+	r.NsOp = d.Nanoseconds()
+}
+
+// NsOpDuration returns NsOp as a time.Duration.
+func (r Result) NsOpDuration() time.Duration {
+	return time.Duration(r.NsOp)
+}
+
+// ResultFromDuration creates a Result{} for ops operations that took a total
+// of elapsed, e.g. as measured with time.Since.
+func ResultFromDuration(ops int64, elapsed time.Duration) Result {
+	if ops < 1 {
+		return Result{}
+	}
+	return Result{Ops: ops, NsOp: elapsed.Nanoseconds() / ops}
+}
+
+// NsOpAsDuration: if true, the NsOp column is output as a duration, e.g.
+// "1.2ms" instead of "1234567"; see FormatDuration.  The column's
+// precision, see SetPrecision, sets the decimal places; the default is 1.
+func (b *Benches) NsOpAsDuration(v bool) {
+	b.nsOpDuration = v
+}
+
+// FormatDuration returns d in the largest unit, of ns, µs, ms, and s, that
+// it has at least 1 of, with the decimal places, e.g. "1.2ms".  Unlike
+// time.Duration's String, a duration of minutes or more is still in s.
+func FormatDuration(d time.Duration, digits int) string {
+	units := []struct {
+		d    time.Duration
+		unit string
+	}{
+		{time.Second, "s"},
+		{time.Millisecond, "ms"},
+		{time.Microsecond, "µs"},
+	}
+	abs := d
+	if abs < 0 {
+		abs = -abs
+	}
+	for _, u := range units {
+		if abs >= u.d {
+			return strconv.FormatFloat(float64(d)/float64(u.d), 'f', digits, 64) + u.unit
+		}
+	}
+	return strconv.FormatInt(int64(d), 10) + "ns"
+}
+
+// durationString returns the per op nanoseconds as a duration.  A bench
+// without iterations, e.g. one that failed, is treated as 1 iteration.
+func (b *Benches) durationString(v int64, it int) string {
+	if it < 1 {
+		it = 1
+	}
+	digits := 1
+	if p, ok := b.precision[NsOpColumn]; ok {
+		digits = p
+	}
+	return b.localize(FormatDuration(time.Duration(v/int64(it)), digits))
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d      time.Duration
+		digits int
+		want   string
+	}{
+		{0, 1, "0ns"},
+		{999, 1, "999ns"},
+		{1500, 1, "1.5µs"},
+		{1234567, 1, "1.2ms"},
+		{1234567, 3, "1.235ms"},
+		{90 * time.Second, 0, "90s"},
+		{-2 * time.Millisecond, 1, "-2.0ms"},
+	}
+	for _, test := range tests {
+		if got := FormatDuration(test.d, test.digits); got != test.want {
+			t.Errorf("%d: got %q; want %q", test.d, got, test.want)
+		}
+	}
+}
+
+func TestResultDuration(t *testing.T) {
+	var r Result
+	r.SetNsOp(1500 * time.Microsecond)
+	if r.NsOp != 1500000 || r.NsOpDuration() != 1500*time.Microsecond {
+		t.Errorf("got %d; want 1500000", r.NsOp)
+	}
+	r = ResultFromDuration(4, 10*time.Millisecond)
+	if r.Ops != 4 || r.NsOp != 2500000 {
+		t.Errorf("got %+v; want 4 ops of 2500000 ns", r)
+	}
+	if r := ResultFromDuration(0, time.Second); r != (Result{}) {
+		t.Errorf("got %+v; want an empty result", r)
+	}
+}
+
+func TestNsOpAsDuration(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewStringBench(&buf)
+	b.NsOpAsDuration(true)
+	SetBenches(b, &Benches{Benchmarks: []Bench{
+		{Name: "a", Iterations: 2, Result: Result{Ops: 10, NsOp: 3000}},
+		{Name: "b", Iterations: 1, Result: Result{Ops: 10, NsOp: 1234567}},
+	}})
+	b.Out()
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[2], "a      20    1.5µs") || !strings.HasPrefix(lines[3], "b      10    1.2ms") {
+		t.Errorf("got %q; want the ns/op as aligned durations", buf.String())
+	}
+	// a failed bench has no iterations.
+	buf.Reset()
+	b = NewStringBench(&buf, WithNsOpAsDuration())
+	b.Append(Bench{Name: "x", Err: "boom"})
+	err := b.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(buf.String(), "0ns") {
+		t.Errorf("got %q; want a 0ns duration", buf.String())
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+// Each calls f for each bench in the set, in order, with the bench's index.
+// The bench is a copy; changing it doesn't change the set.  Iteration stops
+// when f returns false.
+func (b *Benches) Each(f func(i int, v Bench) bool) {
+	for i, v := range b.Benchmarks {
+		if !f(i, v) {
+			return
+		}
+	}
+}
+
+// EachGroup calls f for each group in the set, in order, with the group's
+// name and a copy of its benches.  Like the sections in the output, a group
+// is a run of consecutive benches with the same Group; sort the set first if
+// a group's benches may not be together.  Iteration stops when f returns
+// false.
+func (b *Benches) EachGroup(f func(group string, benches []Bench) bool) {
+	for i := 0; i < len(b.Benchmarks); {
+		j := i + 1
+		for j < len(b.Benchmarks) && b.Benchmarks[j].Group == b.Benchmarks[i].Group {
+			j++
+		}
+		if !f(b.Benchmarks[i].Group, append([]Bench(nil), b.Benchmarks[i:j]...)) {
+			return
+		}
+		i = j
+	}
+}
+
+// Groups returns the names of the set's groups, in the order they first
+// appear.  Each name is only returned once.
+func (b *Benches) Groups() []string {
+	var groups []string
+	seen := map[string]bool{}
+	for _, v := range b.Benchmarks {
+		if seen[v.Group] {
+			continue
+		}
+		seen[v.Group] = true
+		groups = append(groups, v.Group)
+	}
+	return groups
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func groupedBenches() *Benches {
+	b := NewBenches()
+	for _, v := range [][2]string{{"json", "a"}, {"json", "b"}, {"xml", "c"}, {"json", "d"}} {
+		bench := NewBench(v[1])
+		bench.Group = v[0]
+		b.Append(bench)
+	}
+	return b
+}
+
+func TestEach(t *testing.T) {
+	b := groupedBenches()
+	var names []string
+	b.Each(func(i int, v Bench) bool {
+		if b.Benchmarks[i].Name != v.Name {
+			t.Errorf("%d: got %q; want %q", i, v.Name, b.Benchmarks[i].Name)
+		}
+		names = append(names, v.Name)
+		v.Name = "changed"
+		return i < 2
+	})
+	if !reflect.DeepEqual(names, []string{"a", "b", "c"}) {
+		t.Errorf("got %v; want [a b c]", names)
+	}
+	if b.Benchmarks[0].Name != "a" {
+		t.Errorf("got %q; want the bench to be unchanged", b.Benchmarks[0].Name)
+	}
+}
+
+func TestEachGroup(t *testing.T) {
+	b := groupedBenches()
+	var got [][]string
+	b.EachGroup(func(group string, benches []Bench) bool {
+		g := []string{group}
+		for _, v := range benches {
+			g = append(g, v.Name)
+		}
+		got = append(got, g)
+		benches[0].Name = "changed"
+		return true
+	})
+	want := [][]string{{"json", "a", "b"}, {"xml", "c"}, {"json", "d"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	if b.Benchmarks[0].Name != "a" {
+		t.Errorf("got %q; want the bench to be unchanged", b.Benchmarks[0].Name)
+	}
+
+	var n int
+	b.EachGroup(func(group string, benches []Bench) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("got %d calls; want 1", n)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	b := groupedBenches()
+	got := b.Groups()
+	if !reflect.DeepEqual(got, []string{"json", "xml"}) {
+		t.Errorf("got %v; want [json xml]", got)
+	}
+	if got := NewBenches().Groups(); got != nil {
+		t.Errorf("got %v; want nil", got)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "strings"
+
+// The word lists used to generate realistic looking data.  They are small;
+// the goal is data with a realistic shape, not realistic variety.
+var (
+	firstNames = []string{
+		"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda",
+		"William", "Elizabeth", "David", "Barbara", "Richard", "Susan", "Joseph", "Jessica",
+		"Thomas", "Sarah", "Charles", "Karen", "Wei", "Yuki", "Aarav", "Fatima",
+		"Mohammed", "Sofia", "Luca", "Olga", "Kwame", "Ana", "Mateo", "Ingrid",
+	}
+	lastNames = []string{
+		"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis",
+		"Rodriguez", "Martinez", "Hernandez", "Lopez", "Gonzalez", "Wilson", "Anderson", "Thomas",
+		"Taylor", "Moore", "Jackson", "Martin", "Lee", "Wang", "Kim", "Nguyen",
+		"Patel", "Singh", "Müller", "Rossi", "Ivanova", "Okafor", "Silva", "Larsen",
+	}
+	words = []string{
+		"the", "of", "and", "a", "to", "in", "is", "you", "that", "it",
+		"he", "was", "for", "on", "are", "as", "with", "his", "they", "at",
+		"be", "this", "have", "from", "or", "one", "had", "by", "word", "but",
+		"not", "what", "all", "were", "we", "when", "your", "can", "said", "there",
+		"use", "an", "each", "which", "she", "do", "how", "their", "if", "will",
+		"time", "system", "data", "network", "value", "result", "server", "request", "cache", "memory",
+		"quick", "brown", "fox", "jumps", "over", "lazy", "dog", "bright", "small", "large",
+	}
+	domains = []string{"example", "mail", "inbox", "company", "acme", "widgets", "cloud", "online"}
+	tlds    = []string{"com", "net", "org", "io", "dev", "co.uk", "de", "jp"}
+)
+
+// pick returns a pseudo-random element of s.
+func (g *Gen) pick(s []string) string {
+	return s[g.rng.Bound(uint32(len(s)))]
+}
+
+// RandName returns a pseudo-random full name, e.g. "Mary Garcia".
+func RandName() string {
+	return defaultGen.RandName()
+}
+
+// RandName returns a pseudo-random full name, e.g. "Mary Garcia".
+func (g *Gen) RandName() string {
+	return g.pick(firstNames) + " " + g.pick(lastNames)
+}
+
+// RandEmail returns a pseudo-random email address, e.g.
+// "mary.garcia42@mail.com".
+func RandEmail() string {
+	return defaultGen.RandEmail()
+}
+
+// RandEmail returns a pseudo-random email address, e.g.
+// "mary.garcia42@mail.com".
+func (g *Gen) RandEmail() string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(g.pick(firstNames)))
+	switch g.rng.Bound(3) {
+	case 0:
+		b.WriteByte('.')
+	case 1:
+		b.WriteByte('_')
+	}
+	b.WriteString(strings.ToLower(g.pick(lastNames)))
+	if g.RandBool() {
+		b.WriteString(g.RandStringFrom(CharsetDigits, 1+g.rng.Bound(3)))
+	}
+	b.WriteByte('@')
+	b.WriteString(g.pick(domains))
+	b.WriteByte('.')
+	b.WriteString(g.pick(tlds))
+	return b.String()
+}
+
+// RandURL returns a pseudo-random URL, e.g.
+// "https://www.acme.io/network/cache?id=8f3a".
+func RandURL() string {
+	return defaultGen.RandURL()
+}
+
+// RandURL returns a pseudo-random URL, e.g.
+// "https://www.acme.io/network/cache?id=8f3a".
+func (g *Gen) RandURL() string {
+	var b strings.Builder
+	if g.rng.Bound(4) == 0 {
+		b.WriteString("http://")
+	} else {
+		b.WriteString("https://")
+	}
+	if g.RandBool() {
+		b.WriteString("www.")
+	}
+	b.WriteString(g.pick(domains))
+	b.WriteByte('.')
+	b.WriteString(g.pick(tlds))
+	for i := uint32(0); i <= g.rng.Bound(3); i++ {
+		b.WriteByte('/')
+		b.WriteString(g.pick(words))
+	}
+	if g.RandBool() {
+		b.WriteString("?id=")
+		b.WriteString(g.RandStringFrom(CharsetHex, 4+g.rng.Bound(8)))
+	}
+	return b.String()
+}
+
+// RandSentence returns a pseudo-random sentence of n words, starting with a
+// capital letter and ending with a period.  If n is 0, the number of words
+// is pseudo-random, between 4 and 16.
+func RandSentence(n int) string {
+	return defaultGen.RandSentence(n)
+}
+
+// RandSentence returns a pseudo-random sentence of n words.  See the
+// RandSentence function.
+func (g *Gen) RandSentence(n int) string {
+	if n <= 0 {
+		n = 4 + int(g.rng.Bound(13))
+	}
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		w := g.pick(words)
+		if i == 0 {
+			w = strings.ToUpper(w[:1]) + w[1:]
+		} else {
+			b.WriteByte(' ')
+		}
+		b.WriteString(w)
+	}
+	b.WriteByte('.')
+	return b.String()
+}
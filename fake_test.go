@@ -0,0 +1,40 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"net/mail"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestFakeData(t *testing.T) {
+	g := NewGen(5)
+	for i := 0; i < 100; i++ {
+		if n := g.RandName(); len(strings.Fields(n)) != 2 {
+			t.Errorf("got name %q; want a first and last name", n)
+		}
+		if e := g.RandEmail(); !strings.Contains(e, "@") {
+			t.Errorf("got email %q; want an address", e)
+		} else if _, err := mail.ParseAddress(e); err != nil {
+			t.Errorf("got email %q: %s", e, err)
+		}
+		u, err := url.Parse(g.RandURL())
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			t.Errorf("got url %v, %v; want an absolute http(s) URL", u, err)
+		}
+		s := g.RandSentence(0)
+		if n := len(strings.Fields(s)); n < 4 || n > 16 {
+			t.Errorf("got sentence %q with %d words; want 4-16", s, n)
+		}
+		if !strings.HasSuffix(s, ".") || strings.ToUpper(s[:1]) != s[:1] {
+			t.Errorf("got sentence %q; want it capitalized and ending with a period", s)
+		}
+	}
+	if n := len(strings.Fields(RandSentence(7))); n != 7 {
+		t.Errorf("got %d words; want 7", n)
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The defaults Fill uses for fields that don't have a tag, or whose tag
+// doesn't set the value.
+const (
+	defaultFillLen = 8  // the length of slices and maps.
+	defaultFillStr = 16 // the length of strings and []byte.
+)
+
+// charsets are the named character sets a fill tag's charset can refer to.
+var charsets = map[string]string{
+	"alphanum":  CharsetAlphanum,
+	"digits":    CharsetDigits,
+	"hex":       CharsetHex,
+	"base64":    CharsetBase64,
+	"base64url": CharsetBase64URL,
+}
+
+// fillOpts are the options from a field's bench tag.
+type fillOpts struct {
+	min, max float64
+	hasRange bool
+	len      int
+	hasLen   bool
+	charset  string
+	skip     bool
+}
+
+// parseFillTag parses a bench struct tag, e.g. `bench:"min=1,max=100"`.  The
+// supported keys are min and max, for numbers, len, for strings, slices,
+// arrays, and maps, and charset, for strings and []byte.  A tag of "-" skips
+// the field.
+func parseFillTag(tag string) (fillOpts, error) {
+	var o fillOpts
+	if tag == "-" {
+		o.skip = true
+		return o, nil
+	}
+	if tag == "" {
+		return o, nil
+	}
+	var hasMin, hasMax bool
+	for _, kv := range strings.Split(tag, ",") {
+		i := strings.IndexByte(kv, '=')
+		if i < 0 {
+			return o, fmt.Errorf("bench tag: %q: missing value", kv)
+		}
+		k, v := strings.TrimSpace(kv[:i]), kv[i+1:]
+		var err error
+		switch k {
+		case "min":
+			o.min, err = strconv.ParseFloat(v, 64)
+			hasMin = true
+		case "max":
+			o.max, err = strconv.ParseFloat(v, 64)
+			hasMax = true
+		case "len":
+			o.len, err = strconv.Atoi(v)
+			if err == nil && o.len < 0 {
+				err = errors.New("must be >= 0")
+			}
+			o.hasLen = true
+		case "charset":
+			o.charset = v
+			if cs, ok := charsets[v]; ok {
+				o.charset = cs
+			}
+		default:
+			return o, fmt.Errorf("bench tag: unknown key %q", k)
+		}
+		if err != nil {
+			return o, fmt.Errorf("bench tag: %s: %s", k, err)
+		}
+	}
+	if hasMin != hasMax {
+		return o, errors.New("bench tag: min and max must be used together")
+	}
+	if hasMin && o.max < o.min {
+		return o, errors.New("bench tag: max must be >= min")
+	}
+	o.hasRange = hasMin
+	return o, nil
+}
+
+// Fill populates v, which must be a non-nil pointer, with pseudo-random
+// values.  Structs are filled field by field, recursively; nil pointers are
+// allocated and filled, except for pointers to a type that's already being
+// filled, e.g. a linked list node's next node, which are left nil.
+// Unexported fields are left alone.  Fields can be
+// configured with a bench tag:
+//
+//	Age   int     `bench:"min=18,max=99"`   // a value in [min, max]
+//	Score float64 `bench:"min=0,max=1"`     // a value in [min, max)
+//	ID    string  `bench:"len=32,charset=hex"`
+//	Tags  []string `bench:"len=3"`
+//	Skip  string  `bench:"-"`
+//
+// The charset is either one of alphanum, digits, hex, base64, or base64url,
+// or the characters to use; it can't contain a comma.  Without a tag,
+// integers span their type's range, floats are in [0, 1), strings and
+// []byte are 16 alphanumeric characters, and slices and maps have 8
+// elements.  Channels, funcs, and interfaces are left alone.
+func Fill(v interface{}) error {
+	return defaultGen.Fill(v)
+}
+
+// Fill populates v, which must be a non-nil pointer, with pseudo-random
+// values.  See the Fill function.
+func (g *Gen) Fill(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("fill: v must be a non-nil pointer")
+	}
+	// the type being filled is on the stack, so a pointer back to it is
+	// left nil.
+	return g.fillValue(rv.Elem(), fillOpts{}, map[reflect.Type]bool{rv.Type(): true})
+}
+
+// fillValue fills v.  stack has the pointer types that are being filled;
+// pointers of those types are left as they are so self-referential types,
+// e.g. a linked list's node, don't recurse forever.
+func (g *Gen) fillValue(v reflect.Value, o fillOpts, stack map[reflect.Type]bool) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(g.RandBool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if o.hasRange {
+			v.SetInt(g.int64Inclusive(int64(o.min), int64(o.max)))
+			return nil
+		}
+		bits := uint(v.Type().Bits())
+		v.SetInt(int64(g.uint64()) >> (64 - bits))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if o.hasRange {
+			if o.min < 0 {
+				return fmt.Errorf("fill: %s: min must be >= 0", v.Type())
+			}
+			v.SetUint(uint64(g.int64Inclusive(int64(o.min), int64(o.max))))
+			return nil
+		}
+		bits := uint(v.Type().Bits())
+		v.SetUint(g.uint64() >> (64 - bits))
+	case reflect.Float32, reflect.Float64:
+		if o.hasRange && o.max > o.min {
+			v.SetFloat(g.RandFloat64Range(o.min, o.max))
+			return nil
+		}
+		if o.hasRange {
+			v.SetFloat(o.min)
+			return nil
+		}
+		v.SetFloat(g.RandFloat64())
+	case reflect.String:
+		v.SetString(g.fillString(o))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 && !o.hasRange {
+			v.SetBytes([]byte(g.fillString(o)))
+			return nil
+		}
+		n := defaultFillLen
+		if o.hasLen {
+			n = o.len
+		}
+		s := reflect.MakeSlice(v.Type(), n, n)
+		for i := 0; i < n; i++ {
+			err := g.fillValue(s.Index(i), o.elem(), stack)
+			if err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			err := g.fillValue(v.Index(i), o.elem(), stack)
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		n := defaultFillLen
+		if o.hasLen {
+			n = o.len
+		}
+		m := reflect.MakeMapWithSize(v.Type(), n)
+		// duplicate keys are possible, e.g. with a bool key, so give up
+		// after a reasonable number of attempts.
+		for i := 0; m.Len() < n && i < 4*n; i++ {
+			k := reflect.New(v.Type().Key()).Elem()
+			err := g.fillValue(k, fillOpts{}, stack)
+			if err != nil {
+				return err
+			}
+			e := reflect.New(v.Type().Elem()).Elem()
+			err = g.fillValue(e, o.elem(), stack)
+			if err != nil {
+				return err
+			}
+			m.SetMapIndex(k, e)
+		}
+		v.Set(m)
+	case reflect.Ptr:
+		t := v.Type()
+		if stack[t] {
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		stack[t] = true
+		defer delete(stack, t)
+		return g.fillValue(v.Elem(), o, stack)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue // unexported
+			}
+			fo, err := parseFillTag(f.Tag.Get("bench"))
+			if err != nil {
+				return fmt.Errorf("fill: %s.%s: %s", t, f.Name, err)
+			}
+			if fo.skip {
+				continue
+			}
+			err = g.fillValue(v.Field(i), fo, stack)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// elem returns the options for the elements of a slice, array, or map: the
+// range and charset apply to the elements but the length doesn't.
+func (o fillOpts) elem() fillOpts {
+	o.hasLen = false
+	o.len = 0
+	return o
+}
+
+// fillString returns a string using the length and charset options.
+func (g *Gen) fillString(o fillOpts) string {
+	n := defaultFillStr
+	if o.hasLen {
+		n = o.len
+	}
+	if o.charset != "" {
+		return g.RandStringFrom(o.charset, uint32(n))
+	}
+	return g.RandString(uint32(n))
+}
+
+// int64Inclusive returns a pseudo-random int64 in [min, max].
+func (g *Gen) int64Inclusive(min, max int64) int64 {
+	if min == math.MinInt64 && max == math.MaxInt64 {
+		return int64(g.uint64())
+	}
+	return min + int64(g.uint64n(uint64(max)-uint64(min)+1))
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"strings"
+	"testing"
+)
+
+type fillInner struct {
+	Score float64 `bench:"min=1,max=2"`
+}
+
+type fillTest struct {
+	Age    int    `bench:"min=18,max=99"`
+	Count  uint8  `bench:"min=1,max=3"`
+	ID     string `bench:"len=32,charset=hex"`
+	Name   string
+	Data   []byte         `bench:"len=4"`
+	Tags   []string       `bench:"len=3,charset=xyz"`
+	Counts map[string]int `bench:"len=5,min=0,max=9"`
+	Inner  *fillInner
+	Arr    [2]fillInner
+	Skip   string `bench:"-"`
+	hidden string
+}
+
+func TestFill(t *testing.T) {
+	var v fillTest
+	v.Skip = "keep"
+	err := NewGen(9).Fill(&v)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if v.Age < 18 || v.Age > 99 {
+		t.Errorf("Age: got %d; want [18, 99]", v.Age)
+	}
+	if v.Count < 1 || v.Count > 3 {
+		t.Errorf("Count: got %d; want [1, 3]", v.Count)
+	}
+	if len(v.ID) != 32 || strings.Trim(v.ID, CharsetHex) != "" {
+		t.Errorf("ID: got %q; want 32 hex characters", v.ID)
+	}
+	if len(v.Name) != defaultFillStr {
+		t.Errorf("Name: got %q; want %d characters", v.Name, defaultFillStr)
+	}
+	if len(v.Data) != 4 {
+		t.Errorf("Data: got %d bytes; want 4", len(v.Data))
+	}
+	if len(v.Tags) != 3 || len(v.Tags[0]) != defaultFillStr || strings.Trim(v.Tags[0], "xyz") != "" {
+		t.Errorf("Tags: got %q; want 3 strings of x, y, and z", v.Tags)
+	}
+	if len(v.Counts) != 5 {
+		t.Errorf("Counts: got %d entries; want 5", len(v.Counts))
+	}
+	for k, n := range v.Counts {
+		if n < 0 || n > 9 {
+			t.Errorf("Counts[%q]: got %d; want [0, 9]", k, n)
+		}
+	}
+	if v.Inner == nil || v.Inner.Score < 1 || v.Inner.Score >= 2 {
+		t.Errorf("Inner: got %+v; want an allocated struct with a Score in [1, 2)", v.Inner)
+	}
+	if v.Arr[1].Score < 1 || v.Arr[1].Score >= 2 {
+		t.Errorf("Arr: got %+v; want Scores in [1, 2)", v.Arr)
+	}
+	if v.Skip != "keep" || v.hidden != "" {
+		t.Errorf("got Skip %q, hidden %q; want them left alone", v.Skip, v.hidden)
+	}
+}
+
+func TestFillErrors(t *testing.T) {
+	var v fillTest
+	if err := Fill(v); err == nil {
+		t.Error("got no error for a non-pointer; want one")
+	}
+	var bad struct {
+		N int `bench:"min=1"`
+	}
+	if err := Fill(&bad); err == nil {
+		t.Error("got no error for min without max; want one")
+	}
+	var negSlice struct {
+		S []int `bench:"len=-1"`
+	}
+	if err := Fill(&negSlice); err == nil {
+		t.Error("got no error for a negative slice len; want one")
+	}
+	var negString struct {
+		S string `bench:"len=-1"`
+	}
+	if err := Fill(&negString); err == nil {
+		t.Error("got no error for a negative string len; want one")
+	}
+}
+
+type fillNode struct {
+	V        int
+	Next     *fillNode
+	Children []*fillNode `bench:"len=2"`
+}
+
+func TestFillCycle(t *testing.T) {
+	var v fillNode
+	err := Fill(&v)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if v.Next != nil {
+		t.Errorf("got Next %+v; want the cyclic pointer left nil", v.Next)
+	}
+	var l struct{ Head *fillNode }
+	err = Fill(&l)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if l.Head == nil || l.Head.Next != nil || len(l.Head.Children) != 2 || l.Head.Children[0] != nil {
+		t.Errorf("got %+v; want the head filled and its nodes left nil", l.Head)
+	}
+}
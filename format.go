@@ -0,0 +1,111 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// formats are the registered output formats, by name.
+var formats = struct {
+	sync.RWMutex
+	m map[string]func(io.Writer) Benchmarker
+}{
+	m: map[string]func(io.Writer) Benchmarker{
+		"txt":  func(w io.Writer) Benchmarker { return NewStringBench(w) },
+		"csv":  func(w io.Writer) Benchmarker { return NewCSVBench(w) },
+		"md":   func(w io.Writer) Benchmarker { return NewMDBench(w) },
+		"html": func(w io.Writer) Benchmarker { return NewHTMLBench(w) },
+	},
+}
+
+// RegisterFormat makes an output format available by name, e.g. for
+// config outputs and the benchutil command.  The txt, csv, md, and html
+// formats are registered by default.  It's meant to be called from an init func;
+// if the name is already registered or factory is nil, it panics.
+func RegisterFormat(name string, factory func(io.Writer) Benchmarker) {
+	formats.Lock()
+	defer formats.Unlock()
+	if factory == nil {
+		panic("benchutil: RegisterFormat factory is nil")
+	}
+	if _, ok := formats.m[name]; ok {
+		panic("benchutil: RegisterFormat called twice for format " + name)
+	}
+	formats.m[name] = factory
+}
+
+// NewFormat returns a Benchmarker, of the named format, that writes to w.
+// The options are applied if the Benchmarker embeds Benches.
+func NewFormat(name string, w io.Writer, opts ...Option) (Benchmarker, error) {
+	formats.RLock()
+	f, ok := formats.m[name]
+	formats.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("benchutil: unknown format %q", name)
+	}
+	b := f(w)
+	if d, ok := b.(interface{ benches() *Benches }); ok && d.benches() != nil {
+		d.benches().apply(opts)
+	}
+	return b, nil
+}
+
+// HasFormat returns whether or not the format is registered.
+func HasFormat(name string) bool {
+	formats.RLock()
+	defer formats.RUnlock()
+	_, ok := formats.m[name]
+	return ok
+}
+
+// Formats returns the names of the registered formats, sorted.
+func Formats() []string {
+	formats.RLock()
+	defer formats.RUnlock()
+	names := make([]string, 0, len(formats.m))
+	for k := range formats.m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// benches returns b; it's promoted to the Benchmarkers that embed Benches
+// so SetBenches can reach them.
+func (b *Benches) benches() *Benches {
+	return b
+}
+
+// SetBenches sets the Benchmarker's benchmarks and run information, e.g.
+// its Hostname, Timestamp, Git, and Meta, to src's; the Benchmarker's output
+// settings aren't changed.  This is how saved results, e.g. parsed from go
+// test output, are written using a Benchmarker from NewFormat.  The
+// Benchmarker must embed Benches; if it doesn't, an error is returned.
+func SetBenches(dst Benchmarker, src *Benches) error {
+	d, ok := dst.(interface{ benches() *Benches })
+	if !ok {
+		return fmt.Errorf("benchutil: %T doesn't embed Benches", dst)
+	}
+	b := d.benches()
+	if b == nil {
+		return fmt.Errorf("benchutil: %T doesn't embed Benches", dst)
+	}
+	b.Name = src.Name
+	b.Desc = src.Desc
+	b.Note = src.Note
+	b.Benchmarks = src.Benchmarks
+	b.Warnings = src.Warnings
+	b.SysInfo = src.SysInfo
+	b.Hostname = src.Hostname
+	b.Timestamp = src.Timestamp
+	b.Git = src.Git
+	b.Env = src.Env
+	b.Meta = src.Meta
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countBench is a custom format that writes the number of benchmarks.
+type countBench struct {
+	Benches
+	w io.Writer
+}
+
+func (c *countBench) Out() error {
+	_, err := fmt.Fprintf(c.w, "%d benchmarks on %s\n", len(c.Benchmarks), c.Hostname)
+	return err
+}
+
+func TestRegisterFormat(t *testing.T) {
+	RegisterFormat("count", func(w io.Writer) Benchmarker { return &countBench{w: w} })
+	if !HasFormat("count") {
+		t.Error("got false; want count registered")
+	}
+	if got, want := strings.Join(Formats(), ","), "count,csv,html,md,txt"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+	var buf bytes.Buffer
+	b, err := NewFormat("count", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := NewBenches()
+	src.Hostname = "test"
+	src.Timestamp = time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	src.Append(Bench{Name: "a"}, Bench{Name: "b"})
+	err = SetBenches(b, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.Out()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "2 benchmarks on test\n" {
+		t.Errorf("got %q", buf.String())
+	}
+	_, err = NewFormat("nope", &buf)
+	if err == nil {
+		t.Error("got no error for an unknown format")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("got no panic for a duplicate format")
+			}
+		}()
+		RegisterFormat("csv", func(w io.Writer) Benchmarker { return NewCSVBench(w) })
+	}()
+}
+
+func TestSetBenches(t *testing.T) {
+	var buf bytes.Buffer
+	b, err := NewFormat("csv", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.IncludeOpsColumnDesc(true)
+	src := &Benches{Hostname: "test", Timestamp: time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)}
+	src.Append(Bench{Name: "a", Iterations: 1, Result: Result{Ops: 1, NsOp: 5}})
+	err = SetBenches(b, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.Out()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the output settings are kept and the source's header-less Benches don't
+	// affect it.
+	if !strings.Contains(buf.String(), "Name,Operations") || !strings.Contains(buf.String(), "5 ns/op") {
+		t.Errorf("got %q", buf.String())
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "fmt"
+
+// forward is embedded by the Benchmarkers that wrap another one, e.g.
+// BufferedBench, so the wrapped Benchmarker's optional interfaces, e.g.
+// Annotator, aren't hidden by the wrapper.  If the wrapped Benchmarker
+// doesn't implement the interface, the setters do nothing.
+type forward struct {
+	b Benchmarker
+}
+
+// wrapped returns the wrapped Benchmarker.
+func (f forward) wrapped() Benchmarker {
+	return f.b
+}
+
+// AddWarning adds a warning to the wrapped Benchmarker; see Annotator.
+func (f forward) AddWarning(s string) {
+	if a, ok := f.b.(Annotator); ok {
+		a.AddWarning(s)
+	}
+}
+
+// SetMeta sets a key value pair on the wrapped Benchmarker; see Annotator.
+func (f forward) SetMeta(key, value string) {
+	if a, ok := f.b.(Annotator); ok {
+		a.SetMeta(key, value)
+	}
+}
+
+// IncludeGPUInfo sets whether or not the wrapped Benchmarker's system info
+// includes the GPUs; see DeviceInfoConfigurer.
+func (f forward) IncludeGPUInfo(v bool) {
+	if d, ok := f.b.(DeviceInfoConfigurer); ok {
+		d.IncludeGPUInfo(v)
+	}
+}
+
+// IncludeDiskInfo sets whether or not the wrapped Benchmarker's system info
+// includes the block devices; see DeviceInfoConfigurer.
+func (f forward) IncludeDiskInfo(v bool) {
+	if d, ok := f.b.(DeviceInfoConfigurer); ok {
+		d.IncludeDiskInfo(v)
+	}
+}
+
+// Info returns the wrapped Benchmarker's system info; see InfoProvider.  If
+// it doesn't provide it, an error is returned.
+func (f forward) Info() (*SysInfo, error) {
+	p, ok := f.b.(InfoProvider)
+	if !ok {
+		return nil, fmt.Errorf("benchutil: %T doesn't provide the system info", f.b)
+	}
+	return p.Info()
+}
+
+// HideColumns hides the wrapped Benchmarker's columns; see ColumnSelector.
+func (f forward) HideColumns(cols ...string) {
+	if c, ok := f.b.(ColumnSelector); ok {
+		c.HideColumns(cols...)
+	}
+}
+
+// ForceColumn forces, or stops forcing, one of the wrapped Benchmarker's
+// columns; see ColumnSelector.
+func (f forward) ForceColumn(col string, v bool) {
+	if c, ok := f.b.(ColumnSelector); ok {
+		c.ForceColumn(col, v)
+	}
+}
+
+// SetRowFormatter sets the wrapped Benchmarker's RowFormatter; see
+// RowFormatterSetter.
+func (f forward) SetRowFormatter(rf RowFormatter) {
+	if r, ok := f.b.(RowFormatterSetter); ok {
+		r.SetRowFormatter(rf)
+	}
+}
+
+// SetTitle sets the wrapped Benchmarker's title; see DocumentConfigurer.
+func (f forward) SetTitle(s string) {
+	if d, ok := f.b.(DocumentConfigurer); ok {
+		d.SetTitle(s)
+	}
+}
+
+// SetCaption sets the wrapped Benchmarker's caption; see
+// DocumentConfigurer.
+func (f forward) SetCaption(s string) {
+	if d, ok := f.b.(DocumentConfigurer); ok {
+		d.SetCaption(s)
+	}
+}
+
+// SetFooter sets the wrapped Benchmarker's footer; see DocumentConfigurer.
+func (f forward) SetFooter(s string) {
+	if d, ok := f.b.(DocumentConfigurer); ok {
+		d.SetFooter(s)
+	}
+}
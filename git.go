@@ -0,0 +1,115 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"runtime/debug"
+	"strings"
+)
+
+// GitInfo holds information about the version of the code that was
+// benchmarked.
+type GitInfo struct {
+	Commit string `json:"commit"`           // the commit hash.
+	Branch string `json:"branch,omitempty"` // the branch; empty if HEAD is detached.
+	Tag    string `json:"tag,omitempty"`    // the tag pointing at the commit; if there is one.
+	Dirty  bool   `json:"dirty"`            // whether or not the working tree had uncommitted changes.
+}
+
+// String returns the commit, with its branch and tag and, if applicable,
+// dirty flag, e.g. "3f7a2c1 (master, v1.2.0, dirty)".
+func (g *GitInfo) String() string {
+	var extra []string
+	for _, v := range []string{g.Branch, g.Tag} {
+		if v != "" {
+			extra = append(extra, v)
+		}
+	}
+	if g.Dirty {
+		extra = append(extra, "dirty")
+	}
+	if len(extra) == 0 {
+		return g.Commit
+	}
+	return g.Commit + " (" + strings.Join(extra, ", ") + ")"
+}
+
+// GetGitInfo gets the git information of the repository that dir is in;
+// an empty dir is the current working directory.  If git isn't available,
+// or dir isn't in a repository, the vcs information from the binary's build
+// info is used; if there isn't any, an error is returned.
+func GetGitInfo(dir string) (*GitInfo, error) {
+	commit, err := git(dir, "rev-parse", "HEAD")
+	if err != nil {
+		g, ok := buildGitInfo()
+		if !ok {
+			return nil, err
+		}
+		return g, nil
+	}
+	g := &GitInfo{Commit: commit}
+	// these are best effort; a detached HEAD has no branch and most commits
+	// aren't tagged.
+	g.Branch, _ = git(dir, "symbolic-ref", "--short", "-q", "HEAD")
+	g.Tag, _ = git(dir, "describe", "--tags", "--exact-match", "HEAD")
+	status, err := git(dir, "status", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	g.Dirty = status != ""
+	return g, nil
+}
+
+// git runs the git command, in dir, and returns its trimmed output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if stderr.Len() > 0 {
+			return "", errors.New("git: " + strings.TrimSpace(stderr.String()))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// buildGitInfo returns the vcs information stamped into the binary by the
+// go command.
+func buildGitInfo() (*GitInfo, bool) {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil, false
+	}
+	var g GitInfo
+	for _, v := range bi.Settings {
+		switch v.Key {
+		case "vcs.revision":
+			g.Commit = v.Value
+		case "vcs.modified":
+			g.Dirty = v.Value == "true"
+		}
+	}
+	if g.Commit == "" {
+		return nil, false
+	}
+	return &g, true
+}
+
+// CaptureGitInfo gets the git information of the repository that dir is in
+// and adds it to the output's header.
+func (b *Benches) CaptureGitInfo(dir string) error {
+	g, err := GetGitInfo(dir)
+	if err != nil {
+		return err
+	}
+	b.Git = g
+	return nil
+}
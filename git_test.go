@@ -0,0 +1,64 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitInfoString(t *testing.T) {
+	tests := []struct {
+		g    GitInfo
+		want string
+	}{
+		{GitInfo{Commit: "abc"}, "abc"},
+		{GitInfo{Commit: "abc", Branch: "master"}, "abc (master)"},
+		{GitInfo{Commit: "abc", Branch: "master", Tag: "v1.0.0", Dirty: true}, "abc (master, v1.0.0, dirty)"},
+	}
+	for _, test := range tests {
+		if got := test.g.String(); got != test.want {
+			t.Errorf("got %q; want %q", got, test.want)
+		}
+	}
+}
+
+func TestGetGitInfo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir, err := ioutil.TempDir("", "benchutil")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "work"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"tag", "v0.1.0"},
+	} {
+		if _, err := git(dir, args...); err != nil {
+			t.Skipf("git %v: %s", args, err)
+		}
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "new"), []byte("x"), 0644)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var b Benches
+	err = b.CaptureGitInfo(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(b.Git.Commit) != 40 {
+		t.Errorf("got commit %q; want a hash", b.Git.Commit)
+	}
+	if b.Git.Branch != "work" || b.Git.Tag != "v0.1.0" || !b.Git.Dirty {
+		t.Errorf("got %s; want branch work, tag v0.1.0, dirty", b.Git)
+	}
+}
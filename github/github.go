@@ -0,0 +1,185 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+// Package github publishes benchmark reports to GitHub: as a pull request
+// comment, as a file in a gist, or as a check run with an annotation for
+// each regression.
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/mohae/benchutil"
+	"github.com/mohae/benchutil/history"
+)
+
+// DefaultAPIURL is the GitHub API's URL.
+const DefaultAPIURL = "https://api.github.com"
+
+// maxText is the most text a check run's output can have.
+const maxText = 65535
+
+// maxAnnotations is the most annotations a check run request can have.
+const maxAnnotations = 50
+
+// Publisher publishes reports to a repository using a token.
+type Publisher struct {
+	Token  string       // the token, e.g. GITHUB_TOKEN in Actions.
+	Owner  string       // the repository's owner.
+	Repo   string       // the repository's name.
+	APIURL string       // the API's URL; default is DefaultAPIURL.  Set it for GitHub Enterprise.
+	Client *http.Client // the client used; default is http.DefaultClient.
+	// CheckName is the name of the check runs; default is "benchmarks".
+	CheckName string
+	// AnnotationPath is the file regression annotations are attached to;
+	// GitHub requires one.  Default is "go.mod".
+	AnnotationPath string
+}
+
+// Markdown returns the benches' Markdown report, using the benches'
+// settings.
+func Markdown(b *benchutil.Benches) (string, error) {
+	var buf bytes.Buffer
+	m := benchutil.NewMDBench(&buf)
+	m.Benches = *b
+	err := m.Out()
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// CommentPR posts the benches' Markdown report as a comment on the pull
+// request.
+func (p *Publisher) CommentPR(number int, b *benchutil.Benches) error {
+	report, err := Markdown(b)
+	if err != nil {
+		return err
+	}
+	return p.do("POST", fmt.Sprintf("/repos/%s/%s/issues/%d/comments", p.Owner, p.Repo, number),
+		map[string]string{"body": report}, nil)
+}
+
+// UpdateGist writes the benches' Markdown report to the file in the gist,
+// replacing its contents; the gist's other files aren't changed.
+func (p *Publisher) UpdateGist(id, filename string, b *benchutil.Benches) error {
+	report, err := Markdown(b)
+	if err != nil {
+		return err
+	}
+	return p.do("PATCH", "/gists/"+id, map[string]interface{}{
+		"files": map[string]interface{}{filename: map[string]string{"content": report}},
+	}, nil)
+}
+
+// CheckRun creates a completed check run on the commit with the benches'
+// Markdown report as its output and returns its ID.  Each regression is a
+// warning annotation; if there are any, the check's conclusion is
+// "failure", otherwise it's "success".  Only the first 50 regressions are
+// annotated.
+func (p *Publisher) CheckRun(headSHA string, b *benchutil.Benches, regressions []history.Regression) (int64, error) {
+	report, err := Markdown(b)
+	if err != nil {
+		return 0, err
+	}
+	if len(report) > maxText {
+		report = report[:maxText]
+	}
+	name := p.CheckName
+	if name == "" {
+		name = "benchmarks"
+	}
+	path := p.AnnotationPath
+	if path == "" {
+		path = "go.mod"
+	}
+	title := fmt.Sprintf("%d benchmarks", len(b.Benchmarks))
+	conclusion := "success"
+	if len(regressions) > 0 {
+		title = fmt.Sprintf("%d regressions in %d benchmarks", len(regressions), len(b.Benchmarks))
+		conclusion = "failure"
+	}
+	var summary strings.Builder
+	for _, r := range regressions {
+		fmt.Fprintf(&summary, "- %s\n", r)
+	}
+	if summary.Len() == 0 {
+		summary.WriteString("No regressions.")
+	}
+	annotations := []map[string]interface{}{}
+	for i, r := range regressions {
+		if i == maxAnnotations {
+			break
+		}
+		annotations = append(annotations, map[string]interface{}{
+			"path":             path,
+			"start_line":       1,
+			"end_line":         1,
+			"annotation_level": "warning",
+			"title":            r.Name,
+			"message":          r.String(),
+		})
+	}
+	var resp struct {
+		ID int64 `json:"id"`
+	}
+	err = p.do("POST", fmt.Sprintf("/repos/%s/%s/check-runs", p.Owner, p.Repo), map[string]interface{}{
+		"name":       name,
+		"head_sha":   headSHA,
+		"status":     "completed",
+		"conclusion": conclusion,
+		"output": map[string]interface{}{
+			"title":       title,
+			"summary":     summary.String(),
+			"text":        report,
+			"annotations": annotations,
+		},
+	}, &resp)
+	return resp.ID, err
+}
+
+// do makes the API request with the body as JSON.  If v isn't nil, the
+// response is decoded into it.
+func (p *Publisher) do(method, path string, body, v interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	api := p.APIURL
+	if api == "" {
+		api = DefaultAPIURL
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(api, "/")+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	if p.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("github: %s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package github
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mohae/benchutil"
+	"github.com/mohae/benchutil/history"
+)
+
+type request struct {
+	method, path, auth string
+	body               map[string]interface{}
+}
+
+func testServer(t *testing.T) (*httptest.Server, *[]request) {
+	var reqs []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{method: r.Method, path: r.URL.Path, auth: r.Header.Get("Authorization")}
+		json.NewDecoder(r.Body).Decode(&req.body)
+		reqs = append(reqs, req)
+		if strings.HasPrefix(r.URL.Path, "/gists/missing") {
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 42}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &reqs
+}
+
+func testBenches() *benchutil.Benches {
+	m := benchutil.NewMDBench(ioutil.Discard)
+	m.Append(benchutil.Bench{Group: "json", Name: "decode", Iterations: 1, Result: benchutil.Result{Ops: 10, NsOp: 100}})
+	return &m.Benches
+}
+
+func TestPublisher(t *testing.T) {
+	srv, reqs := testServer(t)
+	p := &Publisher{Token: "secret", Owner: "mohae", Repo: "benchutil", APIURL: srv.URL}
+	b := testBenches()
+
+	err := p.CommentPR(7, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := (*reqs)[0]
+	if r.method != "POST" || r.path != "/repos/mohae/benchutil/issues/7/comments" || r.auth != "Bearer secret" {
+		t.Errorf("got %s %s %q", r.method, r.path, r.auth)
+	}
+	if body, _ := r.body["body"].(string); !strings.Contains(body, "decode") {
+		t.Errorf("got %v; want the markdown report", r.body)
+	}
+
+	err = p.UpdateGist("abc", "bench.md", b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = (*reqs)[1]
+	if r.method != "PATCH" || r.path != "/gists/abc" {
+		t.Errorf("got %s %s", r.method, r.path)
+	}
+	if _, ok := r.body["files"].(map[string]interface{})["bench.md"]; !ok {
+		t.Errorf("got %v; want bench.md", r.body)
+	}
+	err = p.UpdateGist("missing", "bench.md", b)
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("got %v; want a 404 error", err)
+	}
+
+	regs := []history.Regression{{Name: "json/decode", Metric: history.NsOp, Baseline: 50, Value: 100, Change: 1}}
+	id, err := p.CheckRun("3f7a2c1", b, regs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 42 {
+		t.Errorf("got id %d; want 42", id)
+	}
+	r = (*reqs)[3]
+	if r.path != "/repos/mohae/benchutil/check-runs" || r.body["head_sha"] != "3f7a2c1" || r.body["conclusion"] != "failure" || r.body["name"] != "benchmarks" {
+		t.Errorf("got %s %v", r.path, r.body)
+	}
+	out := r.body["output"].(map[string]interface{})
+	annotations := out["annotations"].([]interface{})
+	if len(annotations) != 1 || annotations[0].(map[string]interface{})["path"] != "go.mod" {
+		t.Errorf("got %v; want 1 annotation on go.mod", annotations)
+	}
+	if out["title"] != "1 regressions in 1 benchmarks" {
+		t.Errorf("got title %q", out["title"])
+	}
+
+	_, err = p.CheckRun("3f7a2c1", b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := (*reqs)[4].body["conclusion"]; c != "success" {
+		t.Errorf("got %v; want success", c)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GPU holds information about a GPU.
+type GPU struct {
+	Model  string `json:"model"`            // the GPU's name, or its PCI vendor:device id if the name isn't known.
+	VRAM   uint64 `json:"vram,omitempty"`   // the GPU's memory, in bytes; 0 if unknown.
+	Driver string `json:"driver,omitempty"` // the driver, and its version if known.
+}
+
+// nvidiaSMI is the nvidia-smi command; it's a var so it can be changed for
+// testing.
+var nvidiaSMI = "nvidia-smi"
+
+// GetGPUs enumerates the system's GPUs.  NVIDIA GPUs are queried using
+// nvidia-smi, if it's available; otherwise the GPUs are enumerated from the
+// DRM devices in sysfs.  If no GPUs are found, nil is returned.
+func GetGPUs() ([]GPU, error) {
+	gpus, err := nvidiaGPUs()
+	if err == nil && len(gpus) > 0 {
+		return gpus, nil
+	}
+	return drmGPUs()
+}
+
+// nvidiaGPUs gets the GPU information from nvidia-smi.
+func nvidiaGPUs() ([]GPU, error) {
+	if _, err := exec.LookPath(nvidiaSMI); err != nil {
+		return nil, err
+	}
+	out, err := exec.Command(nvidiaSMI, "--query-gpu=name,memory.total,driver_version", "--format=csv,noheader,nounits").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseNvidiaSMI(out)
+}
+
+// parseNvidiaSMI parses nvidia-smi's CSV output; memory.total is in MiB.
+func parseNvidiaSMI(out []byte) ([]GPU, error) {
+	r := csv.NewReader(bytes.NewReader(out))
+	r.TrimLeadingSpace = true
+	recs, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var gpus []GPU
+	for _, rec := range recs {
+		if len(rec) != 3 {
+			continue
+		}
+		g := GPU{Model: rec[0], Driver: "nvidia " + rec[2]}
+		mib, err := strconv.ParseUint(rec[1], 10, 64)
+		if err == nil {
+			g.VRAM = mib << 20
+		}
+		gpus = append(gpus, g)
+	}
+	return gpus, nil
+}
+
+// drmGPUs enumerates the GPUs from /sys/class/drm.  The model is the PCI
+// vendor:device id; VRAM is only available for drivers that report it, e.g.
+// amdgpu.
+func drmGPUs() ([]GPU, error) {
+	cards, err := filepath.Glob(filepath.Join(sysfs, "class/drm/card[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(cards)
+	var gpus []GPU
+	for _, c := range cards {
+		// skip the connectors, e.g. card0-HDMI-A-1
+		if strings.Contains(filepath.Base(c), "-") {
+			continue
+		}
+		rel, err := filepath.Rel(sysfs, filepath.Join(c, "device"))
+		if err != nil {
+			continue
+		}
+		vendor, ok := readSysfsValue(filepath.Join(rel, "vendor"))
+		if !ok {
+			continue
+		}
+		device, _ := readSysfsValue(filepath.Join(rel, "device"))
+		g := GPU{Model: strings.TrimPrefix(vendor, "0x") + ":" + strings.TrimPrefix(device, "0x")}
+		if v, ok := readSysfsValue(filepath.Join(rel, "mem_info_vram_total")); ok {
+			g.VRAM, _ = strconv.ParseUint(v, 10, 64)
+		}
+		if d, err := os.Readlink(filepath.Join(c, "device/driver")); err == nil {
+			g.Driver = filepath.Base(d)
+		}
+		gpus = append(gpus, g)
+	}
+	return gpus, nil
+}
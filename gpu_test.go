@@ -0,0 +1,43 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "testing"
+
+func TestParseNvidiaSMI(t *testing.T) {
+	out := []byte("Tesla V100-SXM2-16GB, 16160, 470.57.02\nNVIDIA A100-SXM4-40GB, 40536, 470.57.02\n")
+	gpus, err := parseNvidiaSMI(out)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(gpus) != 2 {
+		t.Fatalf("got %d gpus; want 2", len(gpus))
+	}
+	want := GPU{Model: "Tesla V100-SXM2-16GB", VRAM: 16160 << 20, Driver: "nvidia 470.57.02"}
+	if gpus[0] != want {
+		t.Errorf("got %+v; want %+v", gpus[0], want)
+	}
+}
+
+func TestDRMGPUs(t *testing.T) {
+	restore := fakeSysfs(t, map[string]string{
+		"class/drm/card0/device/vendor":              "0x1002\n",
+		"class/drm/card0/device/device":              "0x73bf\n",
+		"class/drm/card0/device/mem_info_vram_total": "17163091968\n",
+		"class/drm/card0-DP-1/status":                "connected\n",
+	})
+	defer restore()
+	gpus, err := drmGPUs()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(gpus) != 1 {
+		t.Fatalf("got %d gpus; want 1", len(gpus))
+	}
+	want := GPU{Model: "1002:73bf", VRAM: 17163091968}
+	if gpus[0] != want {
+		t.Errorf("got %+v; want %+v", gpus[0], want)
+	}
+}
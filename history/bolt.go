@@ -0,0 +1,178 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/mohae/benchutil"
+	bolt "go.etcd.io/bbolt"
+)
+
+// The bolt buckets.  The runs and run info are keyed by the run's ID; the
+// results are keyed by the run's ID and the bench's index in the run, so
+// iterating them is in run order.
+var (
+	runsBucket    = []byte("runs")
+	runInfoBucket = []byte("runinfo")
+	resultsBucket = []byte("results")
+)
+
+// BoltStore is a Store backed by a bbolt database.  Unlike SQLiteStore, it
+// doesn't need cgo.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// boltResult is a result as it's stored in the results bucket.
+type boltResult struct {
+	ID    string
+	Bench benchutil.Bench
+}
+
+// NewBoltStore returns a BoltStore that uses db, creating its buckets if
+// they don't exist.  Closing the store closes the db.
+func NewBoltStore(db *bolt.DB) (*BoltStore, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{runsBucket, runInfoBucket, resultsBucket} {
+			_, err := tx.CreateBucketIfNotExists(name)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// SaveRun implements Store.
+func (s *BoltStore) SaveRun(b *benchutil.Benches) (int64, error) {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return 0, err
+	}
+	var id int64
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(runsBucket)
+		seq, err := runs.NextSequence()
+		if err != nil {
+			return err
+		}
+		id = int64(seq)
+		key := boltKey(id)
+		err = runs.Put(key, data)
+		if err != nil {
+			return err
+		}
+		inf, err := json.Marshal(runInfo(id, b))
+		if err != nil {
+			return err
+		}
+		err = tx.Bucket(runInfoBucket).Put(key, inf)
+		if err != nil {
+			return err
+		}
+		results := tx.Bucket(resultsBucket)
+		for i, bench := range b.Benchmarks {
+			v, err := json.Marshal(boltResult{ID: bench.ID(), Bench: bench})
+			if err != nil {
+				return err
+			}
+			k := make([]byte, 12)
+			copy(k, key)
+			binary.BigEndian.PutUint32(k[8:], uint32(i))
+			err = results.Put(k, v)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ListRuns implements Store.
+func (s *BoltStore) ListRuns() ([]RunInfo, error) {
+	var runs []RunInfo
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(runInfoBucket).Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var inf RunInfo
+			err := json.Unmarshal(v, &inf)
+			if err != nil {
+				return err
+			}
+			runs = append(runs, inf)
+		}
+		return nil
+	})
+	return runs, err
+}
+
+// LoadRun implements Store.
+func (s *BoltStore) LoadRun(id int64) (*benchutil.Benches, error) {
+	var b *benchutil.Benches
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(runsBucket).Get(boltKey(id))
+		if v == nil {
+			return ErrNotFound
+		}
+		b = benchutil.NewBenches()
+		return json.Unmarshal(v, b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Results implements Store.  Every stored result is checked, so this takes
+// time proportional to the size of the history.
+func (s *BoltStore) Results(name string) ([]Result, error) {
+	var results []Result
+	err := s.db.View(func(tx *bolt.Tx) error {
+		infos := tx.Bucket(runInfoBucket)
+		var inf RunInfo
+		return tx.Bucket(resultsBucket).ForEach(func(k, v []byte) error {
+			var r boltResult
+			err := json.Unmarshal(v, &r)
+			if err != nil {
+				return err
+			}
+			if r.ID != name {
+				return nil
+			}
+			id := int64(binary.BigEndian.Uint64(k[:8]))
+			if inf.ID != id {
+				err = json.Unmarshal(infos.Get(k[:8]), &inf)
+				if err != nil {
+					return err
+				}
+			}
+			results = append(results, Result{RunID: id, Timestamp: inf.Timestamp, Bench: r.Bench})
+			return nil
+		})
+	})
+	return results, err
+}
+
+// Close implements Store; it closes the database.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// boltKey returns the key for the run's ID.
+func boltKey(id int64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(id))
+	return k
+}
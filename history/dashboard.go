@@ -0,0 +1,280 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package history
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mohae/benchutil"
+)
+
+// Serve serves a dashboard of the store's history on addr; see Handler.  It
+// only returns if there's an error.
+func Serve(addr string, s Store) error {
+	return http.ListenAndServe(addr, Handler(s))
+}
+
+// Handler returns a handler for a dashboard of the store's history.  The
+// dashboard has these pages:
+//
+//	/                 the runs, newest first.
+//	/run?id=N         the benchmarks in a run.
+//	/bench?name=X     a benchmark's trend across runs; X is its ID, e.g.
+//	                  json/decode/small.
+//	/compare?a=N&b=M  the benchmarks in two runs side by side.
+func Handler(s Store) http.Handler {
+	d := &dashboard{s: s}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", d.runs)
+	mux.HandleFunc("/run", d.run)
+	mux.HandleFunc("/bench", d.bench)
+	mux.HandleFunc("/compare", d.compare)
+	return mux
+}
+
+type dashboard struct {
+	s Store
+}
+
+func (d *dashboard) runs(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	runs, err := d.s.ListRuns()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	d.render(w, "runs", runs)
+}
+
+func (d *dashboard) run(w http.ResponseWriter, r *http.Request) {
+	id, ok := runID(w, r, "id")
+	if !ok {
+		return
+	}
+	b, err := d.s.LoadRun(id)
+	if err != nil {
+		loadError(w, err)
+		return
+	}
+	rows := make([]runRow, len(b.Benchmarks))
+	for i, bench := range b.Benchmarks {
+		rows[i] = runRow{
+			Bench:    bench,
+			Ops:      Ops.Value(bench),
+			NsOp:     NsOp.Value(bench),
+			BytesOp:  BytesOp.Value(bench),
+			AllocsOp: AllocsOp.Value(bench),
+		}
+	}
+	d.render(w, "run", struct {
+		Run
+		Rows []runRow
+	}{NewRun(id, b), rows})
+}
+
+// runRow is a row of a run's page; the values are the metrics', so they
+// are per op, and the ops are totaled, over the bench's iterations.
+type runRow struct {
+	benchutil.Bench
+	Ops, NsOp, BytesOp, AllocsOp float64
+}
+
+// trendRow is a row of a benchmark's trend page.
+type trendRow struct {
+	Result
+	NsOp, BytesOp, AllocsOp float64
+}
+
+func (d *dashboard) bench(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	results, err := d.s.Results(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(results) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	rows := make([]trendRow, len(results))
+	for i, res := range results {
+		rows[i] = trendRow{
+			Result:   res,
+			NsOp:     NsOp.Value(res.Bench),
+			BytesOp:  BytesOp.Value(res.Bench),
+			AllocsOp: AllocsOp.Value(res.Bench),
+		}
+	}
+	d.render(w, "bench", struct {
+		Name  string
+		Chart template.HTML
+		Rows  []trendRow
+	}{name, chart(NewSeries(name, NsOp, results)), rows})
+}
+
+// compareRow is a row of the comparison page; a side is nil if its run
+// doesn't have the benchmark.
+type compareRow struct {
+	ID     string
+	A, B   *float64
+	Change string
+}
+
+func (d *dashboard) compare(w http.ResponseWriter, r *http.Request) {
+	a, ok := runID(w, r, "a")
+	if !ok {
+		return
+	}
+	b, ok := runID(w, r, "b")
+	if !ok {
+		return
+	}
+	ra, err := d.s.LoadRun(a)
+	if err != nil {
+		loadError(w, err)
+		return
+	}
+	rb, err := d.s.LoadRun(b)
+	if err != nil {
+		loadError(w, err)
+		return
+	}
+	// the rows are in run a's order followed by any that only run b has.
+	var rows []compareRow
+	index := map[string]int{}
+	for _, bench := range ra.Benchmarks {
+		v := NsOp.Value(bench)
+		index[bench.ID()] = len(rows)
+		rows = append(rows, compareRow{ID: bench.ID(), A: &v})
+	}
+	for _, bench := range rb.Benchmarks {
+		v := NsOp.Value(bench)
+		i, ok := index[bench.ID()]
+		if !ok {
+			rows = append(rows, compareRow{ID: bench.ID(), B: &v})
+			continue
+		}
+		rows[i].B = &v
+		if *rows[i].A != 0 {
+			rows[i].Change = fmt.Sprintf("%+.1f%%", (v-*rows[i].A) / *rows[i].A * 100)
+		}
+	}
+	d.render(w, "compare", struct {
+		A, B Run
+		Rows []compareRow
+	}{NewRun(a, ra), NewRun(b, rb), rows})
+}
+
+func (d *dashboard) render(w http.ResponseWriter, name string, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := dashboardTemplates.ExecuteTemplate(w, name, data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// runID returns the run ID in the query parameter.  If it isn't valid, a
+// bad request response is written.
+func runID(w http.ResponseWriter, r *http.Request, param string) (int64, bool) {
+	id, err := strconv.ParseInt(r.URL.Query().Get(param), 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid run id: %q", r.URL.Query().Get(param)), http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// loadError writes the response for a LoadRun error.
+func loadError(w http.ResponseWriter, err error) {
+	if err == ErrNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// chart returns an SVG line chart of the series' values.
+func chart(s Series) template.HTML {
+	const width, height, pad = 600.0, 150.0, 5.0
+	if len(s.Points) < 2 {
+		return ""
+	}
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, p := range s.Points {
+		min = math.Min(min, p.Value)
+		max = math.Max(max, p.Value)
+	}
+	span := max - min
+	if span == 0 {
+		span = 1
+	}
+	pts := make([]string, len(s.Points))
+	for i, p := range s.Points {
+		x := pad + float64(i)/float64(len(s.Points)-1)*(width-2*pad)
+		y := height - pad - (p.Value-min)/span*(height-2*pad)
+		pts[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	return template.HTML(fmt.Sprintf(`<svg width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f"><polyline fill="none" stroke="#36c" stroke-width="2" points="%s"/></svg>`,
+		width, height, width, height, strings.Join(pts, " ")))
+}
+
+var dashboardTemplates = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"num": func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) },
+}).Parse(`
+{{define "head"}}<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { padding: 0.2em 0.8em; border-bottom: 1px solid #ddd; text-align: left; }
+td.n { text-align: right; font-family: monospace; }
+</style></head><body>
+<p><a href="/">runs</a></p>
+<h1>{{.}}</h1>
+{{end}}
+{{define "foot"}}</body></html>
+{{end}}
+
+{{define "runs"}}{{template "head" "Benchmark runs"}}
+<form action="/compare">compare run <input name="a" size="4"> with run <input name="b" size="4"> <button>compare</button></form>
+<table>
+<tr><th>ID</th><th>Timestamp</th><th>Name</th><th>Host</th><th>Commit</th><th>Benchmarks</th></tr>
+{{range .}}<tr><td><a href="/run?id={{.ID}}">{{.ID}}</a></td><td>{{.Timestamp.Format "2006-01-02 15:04:05"}}</td><td>{{.Name}}</td><td>{{.Hostname}}</td><td>{{.Commit}}</td><td class="n">{{.Benchmarks}}</td></tr>
+{{end}}</table>
+{{template "foot"}}{{end}}
+
+{{define "run"}}{{template "head" (printf "Run %d" .ID)}}
+<p>{{.Timestamp.Format "2006-01-02 15:04:05"}}{{with .Benches.Hostname}} on {{.}}{{end}}{{with .Git}} at {{.}}{{end}}</p>
+{{with .Labels}}<ul>{{range .}}<li>{{index . 0}}: {{index . 1}}</li>{{end}}</ul>{{end}}
+<table>
+<tr><th>Benchmark</th><th>Ops</th><th>ns/op</th><th>bytes/op</th><th>allocs/op</th><th>Note</th></tr>
+{{range .Rows}}<tr><td><a href="/bench?name={{.ID}}">{{.ID}}</a></td><td class="n">{{num .Ops}}</td><td class="n">{{num .NsOp}}</td><td class="n">{{num .BytesOp}}</td><td class="n">{{num .AllocsOp}}</td><td>{{.Note}}{{.Err}}</td></tr>
+{{end}}</table>
+{{template "foot"}}{{end}}
+
+{{define "bench"}}{{template "head" .Name}}
+<p>ns/op</p>
+{{.Chart}}
+<table>
+<tr><th>Run</th><th>Timestamp</th><th>ns/op</th><th>bytes/op</th><th>allocs/op</th></tr>
+{{range .Rows}}<tr><td><a href="/run?id={{.RunID}}">{{.RunID}}</a></td><td>{{.Timestamp.Format "2006-01-02 15:04:05"}}</td><td class="n">{{num .NsOp}}</td><td class="n">{{num .BytesOp}}</td><td class="n">{{num .AllocsOp}}</td></tr>
+{{end}}</table>
+{{template "foot"}}{{end}}
+
+{{define "compare"}}{{template "head" (printf "Run %d vs run %d" .A.ID .B.ID)}}
+<table>
+<tr><th>Benchmark</th><th>run {{.A.ID}} ns/op</th><th>run {{.B.ID}} ns/op</th><th>change</th></tr>
+{{range .Rows}}<tr><td><a href="/bench?name={{.ID}}">{{.ID}}</a></td><td class="n">{{with .A}}{{num .}}{{end}}</td><td class="n">{{with .B}}{{num .}}{{end}}</td><td class="n">{{.Change}}</td></tr>
+{{end}}</table>
+{{template "foot"}}{{end}}
+`))
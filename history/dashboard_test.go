@@ -0,0 +1,60 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package history
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashboard(t *testing.T) {
+	s := NewJSONLStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	start := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, ns := range []int64{1000, 1500} {
+		_, err := s.SaveRun(testRun("run", start.Add(time.Duration(i)*time.Hour), ns))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := httptest.NewServer(Handler(s))
+	defer srv.Close()
+
+	for _, test := range []struct {
+		path   string
+		status int
+		want   []string
+	}{
+		{"/", 200, []string{`<a href="/run?id=2">2</a>`, "3f7a2c1"}},
+		// the values are per op, not the totals over the iterations.
+		{"/run?id=1", 200, []string{"Run 1", `<a href="/bench?name=json%2fdecode%2fsmall">`, "<td class=\"n\">100</td>", "<td class=\"n\">1000</td>"}},
+		{"/run?id=9", 404, nil},
+		{"/run?id=x", 400, nil},
+		{"/bench?name=json/decode/small", 200, []string{"<svg", "<td class=\"n\">100</td>", "<td class=\"n\">150</td>"}},
+		{"/bench?name=nope", 404, nil},
+		{"/compare?a=1&b=2", 200, []string{"Run 1 vs run 2", "&#43;50.0%"}},
+		{"/nope", 404, nil},
+	} {
+		resp, err := http.Get(srv.URL + test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != test.status {
+			t.Errorf("%s: got status %d; want %d", test.path, resp.StatusCode, test.status)
+			continue
+		}
+		for _, want := range test.want {
+			if !strings.Contains(string(body), want) {
+				t.Errorf("%s: body doesn't contain %q:\n%s", test.path, want, body)
+			}
+		}
+	}
+}
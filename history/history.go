@@ -0,0 +1,73 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+// Package history stores benchmark runs so results can be tracked over
+// time.
+package history
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mohae/benchutil"
+)
+
+// ErrNotFound is returned when a run isn't in the store.
+var ErrNotFound = errors.New("history: run not found")
+
+// Store stores benchmark runs.
+type Store interface {
+	// SaveRun saves the benches as a new run and returns the run's ID.
+	SaveRun(b *benchutil.Benches) (int64, error)
+	// ListRuns returns the information about every run, newest first.
+	ListRuns() ([]RunInfo, error)
+	// LoadRun returns the benches saved as the run with the ID.  If there
+	// isn't a run with the ID, ErrNotFound is returned.
+	LoadRun(id int64) (*benchutil.Benches, error)
+	// Results returns the results of the benchmark, oldest first, from
+	// every run that has it.  The name is the benchmark's ID, e.g.
+	// "json/decode/small"; it's only the benchmark's Name if the benchmark
+	// has no Group or SubGroup, so benchmarks with the same Name in
+	// different groups aren't mixed up.
+	Results(name string) ([]Result, error)
+	// Close closes the store.
+	Close() error
+}
+
+// RunInfo holds the information about a stored run.
+type RunInfo struct {
+	ID         int64     // the run's ID.
+	Name       string    // the name of the benchmark set; optional.
+	Timestamp  time.Time // when the benchmarks were run.
+	Hostname   string    // the host the benchmarks were run on.
+	Commit     string    // the commit that was benchmarked; if it was captured.
+	Benchmarks int       // the number of benchmarks in the run.
+}
+
+// Result is a benchmark's result from a stored run.
+type Result struct {
+	RunID     int64           // the ID of the run the result is from.
+	Timestamp time.Time       // when the run's benchmarks were run.
+	Bench     benchutil.Bench // the result.
+}
+
+// runInfo returns the RunInfo for b.
+func runInfo(id int64, b *benchutil.Benches) RunInfo {
+	inf := RunInfo{
+		ID:         id,
+		Name:       b.Name,
+		Timestamp:  b.Timestamp,
+		Hostname:   b.Hostname,
+		Benchmarks: len(b.Benchmarks),
+	}
+	if b.Git != nil {
+		inf.Commit = b.Git.Commit
+	}
+	return inf
+}
+
+// matches returns whether or not the bench is the one whose ID is name.
+func matches(b benchutil.Bench, name string) bool {
+	return b.ID() == name
+}
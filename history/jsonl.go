@@ -0,0 +1,212 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/mohae/benchutil"
+)
+
+// JSONLStore is a Store that appends each run to a file as a line of JSON.
+// It has no dependencies and the file can be read with any JSON tooling,
+// which makes it a good fit for small projects; every query reads the whole
+// file.
+type JSONLStore struct {
+	path string
+}
+
+// jsonlRecord is a line in a JSONLStore's file.
+type jsonlRecord struct {
+	ID  int64              `json:"id"`
+	Run *benchutil.Benches `json:"run"`
+}
+
+// NewJSONLStore returns a JSONLStore that uses the file at path.  The file
+// is created on the first SaveRun.
+func NewJSONLStore(path string) *JSONLStore {
+	return &JSONLStore{path: path}
+}
+
+// SaveRun implements Store.  The run's ID is its line number in the file.
+func (s *JSONLStore) SaveRun(b *benchutil.Benches) (int64, error) {
+	recs, err := s.read()
+	if err != nil {
+		return 0, err
+	}
+	var id int64 = 1
+	if len(recs) > 0 {
+		id = recs[len(recs)-1].ID + 1
+	}
+	line, err := json.Marshal(jsonlRecord{ID: id, Run: b})
+	if err != nil {
+		return 0, err
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return 0, err
+	}
+	// a partial last line, which read ignored, is dropped so the run isn't
+	// appended to it.
+	end, err := lastLineEnd(f)
+	if err == nil {
+		err = f.Truncate(end)
+	}
+	if err == nil {
+		_, err = f.WriteAt(append(line, '\n'), end)
+	}
+	if err != nil {
+		f.Close()
+		return 0, err
+	}
+	return id, f.Close()
+}
+
+// ListRuns implements Store.
+func (s *JSONLStore) ListRuns() ([]RunInfo, error) {
+	recs, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]RunInfo, 0, len(recs))
+	for i := len(recs) - 1; i >= 0; i-- {
+		runs = append(runs, runInfo(recs[i].ID, recs[i].Run))
+	}
+	return runs, nil
+}
+
+// LoadRun implements Store.
+func (s *JSONLStore) LoadRun(id int64) (*benchutil.Benches, error) {
+	recs, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range recs {
+		if rec.ID == id {
+			return rec.Run, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// LastRuns returns the last n runs, oldest first.  If there are fewer than
+// n runs, all of them are returned.
+func (s *JSONLStore) LastRuns(n int) ([]*benchutil.Benches, error) {
+	recs, err := s.last(n)
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]*benchutil.Benches, len(recs))
+	for i, rec := range recs {
+		runs[i] = rec.Run
+	}
+	return runs, nil
+}
+
+// Results implements Store.
+func (s *JSONLStore) Results(name string) ([]Result, error) {
+	recs, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return results(recs, name), nil
+}
+
+// Series returns the benchmark's results from the last n runs, oldest
+// first.  The name is matched the same way as it is by Results.
+func (s *JSONLStore) Series(name string, n int) ([]Result, error) {
+	recs, err := s.last(n)
+	if err != nil {
+		return nil, err
+	}
+	return results(recs, name), nil
+}
+
+// Close implements Store; the file is only open while it's being used so
+// there's nothing to close.
+func (s *JSONLStore) Close() error {
+	return nil
+}
+
+// read returns every record in the file, in the order they were written.
+// A missing file has no records.  A partial last line, e.g. from a write
+// that was interrupted, is ignored; the next SaveRun replaces it.
+func (s *JSONLStore) read() ([]jsonlRecord, error) {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var recs []jsonlRecord
+	dec := json.NewDecoder(f)
+	for {
+		rec := jsonlRecord{Run: benchutil.NewBenches()}
+		err := dec.Decode(&rec)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return recs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+}
+
+// lastLineEnd returns the offset just past the last '\n' in f; 0 if there
+// isn't one.
+func lastLineEnd(f *os.File) (int64, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	buf := make([]byte, 4096)
+	for end := fi.Size(); end > 0; {
+		start := end - int64(len(buf))
+		if start < 0 {
+			start = 0
+		}
+		b := buf[:end-start]
+		_, err := f.ReadAt(b, start)
+		if err != nil {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(b, '\n'); i >= 0 {
+			return start + int64(i) + 1, nil
+		}
+		end = start
+	}
+	return 0, nil
+}
+
+// last returns the last n records.
+func (s *JSONLStore) last(n int) ([]jsonlRecord, error) {
+	recs, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	if n >= 0 && n < len(recs) {
+		recs = recs[len(recs)-n:]
+	}
+	return recs, nil
+}
+
+// results returns the named benchmark's results from the records.
+func results(recs []jsonlRecord, name string) []Result {
+	var res []Result
+	for _, rec := range recs {
+		for _, b := range rec.Run.Benchmarks {
+			if matches(b, name) {
+				res = append(res, Result{RunID: rec.ID, Timestamp: rec.Run.Timestamp, Bench: b})
+			}
+		}
+	}
+	return res
+}
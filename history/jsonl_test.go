@@ -0,0 +1,115 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package history
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJSONLStore(t *testing.T) {
+	testStore(t, NewJSONLStore(filepath.Join(t.TempDir(), "history.jsonl")))
+}
+
+func TestJSONLStoreSeries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	s := NewJSONLStore(path)
+	start := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		_, err := s.SaveRun(testRun("run", start.Add(time.Duration(i)*time.Hour), int64(100+i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a partial line from an interrupted write is ignored.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"id":6,"run":{"Name":`)
+	f.Close()
+
+	runs, err := s.LastRuns(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].Benchmarks[0].NsOp != 103 || runs[1].Benchmarks[0].NsOp != 104 {
+		t.Errorf("got %d runs; want the last 2, oldest first", len(runs))
+	}
+	runs, err = s.LastRuns(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 5 {
+		t.Errorf("got %d runs; want 5", len(runs))
+	}
+
+	series, err := s.Series("json/decode/large", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 3 {
+		t.Fatalf("got %d results; want 3", len(series))
+	}
+	for i, r := range series {
+		if r.RunID != int64(i+3) || r.Bench.NsOp != int64(100+i+2)*10 {
+			t.Errorf("%d: got %+v", i, r)
+		}
+	}
+}
+
+// A run saved after an interrupted write replaces the partial line, so the
+// file can still be read.
+func TestJSONLStorePartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	s := NewJSONLStore(path)
+	start := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	_, err := s.SaveRun(testRun("run", start, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"id":2,"run":{"Name":`)
+	f.Close()
+
+	id, err := s.SaveRun(testRun("run", start.Add(time.Hour), 101))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 2 {
+		t.Errorf("got id %d; want 2", id)
+	}
+	runs, err := s.ListRuns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].ID != 2 {
+		t.Fatalf("got %+v; want 2 runs", runs)
+	}
+	id, err = s.SaveRun(testRun("run", start.Add(2*time.Hour), 102))
+	if err != nil || id != 3 {
+		t.Errorf("got id %d, %v; want 3", id, err)
+	}
+	b, err := s.LoadRun(2)
+	if err != nil || b.Benchmarks[0].NsOp != 101 {
+		t.Errorf("got %+v, %v; want run 2", b, err)
+	}
+
+	// a file without a complete line is replaced.
+	err = ioutil.WriteFile(path, []byte(`{"id":1`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err = s.SaveRun(testRun("run", start, 100))
+	if err != nil || id != 1 {
+		t.Errorf("got id %d, %v; want 1", id, err)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package history
+
+import (
+	"sync"
+
+	"github.com/mohae/benchutil"
+)
+
+// MemStore is a Store that holds the runs in memory, e.g. for comparing
+// against a baseline that was loaded from a file.
+type MemStore struct {
+	mu   sync.Mutex
+	runs []*benchutil.Benches
+}
+
+// NewMemStore returns an empty MemStore.
+func NewMemStore() *MemStore {
+	return &MemStore{}
+}
+
+// SaveRun implements Store.  The benches aren't copied.
+func (s *MemStore) SaveRun(b *benchutil.Benches) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs = append(s.runs, b)
+	return int64(len(s.runs)), nil
+}
+
+// ListRuns implements Store.
+func (s *MemStore) ListRuns() ([]RunInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	runs := make([]RunInfo, 0, len(s.runs))
+	for i := len(s.runs) - 1; i >= 0; i-- {
+		runs = append(runs, runInfo(int64(i+1), s.runs[i]))
+	}
+	return runs, nil
+}
+
+// LoadRun implements Store.
+func (s *MemStore) LoadRun(id int64) (*benchutil.Benches, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id < 1 || id > int64(len(s.runs)) {
+		return nil, ErrNotFound
+	}
+	return s.runs[id-1], nil
+}
+
+// Results implements Store.
+func (s *MemStore) Results(name string) ([]Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res []Result
+	for i, b := range s.runs {
+		for _, v := range b.Benchmarks {
+			if matches(v, name) {
+				res = append(res, Result{RunID: int64(i + 1), Timestamp: b.Timestamp, Bench: v})
+			}
+		}
+	}
+	return res, nil
+}
+
+// Close implements Store; it doesn't do anything.
+func (s *MemStore) Close() error {
+	return nil
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package history
+
+import (
+	"testing"
+
+	"github.com/mohae/benchutil"
+)
+
+func TestMemStore(t *testing.T) {
+	testStore(t, NewMemStore())
+}
+
+// An ungrouped benchmark's ID is its Name; it isn't mixed up with a grouped
+// benchmark of the same Name.
+func TestResultsUngrouped(t *testing.T) {
+	s := NewMemStore()
+	b := &benchutil.Benches{}
+	b.Append(
+		benchutil.Bench{Name: "small", Iterations: 1, Result: benchutil.Result{NsOp: 10}},
+		benchutil.Bench{Group: "json", Name: "small", Iterations: 1, Result: benchutil.Result{NsOp: 20}},
+	)
+	_, err := s.SaveRun(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := s.Results("small")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Bench.NsOp != 10 {
+		t.Errorf("got %+v; want only the ungrouped small", results)
+	}
+}
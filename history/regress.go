@@ -0,0 +1,74 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package history
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/mohae/benchutil"
+)
+
+// Regression is a benchmark whose value got worse than its baseline.
+type Regression struct {
+	Name     string  // the benchmark's ID.
+	Metric   Metric  // the metric that regressed.
+	Baseline float64 // the mean of the benchmark's prior values.
+	Value    float64 // the benchmark's value.
+	Change   float64 // the relative change from the baseline, e.g. 0.5 is 50% more.
+}
+
+// String returns the regression as a string, e.g.
+// "json/decode/small: ns/op 100 -> 150 (+50.0%)".
+func (r Regression) String() string {
+	return fmt.Sprintf("%s: %s %g -> %g (%+.1f%%)", r.Name, r.Metric, r.Baseline, r.Value, r.Change*100)
+}
+
+// CheckRegressions compares the benches' results against the mean of each
+// benchmark's last n results in the store; if n < 1 all of its results are
+// used.  A change of more than the threshold, e.g. 0.1 for 10%, in the wrong
+// direction is a regression: an increase for NsOp, BytesOp, and AllocsOp, a
+// decrease for Ops.  If no metrics are specified, NsOp is checked.  Failed
+// benches and benchmarks without history are skipped.  The benches should
+// be checked before they're saved to the store.
+func CheckRegressions(s Store, b *benchutil.Benches, n int, threshold float64, metrics ...Metric) ([]Regression, error) {
+	if len(metrics) == 0 {
+		metrics = []Metric{NsOp}
+	}
+	var regs []Regression
+	for _, bench := range b.Benchmarks {
+		if bench.Failed() {
+			continue
+		}
+		results, err := s.Results(bench.ID())
+		if err != nil {
+			return nil, err
+		}
+		if len(results) == 0 {
+			continue
+		}
+		results = append(results, Result{Timestamp: b.Timestamp, Bench: bench})
+		for _, m := range metrics {
+			series := NewSeries(bench.ID(), m, results)
+			change := series.Change(n)
+			if math.IsNaN(change) {
+				continue
+			}
+			if m == Ops {
+				change = -change
+			}
+			if change <= threshold {
+				continue
+			}
+			mean, _ := series.Baseline(n)
+			last, _ := series.Last()
+			if m == Ops {
+				change = -change
+			}
+			regs = append(regs, Regression{Name: bench.ID(), Metric: m, Baseline: mean, Value: last.Value, Change: change})
+		}
+	}
+	return regs, nil
+}
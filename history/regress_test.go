@@ -0,0 +1,47 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mohae/benchutil"
+)
+
+func TestCheckRegressions(t *testing.T) {
+	s := NewJSONLStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	start := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, ns := range []int64{1000, 1000, 1000} {
+		_, err := s.SaveRun(testRun("run", start.Add(time.Duration(i)*time.Hour), ns))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	b := &benchutil.Benches{Timestamp: start.Add(3 * time.Hour)}
+	b.Append(
+		benchutil.Bench{Group: "json", SubGroup: "decode", Name: "small", Iterations: 10, Result: benchutil.Result{NsOp: 1050}},
+		benchutil.Bench{Group: "json", SubGroup: "decode", Name: "large", Iterations: 10, Result: benchutil.Result{NsOp: 15000}},
+		benchutil.Bench{Group: "json", SubGroup: "encode", Name: "new", Iterations: 10, Result: benchutil.Result{NsOp: 15000}},
+	)
+	regs, err := CheckRegressions(s, b, 0, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(regs) != 1 {
+		t.Fatalf("got %v; want 1 regression", regs)
+	}
+	if got, want := regs[0].String(), "json/decode/large: ns/op 1000 -> 1500 (+50.0%)"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+	regs, err = CheckRegressions(s, b, 0, 0.01, Ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(regs) != 0 {
+		t.Errorf("got %v; want no ops regressions", regs)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package history
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mohae/benchutil"
+)
+
+// Run is a stored set of benchmarks along with what identifies it: when
+// and where it was run, what code was benchmarked, and its labels.
+type Run struct {
+	ID        int64              // the run's ID in its store.
+	Timestamp time.Time          // when the benchmarks were run.
+	SysInfo   *benchutil.SysInfo // the system the benchmarks were run on; if it was captured.
+	Git       *benchutil.GitInfo // the code that was benchmarked; if it was captured.
+	Labels    [][2]string        // the run's labels; these are the Benches' Meta.
+	Benches   *benchutil.Benches // the benchmarks.
+}
+
+// NewRun returns the Run for the benches stored with the ID.
+func NewRun(id int64, b *benchutil.Benches) Run {
+	return Run{
+		ID:        id,
+		Timestamp: b.Timestamp,
+		SysInfo:   b.SysInfo,
+		Git:       b.Git,
+		Labels:    b.Meta,
+		Benches:   b,
+	}
+}
+
+// Label returns the value of the run's label with the key and whether or
+// not the run has the label.
+func (r Run) Label(key string) (string, bool) {
+	for _, kv := range r.Labels {
+		if kv[0] == key {
+			return kv[1], true
+		}
+	}
+	return "", false
+}
+
+// Bench returns the run's benchmark whose ID is name and whether or not the
+// run has it.
+func (r Run) Bench(name string) (benchutil.Bench, bool) {
+	for _, b := range r.Benches.Benchmarks {
+		if matches(b, name) {
+			return b, true
+		}
+	}
+	return benchutil.Bench{}, false
+}
+
+// LoadRuns returns the last n runs in the store, oldest first.  If n < 0,
+// every run is returned.
+func LoadRuns(s Store, n int) ([]Run, error) {
+	infos, err := s.ListRuns()
+	if err != nil {
+		return nil, err
+	}
+	if n >= 0 && n < len(infos) {
+		infos = infos[:n]
+	}
+	runs := make([]Run, len(infos))
+	for i, inf := range infos {
+		b, err := s.LoadRun(inf.ID)
+		if err != nil {
+			return nil, err
+		}
+		// ListRuns is newest first.
+		runs[len(infos)-1-i] = NewRun(inf.ID, b)
+	}
+	return runs, nil
+}
+
+// Metric is a benchmark value that can be tracked across runs.
+type Metric int
+
+const (
+	NsOp     Metric = iota // nanoseconds per op.
+	BytesOp                // bytes allocated per op.
+	AllocsOp               // allocations per op.
+	Ops                    // the number of ops performed.
+)
+
+func (m Metric) String() string {
+	switch m {
+	case NsOp:
+		return "ns/op"
+	case BytesOp:
+		return "bytes/op"
+	case AllocsOp:
+		return "allocs/op"
+	case Ops:
+		return "ops"
+	}
+	return fmt.Sprintf("Metric(%d)", int(m))
+}
+
+// Value returns the bench's value for the metric.  The per op values are
+// averaged over the bench's iterations and the ops are totaled, the same as
+// they are in benchutil's output.
+func (m Metric) Value(b benchutil.Bench) float64 {
+	it := b.Iterations
+	if it < 1 {
+		it = 1
+	}
+	switch m {
+	case NsOp:
+		return float64(b.NsOp) / float64(it)
+	case BytesOp:
+		return float64(b.BytesOp) / float64(it)
+	case AllocsOp:
+		return float64(b.AllocsOp) / float64(it)
+	case Ops:
+		return float64(b.Ops) * float64(it)
+	}
+	return 0
+}
+
+// Point is a benchmark's value in a run.
+type Point struct {
+	RunID     int64     // the run the value is from.
+	Timestamp time.Time // when the run's benchmarks were run.
+	Value     float64   // the value.
+}
+
+// Series is a benchmark's values for a metric across runs, oldest first.
+type Series struct {
+	Name   string  // the benchmark's ID.
+	Metric Metric  // what the values are.
+	Points []Point // the values, oldest first.
+}
+
+// NewSeries returns the series of the metric's values in the results.
+func NewSeries(name string, m Metric, results []Result) Series {
+	s := Series{Name: name, Metric: m, Points: make([]Point, len(results))}
+	for i, r := range results {
+		s.Points[i] = Point{RunID: r.RunID, Timestamp: r.Timestamp, Value: m.Value(r.Bench)}
+	}
+	return s
+}
+
+// Values returns the named benchmark's values for the metric from every run
+// in the store that has it, oldest first.
+func Values(s Store, name string, m Metric) (Series, error) {
+	results, err := s.Results(name)
+	if err != nil {
+		return Series{}, err
+	}
+	return NewSeries(name, m, results), nil
+}
+
+// Values returns the series' values.
+func (s Series) Values() []float64 {
+	v := make([]float64, len(s.Points))
+	for i, p := range s.Points {
+		v[i] = p.Value
+	}
+	return v
+}
+
+// Last returns the most recent point and whether or not the series has any
+// points.
+func (s Series) Last() (Point, bool) {
+	if len(s.Points) == 0 {
+		return Point{}, false
+	}
+	return s.Points[len(s.Points)-1], true
+}
+
+// Baseline returns the mean and standard deviation of the n points before
+// the most recent one; these are what the most recent value is compared
+// against.  If n < 1 or is more than the number of prior points, every
+// prior point is used.  If there aren't any prior points, both are NaN.
+func (s Series) Baseline(n int) (mean, stddev float64) {
+	if len(s.Points) < 2 {
+		return math.NaN(), math.NaN()
+	}
+	prior := s.Points[:len(s.Points)-1]
+	if n > 0 && n < len(prior) {
+		prior = prior[len(prior)-n:]
+	}
+	for _, p := range prior {
+		mean += p.Value
+	}
+	mean /= float64(len(prior))
+	for _, p := range prior {
+		stddev += (p.Value - mean) * (p.Value - mean)
+	}
+	return mean, math.Sqrt(stddev / float64(len(prior)))
+}
+
+// Change returns the relative change of the most recent value from the mean
+// of the n points before it, e.g. 0.1 is 10% more than the baseline; see
+// Baseline.  For NsOp, BytesOp, and AllocsOp a positive change is a
+// regression; for Ops it's an improvement.  If there isn't a baseline, or
+// it's 0, the change is NaN.
+func (s Series) Change(n int) float64 {
+	mean, _ := s.Baseline(n)
+	if math.IsNaN(mean) || mean == 0 {
+		return math.NaN()
+	}
+	last, _ := s.Last()
+	return (last.Value - mean) / mean
+}
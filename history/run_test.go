@@ -0,0 +1,94 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package history
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mohae/benchutil"
+)
+
+func TestRuns(t *testing.T) {
+	s := NewJSONLStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	start := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, ns := range []int64{1000, 1100, 900, 1000, 1500} {
+		b := testRun("run", start.Add(time.Duration(i)*time.Hour), ns)
+		b.SetMeta("build", string(rune('a'+i)))
+		_, err := s.SaveRun(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, err := LoadRuns(s, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].ID != 4 || runs[1].ID != 5 {
+		t.Fatalf("got %+v; want runs 4 and 5", runs)
+	}
+	if v, ok := runs[1].Label("build"); !ok || v != "e" {
+		t.Errorf("got label %q, %t; want \"e\", true", v, ok)
+	}
+	if runs[1].Git == nil || runs[1].Git.Commit != "3f7a2c1" {
+		t.Errorf("got %+v; want the run's git info", runs[1].Git)
+	}
+	b, ok := runs[1].Bench("json/decode/small")
+	if !ok || b.NsOp != 1500 {
+		t.Errorf("got %+v, %t; want the run's bench", b, ok)
+	}
+	runs, err = LoadRuns(s, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 5 {
+		t.Errorf("got %d runs; want 5", len(runs))
+	}
+
+	series, err := Values(s, "json/decode/small", NsOp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{100, 110, 90, 100, 150}
+	got := series.Values()
+	if len(got) != len(want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%d: got %v; want %v", i, got[i], want[i])
+		}
+	}
+	mean, stddev := series.Baseline(0)
+	if mean != 100 || math.Abs(stddev-math.Sqrt(50)) > 1e-9 {
+		t.Errorf("got baseline %v, %v; want 100, %v", mean, stddev, math.Sqrt(50))
+	}
+	if c := series.Change(0); math.Abs(c-0.5) > 1e-9 {
+		t.Errorf("got change %v; want 0.5", c)
+	}
+	if c := series.Change(1); math.Abs(c-0.5) > 1e-9 {
+		t.Errorf("got change %v; want 0.5", c)
+	}
+	if c := (Series{}).Change(0); !math.IsNaN(c) {
+		t.Errorf("got change %v; want NaN", c)
+	}
+}
+
+func TestMetricValue(t *testing.T) {
+	b := benchutil.Bench{Iterations: 4, Result: benchutil.Result{Ops: 10, NsOp: 400, BytesOp: 80, AllocsOp: 8}}
+	for _, test := range []struct {
+		m    Metric
+		want float64
+	}{
+		{NsOp, 100}, {BytesOp, 20}, {AllocsOp, 2}, {Ops, 40},
+	} {
+		if v := test.m.Value(b); v != test.want {
+			t.Errorf("%s: got %v; want %v", test.m, v, test.want)
+		}
+	}
+}
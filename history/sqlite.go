@@ -0,0 +1,164 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/mohae/benchutil"
+)
+
+// sqliteSchema creates the tables, if they don't exist.  The run's benches
+// are stored as JSON; the results are also stored in their own table so
+// they can be queried by benchmark.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	timestamp TEXT NOT NULL,
+	hostname TEXT NOT NULL,
+	git_commit TEXT NOT NULL,
+	benchmarks INTEGER NOT NULL,
+	data TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS results (
+	run_id INTEGER NOT NULL REFERENCES runs(id),
+	seq INTEGER NOT NULL,
+	bench_id TEXT NOT NULL,
+	data TEXT NOT NULL,
+	PRIMARY KEY (run_id, seq)
+);
+CREATE INDEX IF NOT EXISTS results_bench_id ON results(bench_id);
+`
+
+// SQLiteStore is a Store backed by a SQLite database.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore returns a SQLiteStore that uses db, creating its tables if
+// they don't exist.  The db must use a SQLite driver, e.g.
+// github.com/mattn/go-sqlite3; the driver is left to the caller so this
+// package doesn't require cgo.  Closing the store closes the db.
+func NewSQLiteStore(db *sql.DB) (*SQLiteStore, error) {
+	_, err := db.Exec(sqliteSchema)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// SaveRun implements Store.
+func (s *SQLiteStore) SaveRun(b *benchutil.Benches) (int64, error) {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return 0, err
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	inf := runInfo(0, b)
+	res, err := tx.Exec(`INSERT INTO runs (name, timestamp, hostname, git_commit, benchmarks, data) VALUES (?, ?, ?, ?, ?, ?)`,
+		inf.Name, inf.Timestamp.Format(time.RFC3339Nano), inf.Hostname, inf.Commit, inf.Benchmarks, string(data))
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	for i, bench := range b.Benchmarks {
+		data, err := json.Marshal(bench)
+		if err != nil {
+			return 0, err
+		}
+		_, err = tx.Exec(`INSERT INTO results (run_id, seq, bench_id, data) VALUES (?, ?, ?, ?)`,
+			id, i, bench.ID(), string(data))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return id, tx.Commit()
+}
+
+// ListRuns implements Store.
+func (s *SQLiteStore) ListRuns() ([]RunInfo, error) {
+	rows, err := s.db.Query(`SELECT id, name, timestamp, hostname, git_commit, benchmarks FROM runs ORDER BY id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var runs []RunInfo
+	for rows.Next() {
+		var inf RunInfo
+		var ts string
+		err := rows.Scan(&inf.ID, &inf.Name, &ts, &inf.Hostname, &inf.Commit, &inf.Benchmarks)
+		if err != nil {
+			return nil, err
+		}
+		inf.Timestamp, err = time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, inf)
+	}
+	return runs, rows.Err()
+}
+
+// LoadRun implements Store.
+func (s *SQLiteStore) LoadRun(id int64) (*benchutil.Benches, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM runs WHERE id = ?`, id).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	b := benchutil.NewBenches()
+	err = json.Unmarshal([]byte(data), b)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Results implements Store.
+func (s *SQLiteStore) Results(name string) ([]Result, error) {
+	rows, err := s.db.Query(`SELECT r.run_id, runs.timestamp, r.data FROM results r JOIN runs ON runs.id = r.run_id
+		WHERE r.bench_id = ? ORDER BY r.run_id, r.seq`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var results []Result
+	for rows.Next() {
+		var r Result
+		var ts, data string
+		err := rows.Scan(&r.RunID, &ts, &data)
+		if err != nil {
+			return nil, err
+		}
+		r.Timestamp, err = time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal([]byte(data), &r.Bench)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// Close implements Store; it closes the database.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package history
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/mohae/benchutil"
+)
+
+func testRun(name string, ts time.Time, nsOp int64) *benchutil.Benches {
+	b := &benchutil.Benches{Name: name, Hostname: "test", Timestamp: ts,
+		Git: &benchutil.GitInfo{Commit: "3f7a2c1"}}
+	b.Append(
+		benchutil.Bench{Group: "json", SubGroup: "decode", Name: "small", Iterations: 10, Result: benchutil.Result{NsOp: nsOp}},
+		benchutil.Bench{Group: "json", SubGroup: "decode", Name: "large", Iterations: 10, Result: benchutil.Result{NsOp: nsOp * 10}},
+		// the same Name in another group is a different benchmark.
+		benchutil.Bench{Group: "xml", SubGroup: "decode", Name: "small", Iterations: 10, Result: benchutil.Result{NsOp: nsOp * 2}},
+	)
+	return b
+}
+
+func newTestSQLiteStore(t *testing.T) *SQLiteStore {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSQLiteStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// testStore tests a Store's implementation; it's used by each Store's tests.
+func testStore(t *testing.T, s Store) {
+	start := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		id, err := s.SaveRun(testRun("run", start.Add(time.Duration(i)*time.Hour), int64(100+i)))
+		if err != nil {
+			t.Fatalf("save %d: %s", i, err)
+		}
+		if id != int64(i+1) {
+			t.Errorf("save %d: got id %d; want %d", i, id, i+1)
+		}
+	}
+
+	runs, err := s.ListRuns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 3 {
+		t.Fatalf("got %d runs; want 3", len(runs))
+	}
+	if runs[0].ID != 3 || !runs[0].Timestamp.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("got %+v; want the newest run first", runs[0])
+	}
+	if runs[0].Commit != "3f7a2c1" || runs[0].Benchmarks != 3 || runs[0].Hostname != "test" {
+		t.Errorf("got %+v; want commit, benchmarks, and hostname set", runs[0])
+	}
+
+	b, err := s.LoadRun(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Benchmarks) != 3 || b.Benchmarks[0].NsOp != 101 || b.Git.Commit != "3f7a2c1" {
+		t.Errorf("got %+v; want run 2", b)
+	}
+	_, err = s.LoadRun(42)
+	if err != ErrNotFound {
+		t.Errorf("got %v; want ErrNotFound", err)
+	}
+
+	// benchmarks are matched by ID; each group's small is its own.
+	for _, test := range []struct {
+		name string
+		mul  int64
+	}{{"json/decode/small", 1}, {"xml/decode/small", 2}} {
+		results, err := s.Results(test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 3 {
+			t.Fatalf("%s: got %d results; want 3", test.name, len(results))
+		}
+		for i, r := range results {
+			if r.RunID != int64(i+1) || r.Bench.NsOp != int64(100+i)*test.mul || !r.Timestamp.Equal(start.Add(time.Duration(i)*time.Hour)) {
+				t.Errorf("%s: %d: got %+v", test.name, i, r)
+			}
+		}
+	}
+	for _, name := range []string{"small", "nope"} {
+		results, err := s.Results(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 0 {
+			t.Errorf("%s: got %d results; want 0", name, len(results))
+		}
+	}
+}
+
+func TestSQLiteStore(t *testing.T) {
+	testStore(t, newTestSQLiteStore(t))
+}
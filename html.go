@@ -0,0 +1,188 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+)
+
+// HTMLBench Benches is a collection of benchmark informtion and their
+// results.  The output is written as an HTML document to the writer, with
+// the benchmark results formatted as a table.  The set's name is an H2, its
+// description a paragraph, and the run info and system info a definition
+// list.  With sections, each group is its own tbody; if the sections are
+// named, the tbody starts with a row with the group's name instead of having
+// a group column.
+type HTMLBench struct {
+	Benches
+	w io.Writer
+}
+
+// NewHTMLBench returns an HTMLBench that writes to w, configured by the
+// options.
+func NewHTMLBench(w io.Writer, opts ...Option) *HTMLBench {
+	b := &HTMLBench{
+		w: w,
+		Benches: Benches{
+			header:        newHeader(),
+			columnPadding: defaultPadding,
+		},
+	}
+	b.apply(opts)
+	return b
+}
+
+// Out writes the benchmark results to the writer as an HTML document.
+func (b *HTMLBench) Out() error {
+	if b.optErr != nil {
+		return b.optErr
+	}
+	title := b.title
+	if title == "" {
+		title = b.Name
+	}
+	fmt.Fprintf(b.w, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n<body>\n", html.EscapeString(title))
+	if len(b.title) > 0 {
+		fmt.Fprintf(b.w, "<h1>%s</h1>\n", html.EscapeString(b.title))
+	}
+	if len(b.Name) > 0 {
+		fmt.Fprintf(b.w, "<h2>%s</h2>\n", html.EscapeString(b.Name))
+	}
+	if len(b.Desc) > 0 {
+		fmt.Fprintf(b.w, "<p>%s</p>\n", html.EscapeString(b.Desc))
+	}
+	// the run info and the system info, if applicable, are a definition list.
+	kv, err := b.sysInfoKeyValues()
+	if err != nil {
+		return err
+	}
+	kv = append(b.runInfo(), kv...)
+	if len(kv) > 0 {
+		fmt.Fprintln(b.w, "<dl>")
+		for _, v := range kv {
+			fmt.Fprintf(b.w, "<dt>%s</dt><dd>%s</dd>\n", html.EscapeString(v[0]), html.EscapeString(v[1]))
+		}
+		fmt.Fprintln(b.w, "</dl>")
+	}
+	for _, v := range b.Warnings {
+		fmt.Fprintf(b.w, "<p><strong>Warning:</strong> %s</p>\n", html.EscapeString(v))
+	}
+	fmt.Fprintln(b.w, "<table>")
+	if len(b.caption) > 0 {
+		fmt.Fprintf(b.w, "<caption>%s</caption>\n", html.EscapeString(b.caption))
+	}
+	b.setLength()
+	// the header row's cells, and whether or not each column is numeric, so
+	// its cells are right aligned.
+	var hdr []string
+	var num []bool
+	if b.length.Group > 0 && !b.nameSection() {
+		hdr, num = append(hdr, b.header.Group), append(num, false)
+	}
+	if b.length.SubGroup > 0 {
+		hdr, num = append(hdr, b.header.SubGroup), append(num, false)
+	}
+	if b.length.Name > 0 {
+		hdr, num = append(hdr, b.header.Name), append(num, false)
+	}
+	if b.length.Desc > 0 {
+		hdr, num = append(hdr, b.header.Desc), append(num, false)
+	}
+	hdr = append(hdr, b.header.Ops, b.header.NsOp, b.header.BytesOp, b.header.AllocsOp)
+	num = append(num, true, true, true, true)
+	if b.length.Note > 0 {
+		hdr, num = append(hdr, b.header.Note), append(num, false)
+	}
+	var buf bytes.Buffer
+	buf.WriteString("<thead>\n")
+	htmlRow(&buf, "th", hdr, num)
+	buf.WriteString("</thead>\n")
+	_, err = b.w.Write(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	if len(b.Benchmarks) > 0 {
+		err = formatRows(len(b.Benchmarks), b.parallelism, func(buf *bytes.Buffer, i int) error {
+			b.formatRow(buf, i, hdr, num)
+			return nil
+		}, func(p []byte) error {
+			_, err := b.w.Write(p)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(b.w, "</tbody>\n")
+		if err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(b.w, "</table>\n")
+	if err != nil {
+		return err
+	}
+	// The set's note follows the table.
+	if len(b.Note) > 0 {
+		_, err = fmt.Fprintf(b.w, "<p>%s</p>\n", html.EscapeString(b.Note))
+		if err != nil {
+			return err
+		}
+	}
+	if len(b.footer) > 0 {
+		_, err = fmt.Fprintf(b.w, "<footer>%s</footer>\n", html.EscapeString(b.footer))
+		if err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(b.w, "</body>\n</html>\n")
+	return err
+}
+
+// formatRow writes the row for the benchmark at index i to buf, preceded by
+// the start of the table's body, for the first row, and by whatever starts
+// the benchmark's section, if it starts one.  It only reads from b, so rows
+// can be formatted concurrently, each into its own buffer.
+func (b *HTMLBench) formatRow(buf *bytes.Buffer, i int, hdr []string, num []bool) {
+	v := b.Benchmarks[i]
+	if i == 0 || b.sectionPerGroup && v.Group != b.Benchmarks[i-1].Group {
+		if i > 0 {
+			buf.WriteString("</tbody>\n")
+		}
+		buf.WriteString("<tbody>\n")
+		if b.nameSection() {
+			fmt.Fprintf(buf, "<tr><th colspan=\"%d\">%s</th></tr>\n", len(hdr), html.EscapeString(v.Group))
+		}
+		if i > 0 && b.sectionHeaders {
+			htmlRow(buf, "th", hdr, num)
+		}
+	}
+	line := b.csv(i)
+	if b.nameSection() && b.length.Group > 0 {
+		line = line[1:]
+	}
+	htmlRow(buf, "td", line, num)
+}
+
+// nameSection returns whether or not the sections are named.
+func (b *HTMLBench) nameSection() bool {
+	return b.sectionPerGroup && b.nameSections
+}
+
+// htmlRow writes a table row of cells using the tag, e.g. th or td; the
+// numeric cells are right aligned.
+func htmlRow(buf *bytes.Buffer, tag string, cells []string, num []bool) {
+	buf.WriteString("<tr>")
+	for i, v := range cells {
+		if num[i] {
+			fmt.Fprintf(buf, "<%s style=\"text-align: right\">%s</%s>", tag, html.EscapeString(v), tag)
+			continue
+		}
+		fmt.Fprintf(buf, "<%s>%s</%s>", tag, html.EscapeString(v), tag)
+	}
+	buf.WriteString("</tr>\n")
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTMLBench(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewHTMLBench(&buf, WithTitle("Encoding & decoding"))
+	SetBenches(b, &Benches{
+		Name:       "json",
+		Note:       "run on <battery>",
+		Hostname:   "host",
+		Timestamp:  time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC),
+		Benchmarks: testBenches()[:1],
+		Warnings:   []string{"throttled"},
+	})
+	err := b.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Encoding &amp; decoding</title>
+</head>
+<body>
+<h1>Encoding &amp; decoding</h1>
+<h2>json</h2>
+<dl>
+<dt>Host</dt><dd>host</dd>
+<dt>Timestamp</dt><dd>2016-06-01T12:00:00Z</dd>
+</dl>
+<p><strong>Warning:</strong> throttled</p>
+<table>
+<thead>
+<tr><th>Group</th><th>Name</th><th style="text-align: right">Ops</th><th style="text-align: right">ns/Op</th><th style="text-align: right">B/Op</th><th style="text-align: right">Allocs/Op</th></tr>
+</thead>
+<tbody>
+<tr><td>group</td><td>a</td><td style="text-align: right">1000</td><td style="text-align: right">100</td><td style="text-align: right">16</td><td style="text-align: right">1</td></tr>
+</tbody>
+</table>
+<p>run on &lt;battery&gt;</p>
+</body>
+</html>
+`
+	if buf.String() != want {
+		t.Errorf("got %q; want %q", buf.String(), want)
+	}
+	if !HasFormat("html") {
+		t.Error("got no html format; want it registered")
+	}
+}
+
+func TestHTMLSections(t *testing.T) {
+	var benches []Bench
+	for i, g := range []string{"x", "x", "y"} {
+		v := NewBench(string(rune('a' + i)))
+		v.Group = g
+		v.Ops = 10
+		benches = append(benches, v)
+	}
+	tests := []struct {
+		headers, names bool
+		want           []string
+	}{
+		{false, false, []string{"</tbody>\n<tbody>\n<tr><td>y</td>"}},
+		{false, true, []string{"<tbody>\n<tr><th colspan=\"5\">x</th></tr>\n<tr><td>a</td>", "</tbody>\n<tbody>\n<tr><th colspan=\"5\">y</th></tr>\n<tr><td>c</td>"}},
+		{true, false, []string{"</tbody>\n<tbody>\n<tr><th>Group</th>"}},
+		{true, true, []string{"</tbody>\n<tbody>\n<tr><th colspan=\"5\">y</th></tr>\n<tr><th>Name</th>"}},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		b := NewHTMLBench(&buf, WithSections())
+		b.Benchmarks = benches
+		b.SectionHeaders(test.headers)
+		b.NameSections(test.names)
+		err := b.Out()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		for _, want := range test.want {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("headers %t, names %t: got %q; want it to contain %q", test.headers, test.names, buf.String(), want)
+			}
+		}
+		if n := strings.Count(buf.String(), "<tbody>"); n != 2 {
+			t.Errorf("headers %t, names %t: got %d tbody; want 2", test.headers, test.names, n)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// HTTPBench benchmarks an http.Handler, in process, or a URL.  Either the
+// Handler or the URL must be set; if both are, the Handler is used and the
+// URL's path and query are used for the requests.
+type HTTPBench struct {
+	Bench                     // the information about the benchmark: Group, Name, etc.
+	Handler     http.Handler  // the handler to benchmark.
+	URL         string        // the URL to benchmark.
+	Method      string        // the request method; default is GET.
+	Header      http.Header   // the request headers; optional.
+	Body        []byte        // the request body; optional.
+	Requests    int           // the number of requests to make; default is 1000.
+	Concurrency int           // the number of concurrent requests; default is 1.
+	Client      *http.Client  // the client used for URLs; default is http.DefaultClient.
+	Timeout     time.Duration // how long each request can take; 0 means no limit.
+}
+
+// HTTPResult holds the results of an HTTPBench.
+type HTTPResult struct {
+	Requests int             // the number of requests made.
+	Errors   int             // the number of requests that errored or had a status >= 400.
+	Elapsed  time.Duration   // the wall time the requests took.
+	Latency  LatencyRecorder // the latency of each request.
+}
+
+// RequestsPerSec returns the number of requests per second.
+func (r *HTTPResult) RequestsPerSec() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / r.Elapsed.Seconds()
+}
+
+// Benches returns r as Benches using b for their information.  The first
+// Bench has the mean latency as its ns/op and the requests/sec in its Note.
+// It is followed by a Bench for each of the p50, p90, and p99 latencies; the
+// percentile is the SubGroup.
+func (r *HTTPResult) Benches(b Bench) []Bench {
+	if b.Iterations == 0 {
+		b.Iterations = 1
+	}
+	b.Ops = int64(r.Requests)
+	main := b
+	main.NsOp = r.Latency.Mean().Nanoseconds()
+	main.Note = fmt.Sprintf("%.2f req/s", r.RequestsPerSec())
+	if r.Errors > 0 {
+		main.Note += fmt.Sprintf("; %d errors", r.Errors)
+	}
+	benches := []Bench{main}
+	for _, p := range []float64{50, 90, 99} {
+		pb := b
+		pb.SubGroup = fmt.Sprintf("p%.0f", p)
+		pb.NsOp = r.Latency.Percentile(p).Nanoseconds()
+		benches = append(benches, pb)
+	}
+	return benches
+}
+
+// Run runs the benchmark.
+func (h *HTTPBench) Run() (*HTTPResult, error) {
+	url := h.URL
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if h.Handler != nil {
+		srv := httptest.NewServer(h.Handler)
+		defer srv.Close()
+		u := srv.URL
+		if h.URL != "" {
+			req, err := http.NewRequest("GET", h.URL, nil)
+			if err != nil {
+				return nil, err
+			}
+			u += req.URL.RequestURI()
+		}
+		url = u
+		client = srv.Client()
+	}
+	if url == "" {
+		return nil, errors.New("http bench: either a Handler or a URL is required")
+	}
+	if h.Timeout > 0 {
+		c := *client
+		c.Timeout = h.Timeout
+		client = &c
+	}
+	method := h.Method
+	if method == "" {
+		method = "GET"
+	}
+	n := h.Requests
+	if n <= 0 {
+		n = 1000
+	}
+	conc := h.Concurrency
+	if conc <= 0 {
+		conc = 1
+	}
+	var res HTTPResult
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	reqs := make(chan struct{}, n)
+	for i := 0; i < n; i++ {
+		reqs <- struct{}{}
+	}
+	close(reqs)
+	start := time.Now()
+	for i := 0; i < conc; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range reqs {
+				d, err := h.do(client, method, url)
+				res.Latency.Record(d)
+				mu.Lock()
+				res.Requests++
+				if err != nil {
+					res.Errors++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	res.Elapsed = time.Since(start)
+	return &res, nil
+}
+
+// do makes a single request and returns its latency.  The response body is
+// read fully so connections are reused.
+func (h *HTTPBench) do(client *http.Client, method, url string) (time.Duration, error) {
+	var body io.Reader
+	if h.Body != nil {
+		body = bytes.NewReader(h.Body)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range h.Header {
+		req.Header[k] = v
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return time.Since(start), err
+	}
+	_, err = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	d := time.Since(start)
+	if err != nil {
+		return d, err
+	}
+	if resp.StatusCode >= 400 {
+		return d, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return d, nil
+}
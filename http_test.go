@@ -0,0 +1,102 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLatencyRecorder(t *testing.T) {
+	var l LatencyRecorder
+	if l.Percentile(50) != 0 {
+		t.Errorf("got %s; want 0 for an empty recorder", l.Percentile(50))
+	}
+	for i := 100; i > 0; i-- {
+		l.Record(time.Duration(i) * time.Millisecond)
+	}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{50, 50 * time.Millisecond},
+		{90, 90 * time.Millisecond},
+		{99, 99 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+	}
+	for _, test := range tests {
+		if got := l.Percentile(test.p); got != test.want {
+			t.Errorf("p%.0f: got %s; want %s", test.p, got, test.want)
+		}
+	}
+	if l.Mean() != 50500*time.Microsecond {
+		t.Errorf("got mean %s; want 50.5ms", l.Mean())
+	}
+
+	// the ranks at, and just past, the boundaries.
+	var r LatencyRecorder
+	for i := 1; i <= 10; i++ {
+		r.Record(time.Duration(i) * time.Millisecond)
+	}
+	for _, test := range []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, 1 * time.Millisecond},
+		{1, 1 * time.Millisecond},
+		{10, 1 * time.Millisecond},
+		{10.1, 2 * time.Millisecond},
+		{40, 4 * time.Millisecond},
+		{41, 5 * time.Millisecond},
+		{45, 5 * time.Millisecond},
+		{70, 7 * time.Millisecond},
+		{71, 8 * time.Millisecond},
+		{90, 9 * time.Millisecond},
+		{91, 10 * time.Millisecond},
+		{100, 10 * time.Millisecond},
+	} {
+		if got := r.Percentile(test.p); got != test.want {
+			t.Errorf("n=10 p%g: got %s; want %s", test.p, got, test.want)
+		}
+	}
+}
+
+func TestHTTPBench(t *testing.T) {
+	h := HTTPBench{
+		Bench: NewBench("hello"),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/hello" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte("hello"))
+		}),
+		URL:         "/hello",
+		Requests:    50,
+		Concurrency: 4,
+	}
+	res, err := h.Run()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if res.Requests != 50 {
+		t.Errorf("got %d requests; want 50", res.Requests)
+	}
+	if res.Errors != 0 {
+		t.Errorf("got %d errors; want 0", res.Errors)
+	}
+	benches := res.Benches(h.Bench)
+	if len(benches) != 4 {
+		t.Fatalf("got %d benches; want 4", len(benches))
+	}
+	if !strings.HasSuffix(benches[0].Note, "req/s") {
+		t.Errorf("got note %q; want req/s", benches[0].Note)
+	}
+	if benches[3].SubGroup != "p99" {
+		t.Errorf("got %q; want p99", benches[3].SubGroup)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"encoding/hex"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet that ULIDs are encoded with.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// RandUUID returns a pseudo-random version 4 UUID, e.g.
+// "9f1c1a46-05bc-4f7d-9c0a-3c1b1f0a8e2d".  It is not suitable for uses that
+// require unpredictable ids.
+func RandUUID() string {
+	return defaultGen.RandUUID()
+}
+
+// RandUUID returns a pseudo-random version 4 UUID.
+func (g *Gen) RandUUID() string {
+	var u [16]byte
+	g.fill(u[:])
+	u[6] = u[6]&0x0f | 0x40 // version 4
+	u[8] = u[8]&0x3f | 0x80 // RFC 4122 variant
+	var b [36]byte
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b[:])
+}
+
+// RandULID returns a ULID for the current time with a pseudo-random entropy
+// component, e.g. "01ARZ3NDEKTSV4RRFFQ69G5FAV".  ULIDs generated in the
+// same millisecond are not guaranteed to sort in the order they were
+// generated.
+func RandULID() string {
+	return defaultGen.RandULID()
+}
+
+// RandULID returns a ULID for the current time with a pseudo-random entropy
+// component.
+func (g *Gen) RandULID() string {
+	return g.RandULIDAt(time.Now())
+}
+
+// RandULIDAt returns a ULID for time t with a pseudo-random entropy
+// component.  Using a fixed t, with a seeded Gen, makes the ULIDs
+// reproducible.
+func RandULIDAt(t time.Time) string {
+	return defaultGen.RandULIDAt(t)
+}
+
+// RandULIDAt returns a ULID for time t with a pseudo-random entropy
+// component.
+func (g *Gen) RandULIDAt(t time.Time) string {
+	// 48 bits of milliseconds followed by 80 bits of entropy.
+	var u [16]byte
+	ms := uint64(t.UnixNano() / int64(time.Millisecond))
+	for i := 5; i >= 0; i-- {
+		u[i] = byte(ms)
+		ms >>= 8
+	}
+	g.fill(u[6:])
+	// the 128 bits are encoded, most significant first, as 26 5-bit
+	// characters; the first character only has 3 bits.
+	var b [26]byte
+	var acc uint
+	var bits uint = 2 // pad the front so the total is 130 bits.
+	n := 0
+	for _, v := range u {
+		acc = acc<<8 | uint(v)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			b[n] = crockford[acc>>bits&0x1f]
+			n++
+		}
+	}
+	return string(b[:])
+}
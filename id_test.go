@@ -0,0 +1,39 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestRandUUID(t *testing.T) {
+	re := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	for i := 0; i < 100; i++ {
+		u := RandUUID()
+		if !re.MatchString(u) {
+			t.Fatalf("got %q; want a version 4 UUID", u)
+		}
+	}
+}
+
+func TestRandULID(t *testing.T) {
+	ts := time.Unix(1469918176, 385000000)
+	u := NewGen(1).RandULIDAt(ts)
+	if len(u) != 26 {
+		t.Fatalf("got %q; want 26 characters", u)
+	}
+	// the timestamp 1469918176385 is 01ARYZ6S41 in Crockford base32.
+	if u[:10] != "01ARYZ6S41" {
+		t.Errorf("got timestamp %q; want 01ARYZ6S41", u[:10])
+	}
+	if !regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`).MatchString(u) {
+		t.Errorf("got %q; want Crockford base32", u)
+	}
+	if a, b := NewGen(1).RandULIDAt(ts), NewGen(1).RandULIDAt(ts); a != b {
+		t.Errorf("got %q and %q; want the same ULID from the same seed and time", a, b)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+// Package influx writes benchmark results to InfluxDB, either as line
+// protocol text or directly using the InfluxDB v2 HTTP API.
+//
+// Each result is a point in the measurement, "benchmark" by default, with
+// the bench's group, sub-group, and name, and the run's host and commit, as
+// tags and these fields:
+//
+//	ns_per_op      nanoseconds per op.
+//	bytes_per_op   bytes allocated per op.
+//	allocs_per_op  allocations per op.
+//	ops            the number of ops performed.
+package influx
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mohae/benchutil"
+)
+
+// DefaultMeasurement is the measurement used when one isn't specified.
+const DefaultMeasurement = "benchmark"
+
+// WriteLineProtocol writes the benches' results to w as line protocol, with
+// nanosecond timestamps, using the measurement; if it's empty,
+// DefaultMeasurement is used.  The points are timestamped with the run's
+// Timestamp, or the current time if it isn't set.  Failed benches aren't
+// written.
+func WriteLineProtocol(w io.Writer, b *benchutil.Benches, measurement string) error {
+	if measurement == "" {
+		measurement = DefaultMeasurement
+	}
+	ts := b.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	var commit string
+	if b.Git != nil {
+		commit = b.Git.Commit
+	}
+	var buf bytes.Buffer
+	for _, v := range b.Benchmarks {
+		if v.Failed() {
+			continue
+		}
+		buf.Reset()
+		buf.WriteString(escapeMeasurement(measurement))
+		// the tags are in key order, which InfluxDB prefers; empty values
+		// aren't allowed so they're skipped.
+		for _, t := range [][2]string{{"commit", commit}, {"group", v.Group}, {"host", b.Hostname}, {"name", v.Name}, {"subgroup", v.SubGroup}} {
+			if t[1] == "" {
+				continue
+			}
+			buf.WriteByte(',')
+			buf.WriteString(t[0])
+			buf.WriteByte('=')
+			buf.WriteString(escape(t[1]))
+		}
+		it := int64(v.Iterations)
+		if it < 1 {
+			it = 1
+		}
+		fmt.Fprintf(&buf, " ns_per_op=%s,bytes_per_op=%s,allocs_per_op=%s,ops=%di %d\n",
+			float(float64(v.NsOp)/float64(it)), float(float64(v.BytesOp)/float64(it)),
+			float(float64(v.AllocsOp)/float64(it)), v.Ops*it, ts.UnixNano())
+		_, err := w.Write(buf.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writer writes benchmark results to InfluxDB using the v2 HTTP API.
+type Writer struct {
+	URL         string       // the InfluxDB URL, e.g. http://localhost:8086.
+	Org         string       // the organization.
+	Bucket      string       // the bucket the points are written to.
+	Token       string       // the API token.
+	Measurement string       // the measurement; default is DefaultMeasurement.
+	Client      *http.Client // the client used; default is http.DefaultClient.
+}
+
+// Write writes the benches' results to the bucket; see WriteLineProtocol.
+func (iw *Writer) Write(b *benchutil.Benches) error {
+	var body bytes.Buffer
+	err := WriteLineProtocol(&body, b, iw.Measurement)
+	if err != nil {
+		return err
+	}
+	q := url.Values{}
+	q.Set("org", iw.Org)
+	q.Set("bucket", iw.Bucket)
+	q.Set("precision", "ns")
+	req, err := http.NewRequest("POST", strings.TrimSuffix(iw.URL, "/")+"/api/v2/write?"+q.Encode(), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if iw.Token != "" {
+		req.Header.Set("Authorization", "Token "+iw.Token)
+	}
+	client := iw.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("influx: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// tagEscaper escapes tag keys and values.
+var tagEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+// measurementEscaper escapes measurement names.
+var measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+
+func escape(s string) string {
+	return tagEscaper.Replace(s)
+}
+
+func escapeMeasurement(s string) string {
+	return measurementEscaper.Replace(s)
+}
+
+// float returns v formatted as a line protocol float.
+func float(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
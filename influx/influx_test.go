@@ -0,0 +1,70 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package influx
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mohae/benchutil"
+)
+
+func testBenches() *benchutil.Benches {
+	b := &benchutil.Benches{Hostname: "test host", Timestamp: time.Unix(1462104000, 0),
+		Git: &benchutil.GitInfo{Commit: "3f7a2c1"}}
+	b.Append(
+		benchutil.Bench{Group: "json", SubGroup: "decode", Name: "small,a=b", Iterations: 4, Result: benchutil.Result{Ops: 10, NsOp: 402, BytesOp: 80, AllocsOp: 8}},
+		benchutil.Bench{Name: "plain", Iterations: 1, Result: benchutil.Result{Ops: 1, NsOp: 5}},
+		benchutil.Bench{Name: "failed", Err: "boom"},
+	)
+	return b
+}
+
+const wantLines = `benchmark,commit=3f7a2c1,group=json,host=test\ host,name=small\,a\=b,subgroup=decode ns_per_op=100.5,bytes_per_op=20,allocs_per_op=2,ops=40i 1462104000000000000
+benchmark,commit=3f7a2c1,host=test\ host,name=plain ns_per_op=5,bytes_per_op=0,allocs_per_op=0,ops=1i 1462104000000000000
+`
+
+func TestWriteLineProtocol(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteLineProtocol(&buf, testBenches(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != wantLines {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), wantLines)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var query, auth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, auth = r.URL.RawQuery, r.Header.Get("Authorization")
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		if r.URL.Query().Get("bucket") == "missing" {
+			http.Error(w, `{"message":"bucket not found"}`, http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	w := &Writer{URL: srv.URL, Org: "acme", Bucket: "bench", Token: "secret"}
+	err := w.Write(testBenches())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "bucket=bench&org=acme&precision=ns" || auth != "Token secret" || body != wantLines {
+		t.Errorf("got query %q, auth %q, body:\n%s", query, auth, body)
+	}
+	w.Bucket = "missing"
+	err = w.Write(testBenches())
+	if err == nil {
+		t.Error("got no error for a missing bucket")
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LatencyRecorder records latencies and calculates their percentiles.  It is
+// safe for concurrent use.
+type LatencyRecorder struct {
+	mu     sync.Mutex
+	d      []time.Duration
+	sorted bool
+}
+
+// Record adds a latency to the recorder.
+func (l *LatencyRecorder) Record(d time.Duration) {
+	l.mu.Lock()
+	l.d = append(l.d, d)
+	l.sorted = false
+	l.mu.Unlock()
+}
+
+// Len returns the number of latencies recorded.
+func (l *LatencyRecorder) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.d)
+}
+
+// Mean returns the mean of the recorded latencies.
+func (l *LatencyRecorder) Mean() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.d) == 0 {
+		return 0
+	}
+	var t time.Duration
+	for _, v := range l.d {
+		t += v
+	}
+	return t / time.Duration(len(l.d))
+}
+
+// Percentile returns the latency at percentile p, 0 < p <= 100, using the
+// nearest rank: the smallest latency that at least p percent of the
+// latencies are less than or equal to.  If nothing was recorded, 0 is
+// returned.
+func (l *LatencyRecorder) Percentile(p float64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.d) == 0 {
+		return 0
+	}
+	if !l.sorted {
+		sort.Slice(l.d, func(i, j int) bool { return l.d[i] < l.d[j] })
+		l.sorted = true
+	}
+	// p*n is exact for whole percentiles, so ranks on a boundary aren't
+	// rounded up.
+	i := int(math.Ceil(p*float64(len(l.d))/100)) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(l.d) {
+		i = len(l.d) - 1
+	}
+	return l.d[i]
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks is the number of clock ticks per second used by
+// /proc/[pid]/stat; USER_HZ is 100 on all the architectures Linux supports.
+const clockTicks = 100
+
+// loadSampleInterval is how long the processes' CPU usage is sampled for
+// when the load is checked.
+const loadSampleInterval = 500 * time.Millisecond
+
+// ProcessCPU holds a process's CPU usage over a sampling interval.
+type ProcessCPU struct {
+	PID  int     // the process id.
+	Name string  // the process's command name.
+	CPU  float64 // the CPU usage, as a percentage of one CPU.
+}
+
+// BusyProcesses samples the CPU usage of every process, other than this one,
+// over the interval and returns the n busiest, in descending order of usage.
+// Processes that didn't use any CPU during the interval aren't included.
+func BusyProcesses(interval time.Duration, n int) ([]ProcessCPU, error) {
+	before, err := procTicks()
+	if err != nil {
+		return nil, err
+	}
+	time.Sleep(interval)
+	after, err := procTicks()
+	if err != nil {
+		return nil, err
+	}
+	var procs []ProcessCPU
+	for pid, a := range after {
+		b, ok := before[pid]
+		if !ok || a.ticks <= b.ticks {
+			continue
+		}
+		cpu := float64(a.ticks-b.ticks) / clockTicks / interval.Seconds() * 100
+		procs = append(procs, ProcessCPU{PID: pid, Name: a.name, CPU: cpu})
+	}
+	sort.Slice(procs, func(i, j int) bool {
+		if procs[i].CPU == procs[j].CPU {
+			return procs[i].PID < procs[j].PID
+		}
+		return procs[i].CPU > procs[j].CPU
+	})
+	if len(procs) > n {
+		procs = procs[:n]
+	}
+	return procs, nil
+}
+
+// procStat is a process's name and total CPU time, in clock ticks.
+type procStat struct {
+	name  string
+	ticks uint64
+}
+
+// procTicks reads the CPU time used by each process from /proc/[pid]/stat.
+// Processes that exit while being read are skipped.
+func procTicks() (map[int]procStat, error) {
+	paths, err := filepath.Glob(filepath.Join(procfs, "[0-9]*/stat"))
+	if err != nil {
+		return nil, err
+	}
+	self := os.Getpid()
+	stats := make(map[int]procStat, len(paths))
+	for _, p := range paths {
+		pid, err := strconv.Atoi(filepath.Base(filepath.Dir(p)))
+		if err != nil || pid == self {
+			continue
+		}
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		s, ok := parseProcStat(string(b))
+		if ok {
+			stats[pid] = s
+		}
+	}
+	return stats, nil
+}
+
+// parseProcStat parses the contents of a /proc/[pid]/stat file.  The command
+// name is in parentheses and may contain spaces, so the fields are counted
+// from the last ')'.
+func parseProcStat(s string) (procStat, bool) {
+	open := strings.IndexByte(s, '(')
+	end := strings.LastIndexByte(s, ')')
+	if open < 0 || end < open {
+		return procStat{}, false
+	}
+	// the fields after the name start with state; utime and stime are the
+	// 14th and 15th fields of the file.
+	fields := strings.Fields(s[end+1:])
+	if len(fields) < 13 {
+		return procStat{}, false
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return procStat{}, false
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return procStat{}, false
+	}
+	return procStat{name: s[open+1 : end], ticks: utime + stime}, true
+}
+
+// LoadWarning returns a warning about the system being busy if the 1 minute
+// load average is above max.  The warning includes the busiest processes, if
+// they could be determined.  If the system isn't busy, or the load average
+// can't be read, an empty string is returned.
+func LoadWarning(max float64) string {
+	l, err := LoadAvg()
+	if err != nil || l[0] <= max {
+		return ""
+	}
+	procs, _ := BusyProcesses(loadSampleInterval, 3)
+	return loadWarning(l, max, procs)
+}
+
+func loadWarning(l [3]float64, max float64, procs []ProcessCPU) string {
+	msg := fmt.Sprintf("system was busy: load average %.2f %.2f %.2f exceeds %.2f", l[0], l[1], l[2], max)
+	if len(procs) > 0 {
+		top := make([]string, len(procs))
+		for i, p := range procs {
+			top[i] = fmt.Sprintf("%s (pid %d, %.0f%%)", p.Name, p.PID, p.CPU)
+		}
+		msg += "; busiest processes: " + strings.Join(top, ", ")
+	}
+	return msg + ": results may not be reproducible"
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "testing"
+
+func TestParseProcStat(t *testing.T) {
+	s, ok := parseProcStat("1234 (my (odd) proc) R 1 1234 1234 0 -1 4194304 100 0 0 0 250 50 0 0 20 0 1 0 100 1000 100\n")
+	if !ok {
+		t.Fatal("got not ok; want ok")
+	}
+	if s.name != "my (odd) proc" {
+		t.Errorf("got name %q; want %q", s.name, "my (odd) proc")
+	}
+	if s.ticks != 300 {
+		t.Errorf("got %d ticks; want 300", s.ticks)
+	}
+	if _, ok := parseProcStat("1234 (truncated"); ok {
+		t.Error("got ok; want not ok")
+	}
+}
+
+func TestLoadWarning(t *testing.T) {
+	l := [3]float64{3.5, 2, 1}
+	got := loadWarning(l, 1, []ProcessCPU{{PID: 42, Name: "make", CPU: 190}, {PID: 7, Name: "chrome", CPU: 35.4}})
+	want := "system was busy: load average 3.50 2.00 1.00 exceeds 1.00; busiest processes: make (pid 42, 190%), chrome (pid 7, 35%): results may not be reproducible"
+	if got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+	got = loadWarning(l, 1, nil)
+	want = "system was busy: load average 3.50 2.00 1.00 exceeds 1.00: results may not be reproducible"
+	if got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
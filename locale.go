@@ -0,0 +1,116 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Locale holds how numbers and dates are formatted for a language, or a
+// language and region.
+type Locale struct {
+	Decimal    string // the decimal separator.
+	Group      string // the digit grouping separator, between each group of 3 digits.
+	DateLayout string // the time.Format layout of dates and times.
+}
+
+// locales are the known locales, by their lower case BCP 47 tag.
+var locales = map[string]Locale{
+	"en":    {".", ",", "2006-01-02 15:04:05 MST"},
+	"en-us": {".", ",", "01/02/2006 3:04:05 PM MST"},
+	"en-gb": {".", ",", "02/01/2006 15:04:05 MST"},
+	"de":    {",", ".", "02.01.2006 15:04:05 MST"},
+	"es":    {",", ".", "02/01/2006 15:04:05 MST"},
+	"fr":    {",", " ", "02/01/2006 15:04:05 MST"},
+	"it":    {",", ".", "02/01/2006 15:04:05 MST"},
+	"nl":    {",", ".", "02-01-2006 15:04:05 MST"},
+	"pt":    {",", " ", "02/01/2006 15:04:05 MST"},
+	"pt-br": {",", ".", "02/01/2006 15:04:05 MST"},
+	"ru":    {",", " ", "02.01.2006 15:04:05 MST"},
+	"pl":    {",", " ", "02.01.2006 15:04:05 MST"},
+	"sv":    {",", " ", "2006-01-02 15:04:05 MST"},
+	"ja":    {".", ",", "2006/01/02 15:04:05 MST"},
+	"zh":    {".", ",", "2006/01/02 15:04:05 MST"},
+	"ko":    {".", ",", "2006.01.02 15:04:05 MST"},
+}
+
+// LookupLocale returns the Locale for the tag, e.g. "de" or "en-GB"; if
+// there isn't one for the tag's region, the language's is returned.
+func LookupLocale(tag string) (Locale, error) {
+	t := strings.ToLower(strings.Replace(tag, "_", "-", -1))
+	if l, ok := locales[t]; ok {
+		return l, nil
+	}
+	if i := strings.Index(t, "-"); i > 0 {
+		if l, ok := locales[t[:i]]; ok {
+			return l, nil
+		}
+	}
+	return Locale{}, fmt.Errorf("benchutil: unknown locale %q", tag)
+}
+
+// SetLocale sets the locale the numbers, and the run's timestamp, are
+// formatted with in the txt and md output, e.g. "1.234.567" for "de"; CSV
+// output isn't localized so it can be parsed.  An empty tag restores the
+// default: ungrouped numbers and RFC 3339 timestamps.
+func (b *Benches) SetLocale(tag string) error {
+	if tag == "" {
+		b.locale, b.localeTag = nil, ""
+		return nil
+	}
+	l, err := LookupLocale(tag)
+	if err != nil {
+		return err
+	}
+	b.locale, b.localeTag = &l, tag
+	return nil
+}
+
+// FormatNumber returns the number, e.g. "1234567.5", in the locale's
+// format, e.g. "1,234,567.5".  Anything following the digits, e.g. a unit,
+// is left as it is.
+func (l Locale) FormatNumber(s string) string {
+	var sign string
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	end := len(s)
+	for i, c := range s {
+		if (c < '0' || c > '9') && c != '.' {
+			end = i
+			break
+		}
+	}
+	num, rest := s[:end], s[end:]
+	var frac string
+	if i := strings.Index(num, "."); i >= 0 {
+		num, frac = num[:i], l.Decimal+num[i+1:]
+	}
+	if len(num) > 3 && l.Group != "" {
+		var buf strings.Builder
+		lead := len(num) % 3
+		if lead > 0 {
+			buf.WriteString(num[:lead])
+		}
+		for i := lead; i < len(num); i += 3 {
+			if buf.Len() > 0 {
+				buf.WriteString(l.Group)
+			}
+			buf.WriteString(num[i : i+3])
+		}
+		num = buf.String()
+	}
+	return sign + num + frac + rest
+}
+
+// localize returns the number in b's locale; without a locale it's
+// returned as it is.
+func (b *Benches) localize(s string) string {
+	if b.locale == nil {
+		return s
+	}
+	return b.locale.FormatNumber(s)
+}
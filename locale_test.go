@@ -0,0 +1,72 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatNumber(t *testing.T) {
+	de, err := LookupLocale("de_DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	en, _ := LookupLocale("en")
+	tests := []struct {
+		l    Locale
+		s    string
+		want string
+	}{
+		{en, "0", "0"},
+		{en, "123", "123"},
+		{en, "1234", "1,234"},
+		{en, "1234567.25", "1,234,567.25"},
+		{en, "-1234567", "-1,234,567"},
+		{de, "1234567.25", "1.234.567,25"},
+		{de, "1234 ns/op", "1.234 ns/op"},
+	}
+	for _, test := range tests {
+		if got := test.l.FormatNumber(test.s); got != test.want {
+			t.Errorf("%q: got %q; want %q", test.s, got, test.want)
+		}
+	}
+	if _, err := LookupLocale("xx"); err == nil {
+		t.Error("got no error for an unknown locale")
+	}
+}
+
+func TestSetLocale(t *testing.T) {
+	benches := []Bench{{Name: "a", Iterations: 1, Result: Result{Ops: 1234567, NsOp: 1500, BytesOp: 16, AllocsOp: 1}}}
+	ts := time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+	var txt bytes.Buffer
+	s := NewStringBench(&txt)
+	if err := s.SetLocale("de"); err != nil {
+		t.Fatal(err)
+	}
+	SetBenches(s, &Benches{Hostname: "host", Timestamp: ts, Benchmarks: benches})
+	s.Out()
+	if !strings.Contains(txt.String(), "Timestamp:   01.06.2016 12:00:00 UTC") {
+		t.Errorf("got %q; want the localized timestamp", txt.String())
+	}
+	if !strings.Contains(txt.String(), "a     1.234.567  1.500") {
+		t.Errorf("got %q; want the localized, aligned, numbers", txt.String())
+	}
+
+	var csv bytes.Buffer
+	c := NewCSVBench(&csv)
+	c.SetLocale("de")
+	SetBenches(c, &Benches{Benchmarks: benches})
+	c.Out()
+	if !strings.Contains(csv.String(), "a,1234567,1500,16,1") {
+		t.Errorf("got %q; want the csv unlocalized", csv.String())
+	}
+
+	if err := s.SetLocale(""); err != nil || s.locale != nil {
+		t.Errorf("got %v, %v; want the locale cleared", err, s.locale)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "net"
+
+// RandIPv4 returns a pseudo-random IPv4 address.
+func RandIPv4() net.IP {
+	return defaultGen.RandIPv4()
+}
+
+// RandIPv4 returns a pseudo-random IPv4 address.
+func (g *Gen) RandIPv4() net.IP {
+	ip := make(net.IP, net.IPv4len)
+	g.fill(ip)
+	return ip
+}
+
+// RandIPv6 returns a pseudo-random IPv6 address.
+func RandIPv6() net.IP {
+	return defaultGen.RandIPv6()
+}
+
+// RandIPv6 returns a pseudo-random IPv6 address.
+func (g *Gen) RandIPv6() net.IP {
+	ip := make(net.IP, net.IPv6len)
+	g.fill(ip)
+	return ip
+}
+
+// RandMAC returns a pseudo-random 48-bit MAC address.  The address is a
+// locally administered unicast address, so it can't collide with a real
+// device's address.
+func RandMAC() net.HardwareAddr {
+	return defaultGen.RandMAC()
+}
+
+// RandMAC returns a pseudo-random, locally administered, unicast, 48-bit MAC
+// address.
+func (g *Gen) RandMAC() net.HardwareAddr {
+	mac := make(net.HardwareAddr, 6)
+	g.fill(mac)
+	mac[0] = mac[0]&^0x01 | 0x02
+	return mac
+}
+
+// RandCIDRv4 returns a pseudo-random IPv4 network with a prefix length in
+// [minBits, maxBits], e.g. 10.42.128.0/18.  It panics if the prefix lengths
+// aren't in [0, 32] or maxBits < minBits.
+func RandCIDRv4(minBits, maxBits int) *net.IPNet {
+	return defaultGen.RandCIDRv4(minBits, maxBits)
+}
+
+// RandCIDRv4 returns a pseudo-random IPv4 network with a prefix length in
+// [minBits, maxBits].  See the RandCIDRv4 function.
+func (g *Gen) RandCIDRv4(minBits, maxBits int) *net.IPNet {
+	return g.randCIDR(g.RandIPv4(), 32, minBits, maxBits)
+}
+
+// RandCIDRv6 returns a pseudo-random IPv6 network with a prefix length in
+// [minBits, maxBits], e.g. 2001:db8:7f00::/40.  It panics if the prefix
+// lengths aren't in [0, 128] or maxBits < minBits.
+func RandCIDRv6(minBits, maxBits int) *net.IPNet {
+	return defaultGen.RandCIDRv6(minBits, maxBits)
+}
+
+// RandCIDRv6 returns a pseudo-random IPv6 network with a prefix length in
+// [minBits, maxBits].  See the RandCIDRv6 function.
+func (g *Gen) RandCIDRv6(minBits, maxBits int) *net.IPNet {
+	return g.randCIDR(g.RandIPv6(), 128, minBits, maxBits)
+}
+
+func (g *Gen) randCIDR(ip net.IP, bits, minBits, maxBits int) *net.IPNet {
+	if minBits < 0 || maxBits > bits || maxBits < minBits {
+		panic("invalid argument to RandCIDR")
+	}
+	mask := net.CIDRMask(minBits+g.RandIntn(maxBits-minBits+1), bits)
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+}
+
+// RandPort returns a pseudo-random port number in [1, 65535].
+func RandPort() int {
+	return defaultGen.RandPort()
+}
+
+// RandPort returns a pseudo-random port number in [1, 65535].
+func (g *Gen) RandPort() int {
+	return 1 + g.RandIntn(65535)
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"net"
+	"testing"
+)
+
+func TestRandNet(t *testing.T) {
+	g := NewGen(2)
+	for i := 0; i < 100; i++ {
+		if ip := net.ParseIP(g.RandIPv4().String()); ip == nil || ip.To4() == nil {
+			t.Fatalf("got %v; want an IPv4 address", ip)
+		}
+		if ip := g.RandIPv6(); len(ip) != net.IPv6len {
+			t.Fatalf("got %v; want an IPv6 address", ip)
+		}
+		mac := g.RandMAC()
+		if _, err := net.ParseMAC(mac.String()); err != nil || mac[0]&0x03 != 0x02 {
+			t.Fatalf("got %v; want a locally administered unicast MAC", mac)
+		}
+		n := g.RandCIDRv4(8, 24)
+		ones, bits := n.Mask.Size()
+		if bits != 32 || ones < 8 || ones > 24 {
+			t.Fatalf("got %v; want a /8 to /24 IPv4 network", n)
+		}
+		_, parsed, err := net.ParseCIDR(n.String())
+		if err != nil || !parsed.IP.Equal(n.IP) {
+			t.Fatalf("got %v; want the address masked to the network", n)
+		}
+		if ones, bits := g.RandCIDRv6(48, 64).Mask.Size(); bits != 128 || ones < 48 || ones > 64 {
+			t.Fatalf("got /%d of %d bits; want a /48 to /64 IPv6 network", ones, bits)
+		}
+		if p := g.RandPort(); p < 1 || p > 65535 {
+			t.Fatalf("got port %d; want [1, 65535]", p)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+// Package notify posts a summary of a benchmark run, and any regressions,
+// to a Slack or generic webhook.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mohae/benchutil"
+	"github.com/mohae/benchutil/history"
+)
+
+// DefaultTemplate is the message template used when a Webhook doesn't have
+// one; see Summary for its fields.
+var DefaultTemplate = template.Must(template.New("message").Parse(
+	`{{.Name}}: {{.Benchmarks}} benchmarks on {{.Hostname}}{{with .Commit}} at {{.}}{{end}}{{if .Failed}}, {{.Failed}} failed{{end}}{{if .Regressions}}, {{len .Regressions}} regressed{{end}}
+{{- range .Regressions}}
+regression: {{.}}{{end}}
+{{- range .Warnings}}
+warning: {{.}}{{end}}
+{{- with .ReportURL}}
+report: {{.}}{{end}}`))
+
+// Summary is the summary of a run that's posted.  For generic webhooks it's
+// posted as JSON, along with the message.
+type Summary struct {
+	Name        string               `json:"name"`                  // the Benches' Name; "benchmarks" if it isn't set.
+	Hostname    string               `json:"hostname"`              // the host the benchmarks were run on.
+	Commit      string               `json:"commit,omitempty"`      // the commit that was benchmarked; if it was captured.
+	Timestamp   time.Time            `json:"timestamp"`             // when the benchmarks were run.
+	Benchmarks  int                  `json:"benchmarks"`            // the number of benchmarks.
+	Failed      int                  `json:"failed"`                // the number of benchmarks that failed.
+	Warnings    []string             `json:"warnings,omitempty"`    // the warnings about the run's conditions.
+	Regressions []history.Regression `json:"regressions,omitempty"` // the regressions; see history.CheckRegressions.
+	ReportURL   string               `json:"report_url,omitempty"`  // a link to the full report; optional.
+	Message     string               `json:"message"`               // the summary, formatted with the template.
+}
+
+// NewSummary returns the summary of the benches and regressions.  The
+// Message isn't set.
+func NewSummary(b *benchutil.Benches, regressions []history.Regression, reportURL string) Summary {
+	s := Summary{
+		Name:        b.Name,
+		Hostname:    b.Hostname,
+		Timestamp:   b.Timestamp,
+		Benchmarks:  len(b.Benchmarks),
+		Warnings:    b.Warnings,
+		Regressions: regressions,
+		ReportURL:   reportURL,
+	}
+	if s.Name == "" {
+		s.Name = "benchmarks"
+	}
+	if b.Git != nil {
+		s.Commit = b.Git.String()
+	}
+	for _, v := range b.Benchmarks {
+		if v.Failed() {
+			s.Failed++
+		}
+	}
+	return s
+}
+
+// Webhook posts run summaries to a webhook URL.
+type Webhook struct {
+	URL       string             // the webhook's URL.
+	Slack     bool               // post in the format of a Slack incoming webhook, {"text": message}.
+	ReportURL string             // a link to the full report, e.g. from the upload package; optional.
+	Template  *template.Template // the message template; default is DefaultTemplate.
+	Client    *http.Client       // the client used; default is http.DefaultClient.
+}
+
+// Notify posts the summary of the benches and regressions.  Call it after
+// the benches have been output and checked for regressions; regressions can
+// be nil.  A response status other than 2xx is an error.
+func (w *Webhook) Notify(b *benchutil.Benches, regressions []history.Regression) error {
+	s := NewSummary(b, regressions, w.ReportURL)
+	tmpl := w.Template
+	if tmpl == nil {
+		tmpl = DefaultTemplate
+	}
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, s)
+	if err != nil {
+		return fmt.Errorf("notify: template: %s", err)
+	}
+	s.Message = buf.String()
+	var body []byte
+	if w.Slack {
+		body, err = json.Marshal(struct {
+			Text string `json:"text"`
+		}{s.Message})
+	} else {
+		body, err = json.Marshal(s)
+	}
+	if err != nil {
+		return err
+	}
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notify: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
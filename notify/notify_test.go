@@ -0,0 +1,60 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mohae/benchutil"
+	"github.com/mohae/benchutil/history"
+)
+
+func TestWebhook(t *testing.T) {
+	var got map[string]interface{}
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	b := &benchutil.Benches{Name: "json", Hostname: "test", Timestamp: time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC),
+		Git: &benchutil.GitInfo{Commit: "3f7a2c1"}}
+	b.Append(benchutil.Bench{Name: "a"}, benchutil.Bench{Name: "b", Err: "boom"})
+	regs := []history.Regression{{Name: "json/a", Metric: history.NsOp, Baseline: 100, Value: 150, Change: 0.5}}
+
+	w := &Webhook{URL: srv.URL, Slack: true, ReportURL: "https://example.com/r.md"}
+	err := w.Notify(b, regs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "json: 2 benchmarks on test at 3f7a2c1, 1 failed, 1 regressed\nregression: json/a: ns/op 100 -> 150 (+50.0%)\nreport: https://example.com/r.md"
+	if got["text"] != want {
+		t.Errorf("got %q; want %q", got["text"], want)
+	}
+
+	w.Slack = false
+	err = w.Notify(b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["message"] != "json: 2 benchmarks on test at 3f7a2c1, 1 failed\nreport: https://example.com/r.md" {
+		t.Errorf("got message %q", got["message"])
+	}
+	if got["benchmarks"] != 2.0 || got["failed"] != 1.0 || got["commit"] != "3f7a2c1" {
+		t.Errorf("got %v", got)
+	}
+
+	status = http.StatusNotFound
+	err = w.Notify(b, nil)
+	if err == nil {
+		t.Error("got no error for a 404")
+	}
+}
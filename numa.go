@@ -0,0 +1,35 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NUMANode holds information about a NUMA node.
+type NUMANode struct {
+	ID   int    `json:"id"`   // the node number.
+	CPUs string `json:"cpus"` // the node's CPUs as a cpulist, e.g. 0-7,16-23.
+}
+
+// getNUMANodes gets the NUMA nodes, and their CPUs, from sysfs.  If the
+// system doesn't expose NUMA information, nil is returned.
+func getNUMANodes() []NUMANode {
+	paths, _ := filepath.Glob(filepath.Join(sysfs, "devices/system/node/node[0-9]*"))
+	var nodes []NUMANode
+	for _, p := range paths {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(p), "node"))
+		if err != nil {
+			continue
+		}
+		cpus, _ := readSysfsValue(filepath.Join("devices/system/node", filepath.Base(p), "cpulist"))
+		nodes = append(nodes, NUMANode{ID: id, CPUs: cpus})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetNUMANodes(t *testing.T) {
+	restore := fakeSysfs(t, map[string]string{
+		"devices/system/node/node1/cpulist": "8-15,24-31\n",
+		"devices/system/node/node0/cpulist": "0-7,16-23\n",
+		"devices/system/node/possible":      "0-1\n",
+	})
+	defer restore()
+	nodes := getNUMANodes()
+	want := []NUMANode{{0, "0-7,16-23"}, {1, "8-15,24-31"}}
+	if len(nodes) != len(want) {
+		t.Fatalf("got %d nodes; want %d", len(nodes), len(want))
+	}
+	for i := range want {
+		if nodes[i] != want[i] {
+			t.Errorf("%d: got %+v; want %+v", i, nodes[i], want[i])
+		}
+	}
+	s := SysInfo{NUMANodes: nodes}
+	if !strings.Contains(s.DetailedString(), "NUMA nodes: 2\nNode 0:     0-7,16-23\n") {
+		t.Errorf("got %q; want the NUMA nodes", s.DetailedString())
+	}
+}
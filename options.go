@@ -0,0 +1,165 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+// Option configures a Benchmarker's output when it's created, e.g.
+//
+//	NewMDBench(w, WithSystemInfo(), WithSections(), WithPadding(4))
+//
+// Each option is the same as calling its setter after creating the
+// Benchmarker.
+type Option func(*Benches)
+
+// WithOpsColumnDesc includes the ops information in each ops column's
+// result; see IncludeOpsColumnDesc.
+func WithOpsColumnDesc() Option {
+	return func(b *Benches) { b.includeOpsColumnDesc = true }
+}
+
+// WithSystemInfo includes the basic system info in the output; see
+// IncludeSystemInfo.
+func WithSystemInfo() Option {
+	return func(b *Benches) { b.includeSystemInfo = true }
+}
+
+// WithDetailedSystemInfo includes the detailed system info in the output;
+// see IncludeDetailedSystemInfo.
+func WithDetailedSystemInfo() Option {
+	return func(b *Benches) { b.includeDetailedSystemInfo = true }
+}
+
+// WithGPUInfo enumerates the GPUs as part of the system info; see
+// IncludeGPUInfo.
+func WithGPUInfo() Option {
+	return func(b *Benches) { b.includeGPUInfo = true }
+}
+
+// WithDiskInfo includes the block devices in the system info; see
+// IncludeDiskInfo.
+func WithDiskInfo() Option {
+	return func(b *Benches) { b.includeDiskInfo = true }
+}
+
+// WithCSVPreamble writes the records that aren't benchmarks, e.g. the run
+// info, in csv output; see CSVPreamble.
+func WithCSVPreamble() Option {
+	return func(b *Benches) { b.csvPreamble = true }
+}
+
+// WithSections makes a section for each group; see SectionPerGroup.
+func WithSections() Option {
+	return func(b *Benches) { b.sectionPerGroup = true }
+}
+
+// WithSectionHeaders gives each section its own column headers; see
+// SectionHeaders.
+func WithSectionHeaders() Option {
+	return func(b *Benches) { b.sectionHeaders = true }
+}
+
+// WithNameSections uses the group names as the section names; see
+// NameSections.
+func WithNameSections() Option {
+	return func(b *Benches) { b.nameSections = true }
+}
+
+// WithPadding sets the number of spaces between columns; see
+// SetColumnPadding.
+func WithPadding(i int) Option {
+	return func(b *Benches) { b.columnPadding = i }
+}
+
+// WithHeaders sets the column headers; empty headers are left as they are.
+func WithHeaders(h HeaderConfig) Option {
+	return func(b *Benches) {
+		for _, v := range []struct {
+			s   string
+			dst *string
+		}{
+			{h.Group, &b.header.Group},
+			{h.SubGroup, &b.header.SubGroup},
+			{h.Name, &b.header.Name},
+			{h.Desc, &b.header.Desc},
+			{h.Ops, &b.header.Ops},
+			{h.NsOp, &b.header.NsOp},
+			{h.BytesOp, &b.header.BytesOp},
+			{h.AllocsOp, &b.header.AllocsOp},
+			{h.Note, &b.header.Note},
+		} {
+			if v.s != "" {
+				*v.dst = v.s
+			}
+		}
+	}
+}
+
+// WithHiddenColumns leaves the columns out of the output; see HideColumns.
+func WithHiddenColumns(cols ...string) Option {
+	return func(b *Benches) { b.HideColumns(cols...) }
+}
+
+// WithRowFormatter sets how the cells are formatted; see SetRowFormatter.
+func WithRowFormatter(f RowFormatter) Option {
+	return func(b *Benches) { b.rowFormatter = f }
+}
+
+// WithTitle sets the report's title; see SetTitle.
+func WithTitle(s string) Option {
+	return func(b *Benches) { b.title = s }
+}
+
+// WithCaption sets the table's caption; see SetCaption.
+func WithCaption(s string) Option {
+	return func(b *Benches) { b.caption = s }
+}
+
+// WithFooter sets the report's footer; see SetFooter.
+func WithFooter(s string) Option {
+	return func(b *Benches) { b.footer = s }
+}
+
+// WithLocale sets the locale the numbers, and the run's timestamp, are
+// formatted with; see SetLocale.  If the locale isn't known, it's ignored
+// and Out returns the error; check the tag with LookupLocale, e.g. for tags
+// from a flag, to report it earlier.
+func WithLocale(tag string) Option {
+	return func(b *Benches) {
+		err := b.SetLocale(tag)
+		if err != nil {
+			b.optErr = err
+		}
+	}
+}
+
+// WithNsOpAsDuration outputs the NsOp column as durations, e.g. 1.23µs; see
+// NsOpAsDuration.
+func WithNsOpAsDuration() Option {
+	return func(b *Benches) { b.nsOpDuration = true }
+}
+
+// WithSortedAppend keeps the benches in Group, SubGroup, Name order as
+// they're appended; see SortedAppend.
+func WithSortedAppend() Option {
+	return func(b *Benches) { b.sortedAppend = true }
+}
+
+// WithAggregation sets how the benches' samples are combined for output;
+// see SetAggregation.
+func WithAggregation(a Aggregation) Option {
+	return func(b *Benches) { b.aggregation = a }
+}
+
+// WithParallelism sets the number of goroutines the rows are formatted with;
+// see SetParallelism.
+func WithParallelism(n int) Option {
+	return func(b *Benches) { b.parallelism = n }
+}
+
+// apply applies the options to b.
+func (b *Benches) apply(opts []Option) {
+	for _, opt := range opts {
+		opt(b)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestOptions(t *testing.T) {
+	b := NewMDBench(ioutil.Discard, WithSystemInfo(), WithDetailedSystemInfo(), WithGPUInfo(), WithDiskInfo(), WithOpsColumnDesc(), WithSections(), WithSectionHeaders(), WithNameSections(), WithPadding(4), WithHiddenColumns("desc"))
+	if !b.includeSystemInfo || !b.includeDetailedSystemInfo || !b.includeGPUInfo || !b.includeDiskInfo || !b.includeOpsColumnDesc {
+		t.Errorf("got %+v; want the includes set", b.Benches)
+	}
+	if !b.sectionPerGroup || !b.sectionHeaders || !b.nameSections {
+		t.Error("got the section settings unset; want them set")
+	}
+	if b.columnPadding != 4 {
+		t.Errorf("got padding %d; want 4", b.columnPadding)
+	}
+	if !b.hidden["desc"] {
+		t.Error("got desc not hidden; want it hidden")
+	}
+	if b.SectionHeaderHash != "####" {
+		t.Errorf("got section header hash %q; want the default", b.SectionHeaderHash)
+	}
+}
+
+func TestWithHeaders(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewStringBench(&buf, WithHeaders(HeaderConfig{NsOp: "ns per op"}))
+	if b.header.NsOp != "ns per op" || b.header.Ops != "Ops" {
+		t.Errorf("got %+v; want ns/Op replaced and the rest left as they are", b.header)
+	}
+	b.Append(Bench{Name: "decode", Iterations: 1, Result: Result{Ops: 10, NsOp: 5}})
+	err := b.Out()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "ns per op") {
+		t.Errorf("got %q; want the custom header", buf.String())
+	}
+}
+
+func TestWithDocument(t *testing.T) {
+	b := NewMDBench(ioutil.Discard, WithTitle("title"), WithCaption("caption"), WithFooter("footer"))
+	if b.title != "title" || b.caption != "caption" || b.footer != "footer" {
+		t.Errorf("got %q, %q, and %q; want the title, caption, and footer set", b.title, b.caption, b.footer)
+	}
+}
+
+func TestWithLocale(t *testing.T) {
+	b := NewStringBench(ioutil.Discard, WithLocale("de"))
+	if b.locale == nil || b.locale.Decimal != "," || b.localeTag != "de" {
+		t.Errorf("got %+v, %q; want the de locale", b.locale, b.localeTag)
+	}
+	// an unknown locale is ignored and reported by Out.
+	for _, format := range []string{"txt", "csv", "md", "html"} {
+		var buf bytes.Buffer
+		b, err := NewFormat(format, &buf, WithLocale("xx"))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", format, err)
+		}
+		err = b.Out()
+		if err == nil || !strings.Contains(err.Error(), `unknown locale "xx"`) {
+			t.Errorf("%s: got %v; want an unknown locale error", format, err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("%s: got %q; want no output", format, buf.String())
+		}
+	}
+	s := NewStringBench(ioutil.Discard, WithLocale("xx"))
+	if s.locale != nil || s.localeTag != "" {
+		t.Errorf("got %+v, %q; want no locale", s.locale, s.localeTag)
+	}
+	// applying options replaces it.
+	o := Options{Locale: "fr"}
+	if err := o.Apply(s); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := s.Out(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestWithNsOpAsDuration(t *testing.T) {
+	b := NewStringBench(ioutil.Discard, WithNsOpAsDuration())
+	v := NewBench("a")
+	v.Iterations, v.NsOp = 1, 1500
+	if s := b.NsOpString(v); s != "1.5µs" {
+		t.Errorf("got %q; want %q", s, "1.5µs")
+	}
+}
+
+func TestNewFormatOptions(t *testing.T) {
+	b, err := NewFormat("csv", ioutil.Discard, WithPadding(3), WithSystemInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := b.(*CSVBench)
+	if c.columnPadding != 3 || !c.includeSystemInfo {
+		t.Errorf("got padding %d and system info %t; want 3 and true", c.columnPadding, c.includeSystemInfo)
+	}
+	if p := NewBenches(WithPadding(5)); p.columnPadding != 5 {
+		t.Errorf("got padding %d; want 5", p.columnPadding)
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+// Package otlp exports benchmark results as OpenTelemetry metrics to an
+// OTLP/HTTP endpoint, e.g. an OpenTelemetry Collector.
+//
+// Each result is a data point of a gauge per metric, with the bench's
+// group, sub-group, and name as attributes:
+//
+//	benchmark.ns_per_op       nanoseconds per op.
+//	benchmark.bytes_per_op    bytes allocated per op.
+//	benchmark.allocs_per_op   allocations per op.
+//	benchmark.ops             the number of ops performed.
+//
+// The metrics are sent using the OTLP JSON encoding so the OpenTelemetry
+// SDK isn't needed.
+package otlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mohae/benchutil"
+)
+
+// DefaultEndpoint is the default OTLP/HTTP endpoint of a local collector.
+const DefaultEndpoint = "http://localhost:4318"
+
+// ScopeName is the instrumentation scope of the exported metrics.
+const ScopeName = "github.com/mohae/benchutil/otlp"
+
+// Exporter sends benchmark results to an OTLP/HTTP endpoint.
+type Exporter struct {
+	Endpoint    string       // the endpoint; /v1/metrics is appended to it.  Default is DefaultEndpoint.
+	ServiceName string       // the service.name resource attribute; default is "benchmarks".
+	Header      http.Header  // headers added to each request, e.g. for authentication; optional.
+	Client      *http.Client // the client used; default is http.DefaultClient.
+}
+
+// Export sends the benches' results.  The run's hostname, and commit if it
+// was captured, are resource attributes; the data points are timestamped
+// with the run's Timestamp, or the current time if it isn't set.  Failed
+// benches aren't exported.
+func (e *Exporter) Export(b *benchutil.Benches) error {
+	body, err := json.Marshal(request(b, e.ServiceName))
+	if err != nil {
+		return err
+	}
+	endpoint := e.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	url := strings.TrimSuffix(endpoint, "/") + "/v1/metrics"
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range e.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("otlp: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// The OTLP JSON types; only what's needed for gauges is included.
+type (
+	exportRequest struct {
+		ResourceMetrics []resourceMetrics `json:"resourceMetrics"`
+	}
+	resourceMetrics struct {
+		Resource     resource       `json:"resource"`
+		ScopeMetrics []scopeMetrics `json:"scopeMetrics"`
+	}
+	resource struct {
+		Attributes []keyValue `json:"attributes"`
+	}
+	scopeMetrics struct {
+		Scope   scope    `json:"scope"`
+		Metrics []metric `json:"metrics"`
+	}
+	scope struct {
+		Name string `json:"name"`
+	}
+	metric struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Unit        string `json:"unit"`
+		Gauge       gauge  `json:"gauge"`
+	}
+	gauge struct {
+		DataPoints []dataPoint `json:"dataPoints"`
+	}
+	dataPoint struct {
+		Attributes   []keyValue `json:"attributes"`
+		TimeUnixNano string     `json:"timeUnixNano"` // int64s are strings in OTLP JSON.
+		AsDouble     float64    `json:"asDouble"`
+	}
+	keyValue struct {
+		Key   string   `json:"key"`
+		Value anyValue `json:"value"`
+	}
+	anyValue struct {
+		StringValue string `json:"stringValue"`
+	}
+)
+
+// metricDefs are the exported metrics and how their values are computed.
+// NsOp, BytesOp, and AllocsOp are totals over the iterations; Ops is per
+// iteration.
+var metricDefs = []struct {
+	name, desc, unit string
+	value            func(b benchutil.Bench, it float64) float64
+}{
+	{"benchmark.ns_per_op", "Nanoseconds per op.", "ns", func(b benchutil.Bench, it float64) float64 { return float64(b.NsOp) / it }},
+	{"benchmark.bytes_per_op", "Bytes allocated per op.", "By", func(b benchutil.Bench, it float64) float64 { return float64(b.BytesOp) / it }},
+	{"benchmark.allocs_per_op", "Allocations per op.", "{allocation}", func(b benchutil.Bench, it float64) float64 { return float64(b.AllocsOp) / it }},
+	{"benchmark.ops", "The number of ops performed.", "{op}", func(b benchutil.Bench, it float64) float64 { return float64(b.Ops) * it }},
+}
+
+// request returns the export request for the benches.
+func request(b *benchutil.Benches, service string) exportRequest {
+	if service == "" {
+		service = "benchmarks"
+	}
+	attrs := []keyValue{attr("service.name", service)}
+	if b.Hostname != "" {
+		attrs = append(attrs, attr("host.name", b.Hostname))
+	}
+	if b.Name != "" {
+		attrs = append(attrs, attr("benchmark.set", b.Name))
+	}
+	if b.Git != nil && b.Git.Commit != "" {
+		attrs = append(attrs, attr("vcs.revision", b.Git.Commit))
+	}
+	ts := b.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	nanos := strconv.FormatInt(ts.UnixNano(), 10)
+	metrics := make([]metric, len(metricDefs))
+	for i, def := range metricDefs {
+		metrics[i] = metric{Name: def.name, Description: def.desc, Unit: def.unit}
+	}
+	for _, v := range b.Benchmarks {
+		if v.Failed() {
+			continue
+		}
+		it := float64(v.Iterations)
+		if it < 1 {
+			it = 1
+		}
+		pattrs := []keyValue{attr("group", v.Group), attr("subgroup", v.SubGroup), attr("name", v.Name)}
+		for i, def := range metricDefs {
+			metrics[i].Gauge.DataPoints = append(metrics[i].Gauge.DataPoints, dataPoint{
+				Attributes:   pattrs,
+				TimeUnixNano: nanos,
+				AsDouble:     def.value(v, it),
+			})
+		}
+	}
+	return exportRequest{ResourceMetrics: []resourceMetrics{{
+		Resource:     resource{Attributes: attrs},
+		ScopeMetrics: []scopeMetrics{{Scope: scope{Name: ScopeName}, Metrics: metrics}},
+	}}}
+}
+
+func attr(k, v string) keyValue {
+	return keyValue{Key: k, Value: anyValue{StringValue: v}}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package otlp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mohae/benchutil"
+)
+
+func TestExport(t *testing.T) {
+	var path, auth string
+	var got exportRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	b := &benchutil.Benches{Name: "json", Hostname: "test", Timestamp: time.Unix(1462104000, 0),
+		Git: &benchutil.GitInfo{Commit: "3f7a2c1"}}
+	b.Append(
+		benchutil.Bench{Group: "json", SubGroup: "decode", Name: "small", Iterations: 4, Result: benchutil.Result{Ops: 10, NsOp: 400, BytesOp: 80, AllocsOp: 8}},
+		benchutil.Bench{Name: "failed", Err: "boom"},
+	)
+	e := &Exporter{Endpoint: srv.URL + "/", Header: http.Header{"Authorization": {"Bearer token"}}}
+	err := e.Export(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/v1/metrics" || auth != "Bearer token" {
+		t.Errorf("got path %q, auth %q", path, auth)
+	}
+	if len(got.ResourceMetrics) != 1 {
+		t.Fatalf("got %d resource metrics; want 1", len(got.ResourceMetrics))
+	}
+	rm := got.ResourceMetrics[0]
+	want := []keyValue{attr("service.name", "benchmarks"), attr("host.name", "test"), attr("benchmark.set", "json"), attr("vcs.revision", "3f7a2c1")}
+	if len(rm.Resource.Attributes) != len(want) {
+		t.Fatalf("got %v; want %v", rm.Resource.Attributes, want)
+	}
+	for i := range want {
+		if rm.Resource.Attributes[i] != want[i] {
+			t.Errorf("got %v; want %v", rm.Resource.Attributes[i], want[i])
+		}
+	}
+	metrics := rm.ScopeMetrics[0].Metrics
+	values := map[string]float64{"benchmark.ns_per_op": 100, "benchmark.bytes_per_op": 20, "benchmark.allocs_per_op": 2, "benchmark.ops": 40}
+	if len(metrics) != len(values) {
+		t.Fatalf("got %d metrics; want %d", len(metrics), len(values))
+	}
+	for _, m := range metrics {
+		if len(m.Gauge.DataPoints) != 1 {
+			t.Errorf("%s: got %d data points; want 1", m.Name, len(m.Gauge.DataPoints))
+			continue
+		}
+		dp := m.Gauge.DataPoints[0]
+		if dp.AsDouble != values[m.Name] {
+			t.Errorf("%s: got %v; want %v", m.Name, dp.AsDouble, values[m.Name])
+		}
+		if dp.TimeUnixNano != "1462104000000000000" {
+			t.Errorf("%s: got time %s", m.Name, dp.TimeUnixNano)
+		}
+		if dp.Attributes[2] != attr("name", "small") {
+			t.Errorf("%s: got attributes %v", m.Name, dp.Attributes)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"sync"
+)
+
+// rowsPerChunk is the number of rows each worker formats at a time when the
+// rows are formatted concurrently.  Sets with fewer rows are formatted
+// sequentially.
+const rowsPerChunk = 512
+
+// SetParallelism sets the number of goroutines the rows are formatted with
+// by Out, e.g. runtime.GOMAXPROCS(0); the default, or n < 2, formats them
+// sequentially.  The rows are written in order.  This is for large sets, of
+// thousands of benches or more, on machines with many cores; streamed rows
+// are always formatted sequentially.  If a RowFormatter is set, it must be
+// safe for concurrent use.
+func (b *Benches) SetParallelism(n int) {
+	b.parallelism = n
+}
+
+// rowChunk is a range of rows and their formatted output.
+type rowChunk struct {
+	start, end int
+	buf        bytes.Buffer
+	err        error         // the error formatting the rows, if any.
+	done       chan struct{} // closed once the rows have been formatted.
+}
+
+// formatRows formats rows 0 through n-1 with format and writes them, in
+// order, with write.  With more than 1 worker, and more than one chunk of
+// rows, the chunks are formatted concurrently; at most 2 chunks per worker
+// are held at a time, so memory is bounded regardless of n.  Otherwise each
+// row is formatted into the same buffer and written before the next one is
+// formatted.  The first format, or write, error is returned; nothing more
+// is written after it.
+func formatRows(n, workers int, format func(buf *bytes.Buffer, i int) error, write func([]byte) error) error {
+	if workers < 2 || n <= rowsPerChunk {
+		var buf bytes.Buffer
+		for i := 0; i < n; i++ {
+			buf.Reset()
+			err := format(&buf, i)
+			if err != nil {
+				return err
+			}
+			err = write(buf.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	jobs := make(chan *rowChunk)
+	order := make(chan *rowChunk, 2*workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for c := range jobs {
+				for j := c.start; j < c.end && c.err == nil; j++ {
+					c.err = format(&c.buf, j)
+				}
+				close(c.done)
+			}
+		}()
+	}
+	go func() {
+		for start := 0; start < n; start += rowsPerChunk {
+			end := start + rowsPerChunk
+			if end > n {
+				end = n
+			}
+			c := &rowChunk{start: start, end: end, done: make(chan struct{})}
+			// the chunk is queued for writing before it's formatted so the
+			// order is kept.
+			order <- c
+			jobs <- c
+		}
+		close(jobs)
+		close(order)
+	}()
+	var err error
+	for c := range order {
+		<-c.done
+		// after an error, the rest of the chunks are drained, not written,
+		// so the goroutines finish.
+		if err == nil {
+			err = c.err
+		}
+		if err == nil {
+			err = write(c.buf.Bytes())
+		}
+	}
+	wg.Wait()
+	return err
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// largeBenches returns n benches in groups of 100, enough for the rows to
+// be formatted in more than one chunk.
+func largeBenches(n int) []Bench {
+	benches := make([]Bench, n)
+	for i := range benches {
+		v := NewBench(fmt.Sprintf("bench%d", i))
+		v.Group = fmt.Sprintf("g%d", i/100)
+		v.Desc = "a description that is long enough to be wrapped"
+		if i%7 == 0 {
+			v.Note = "every seventh bench has a note that wraps too"
+		}
+		v.Ops, v.NsOp, v.BytesOp, v.AllocsOp = int64(i+1), int64(i*3), 16, 1
+		benches[i] = v
+	}
+	return benches
+}
+
+func TestParallelOut(t *testing.T) {
+	benches := largeBenches(3*rowsPerChunk + 10)
+	formats := []struct {
+		name string
+		new  func(w io.Writer, opts ...Option) Benchmarker
+	}{
+		{"txt", func(w io.Writer, opts ...Option) Benchmarker {
+			s := NewStringBench(w, opts...)
+			s.WrapWidth(16)
+			return s
+		}},
+		{"csv", func(w io.Writer, opts ...Option) Benchmarker { return NewCSVBench(w, opts...) }},
+		{"md", func(w io.Writer, opts ...Option) Benchmarker { return NewMDBench(w, opts...) }},
+		{"html", func(w io.Writer, opts ...Option) Benchmarker { return NewHTMLBench(w, opts...) }},
+	}
+	sections := [][]Option{
+		nil,
+		{WithSections()},
+		{WithSections(), WithSectionHeaders()},
+		{WithSections(), WithNameSections()},
+		{WithSections(), WithSectionHeaders(), WithNameSections()},
+	}
+	for _, f := range formats {
+		for i, opts := range sections {
+			var want, got bytes.Buffer
+			// the benches are set, not appended, so both have the same
+			// timestamp.
+			src := &Benches{Hostname: "host", Timestamp: time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC), Benchmarks: benches}
+			s := f.new(&want, opts...)
+			SetBenches(s, src)
+			if err := s.Out(); err != nil {
+				t.Fatalf("%s %d: unexpected error: %s", f.name, i, err)
+			}
+			p := f.new(&got, append(opts, WithParallelism(4))...)
+			SetBenches(p, src)
+			if err := p.Out(); err != nil {
+				t.Fatalf("%s %d: unexpected error: %s", f.name, i, err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("%s %d: the parallel output differs from the sequential output", f.name, i)
+			}
+		}
+	}
+}
+
+func TestFormatRows(t *testing.T) {
+	format := func(buf *bytes.Buffer, i int) error {
+		buf.WriteString(strconv.Itoa(i))
+		buf.WriteByte('\n')
+		return nil
+	}
+	n := 5*rowsPerChunk + 3
+	var want bytes.Buffer
+	for i := 0; i < n; i++ {
+		format(&want, i)
+	}
+	for _, workers := range []int{0, 1, 2, 8} {
+		var got bytes.Buffer
+		err := formatRows(n, workers, format, func(p []byte) error {
+			got.Write(p)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%d workers: unexpected error: %s", workers, err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("%d workers: the rows are out of order", workers)
+		}
+	}
+
+	// nothing is written after the first error.
+	var writes int
+	err := formatRows(n, 4, format, func(p []byte) error {
+		writes++
+		if writes == 2 {
+			return errors.New("write error")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Error("got no error; want the write error")
+	}
+	if writes != 2 {
+		t.Errorf("got %d writes; want 2", writes)
+	}
+
+	// nor after a format error.
+	writes = 0
+	err = formatRows(n, 4, func(buf *bytes.Buffer, i int) error {
+		if i == rowsPerChunk+1 {
+			return errors.New("format error")
+		}
+		return format(buf, i)
+	}, func(p []byte) error {
+		writes++
+		return nil
+	})
+	if err == nil || err.Error() != "format error" {
+		t.Errorf("got %v; want the format error", err)
+	}
+	if writes != 1 {
+		t.Errorf("got %d writes; want 1", writes)
+	}
+}
+
+func BenchmarkLargeTxtParallel(b *testing.B) {
+	benchmarkLarge(b, 100000, func(w io.Writer) Benchmarker { return NewStringBench(w, WithParallelism(8)) })
+}
+
+func BenchmarkLargeMD(b *testing.B) {
+	benchmarkLarge(b, 100000, func(w io.Writer) Benchmarker { return NewMDBench(w) })
+}
+
+func BenchmarkLargeMDParallel(b *testing.B) {
+	benchmarkLarge(b, 100000, func(w io.Writer) Benchmarker { return NewMDBench(w, WithParallelism(8)) })
+}
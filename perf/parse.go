@@ -0,0 +1,156 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package perf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mohae/benchutil"
+)
+
+// procsSuffix matches the GOMAXPROCS suffix of a benchmark name.
+var procsSuffix = regexp.MustCompile(`-[0-9]+$`)
+
+// procsSuffixOf returns the GOMAXPROCS suffix of the benchmark names, e.g.
+// "-8".  Go doesn't add the suffix when GOMAXPROCS is 1, so a name can end
+// in -N on its own, e.g. BenchmarkFoo/size-1024; the suffix is only
+// returned if every name ends in the same one.
+func procsSuffixOf(names []string) string {
+	var suffix string
+	for i, name := range names {
+		s := procsSuffix.FindString(name)
+		if s == "" || i > 0 && s != suffix {
+			return ""
+		}
+		suffix = s
+	}
+	return suffix
+}
+
+// ParseGoBench parses Go benchmark output, e.g. from go test -bench, into
+// Benches.  Each benchmark's name, without the Benchmark prefix and the
+// GOMAXPROCS suffix, is split on '/' into its Group, SubGroup, and Name.
+// The suffix is only removed if every benchmark has the same one, as Go
+// leaves it off when GOMAXPROCS is 1.  The name's parts are:
+// one part is the Name, two are the Group and Name, and with more than two
+// the Name is everything after the SubGroup.  The ns/op, B/op, and
+// allocs/op values are used, other units are ignored, and the iterations
+// are the Ops.  The commit and host configuration lines are used for the
+// Git and Hostname; the other configuration lines are added to the Meta.
+// Lines that are neither are ignored.
+func ParseGoBench(r io.Reader) (*benchutil.Benches, error) {
+	b := benchutil.NewBenches()
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var line int
+	var names []string
+	for s.Scan() {
+		line++
+		text := s.Text()
+		if strings.HasPrefix(text, "Benchmark") {
+			name, bench, ok, err := parseBenchLine(text)
+			if err != nil {
+				return nil, fmt.Errorf("perf: line %d: %s", line, err)
+			}
+			if ok {
+				names = append(names, name)
+				b.Benchmarks = append(b.Benchmarks, bench)
+			}
+			continue
+		}
+		key, value, ok := parseConfigLine(text)
+		if !ok {
+			continue
+		}
+		switch key {
+		case "commit":
+			if b.Git == nil {
+				b.Git = &benchutil.GitInfo{}
+			}
+			b.Git.Commit = value
+		case "branch":
+			if b.Git == nil {
+				b.Git = &benchutil.GitInfo{}
+			}
+			b.Git.Branch = value
+		case "host":
+			b.Hostname = value
+		default:
+			b.SetMeta(key, value)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	suffix := procsSuffixOf(names)
+	for i, name := range names {
+		setName(&b.Benchmarks[i], strings.TrimSuffix(name, suffix))
+	}
+	return b, nil
+}
+
+// parseConfigLine returns the key and value of a configuration line.
+func parseConfigLine(s string) (key, value string, ok bool) {
+	i := strings.Index(s, ":")
+	if i < 1 || !strings.HasPrefix(s[i:], ": ") {
+		return "", "", false
+	}
+	key = s[:i]
+	if strings.ContainsAny(key, " \t") || strings.ToLower(key) != key {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(s[i+2:]), true
+}
+
+// setName sets the bench's Group, SubGroup, and Name from the benchmark's
+// name, without its Benchmark prefix.
+func setName(b *benchutil.Bench, name string) {
+	parts := strings.Split(name, "/")
+	switch len(parts) {
+	case 1:
+		b.Name = parts[0]
+	case 2:
+		b.Group, b.Name = parts[0], parts[1]
+	default:
+		b.Group, b.SubGroup, b.Name = parts[0], parts[1], strings.Join(parts[2:], "/")
+	}
+}
+
+// parseBenchLine parses a benchmark result line; the benchmark's name,
+// without the Benchmark prefix, is returned with its result.  If the line
+// isn't a result, e.g. it's a benchmark's log output, false is returned.
+func parseBenchLine(s string) (string, benchutil.Bench, bool, error) {
+	f := strings.Fields(s)
+	// the name, iterations, and at least one value and unit.
+	if len(f) < 4 || len(f)%2 != 0 {
+		return "", benchutil.Bench{}, false, nil
+	}
+	n, err := strconv.ParseInt(f[1], 10, 64)
+	if err != nil {
+		return "", benchutil.Bench{}, false, nil
+	}
+	b := benchutil.Bench{Iterations: 1, Result: benchutil.Result{Ops: n}}
+	for i := 2; i < len(f); i += 2 {
+		v, err := strconv.ParseFloat(f[i], 64)
+		if err != nil {
+			return "", benchutil.Bench{}, false, fmt.Errorf("%s: %s value: %s", f[0], f[i+1], err)
+		}
+		switch f[i+1] {
+		case "ns/op":
+			b.NsOp = int64(math.Round(v))
+		case "B/op":
+			b.BytesOp = int64(math.Round(v))
+		case "allocs/op":
+			b.AllocsOp = int64(math.Round(v))
+		}
+	}
+	return strings.TrimPrefix(f[0], "Benchmark"), b, true, nil
+}
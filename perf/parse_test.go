@@ -0,0 +1,86 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package perf
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mohae/benchutil"
+)
+
+func TestParseGoBench(t *testing.T) {
+	in := `goos: linux
+goarch: amd64
+pkg: github.com/mohae/benchutil
+commit: 3f7a2c1
+host: test
+BenchmarkDecode-8            	 1000000	      1052 ns/op	     240 B/op	       3 allocs/op
+BenchmarkJSON/decode-8       	  500000	      2000.6 ns/op	  12.50 MB/s
+BenchmarkJSON/decode/small/a-8 	  200000	      5000 ns/op
+BenchmarkLog prints this
+PASS
+ok  	github.com/mohae/benchutil	3.2s
+`
+	b, err := ParseGoBench(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Git == nil || b.Git.Commit != "3f7a2c1" || b.Hostname != "test" {
+		t.Errorf("got git %v, host %q", b.Git, b.Hostname)
+	}
+	if len(b.Meta) != 3 || b.Meta[2] != [2]string{"pkg", "github.com/mohae/benchutil"} {
+		t.Errorf("got meta %v", b.Meta)
+	}
+	want := []benchutil.Bench{
+		{Name: "Decode", Iterations: 1, Result: benchutil.Result{Ops: 1000000, NsOp: 1052, BytesOp: 240, AllocsOp: 3}},
+		{Group: "JSON", Name: "decode", Iterations: 1, Result: benchutil.Result{Ops: 500000, NsOp: 2001}},
+		{Group: "JSON", SubGroup: "decode", Name: "small/a", Iterations: 1, Result: benchutil.Result{Ops: 200000, NsOp: 5000}},
+	}
+	if len(b.Benchmarks) != len(want) {
+		t.Fatalf("got %d benchmarks; want %d", len(b.Benchmarks), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(b.Benchmarks[i], want[i]) {
+			t.Errorf("%d: got %+v; want %+v", i, b.Benchmarks[i], want[i])
+		}
+	}
+	// without GOMAXPROCS suffixes, a name's own -N isn't removed.
+	b, err = ParseGoBench(strings.NewReader("BenchmarkFoo/size-1024 10 5 ns/op\nBenchmarkFoo/size-2048 10 9 ns/op\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Benchmarks[0].Name != "size-1024" || b.Benchmarks[1].Name != "size-2048" {
+		t.Errorf("got %q and %q; want size-1024 and size-2048", b.Benchmarks[0].Name, b.Benchmarks[1].Name)
+	}
+	b, err = ParseGoBench(strings.NewReader("BenchmarkFoo/size-1024-4 10 5 ns/op\nBenchmarkBar-4 10 9 ns/op\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Benchmarks[0].Name != "size-1024" || b.Benchmarks[1].Name != "Bar" {
+		t.Errorf("got %q and %q; want size-1024 and Bar", b.Benchmarks[0].Name, b.Benchmarks[1].Name)
+	}
+	_, err = ParseGoBench(strings.NewReader("BenchmarkX 10 abc ns/op\n"))
+	if err == nil {
+		t.Error("got no error for a bad value")
+	}
+}
+
+func TestGoBenchRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteGoBench(&buf, testBenches())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseGoBench(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Benchmarks) != 1 || b.Benchmarks[0].ID() != "Json/decode/small_one" || b.Benchmarks[0].NsOp != 101 {
+		t.Errorf("got %+v", b.Benchmarks)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+// Package perf writes benchmark results in the Go benchmark format and
+// uploads them to a Go perf data server, e.g. perfdata.golang.org, so they
+// can be used with the golang.org/x/perf tools, e.g. benchstat.
+package perf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mohae/benchutil"
+)
+
+// DefaultURL is the URL of the Go perf data server.
+const DefaultURL = "https://perfdata.golang.org"
+
+// WriteGoBench writes the benches to w in the Go benchmark format, as it's
+// output by go test -bench.  The run's system info, if it was gathered, its
+// git info, and its Meta are written as configuration lines.  Failed benches
+// aren't written.
+func WriteGoBench(w io.Writer, b *benchutil.Benches) error {
+	bw := bufio.NewWriter(w)
+	for _, kv := range config(b) {
+		fmt.Fprintf(bw, "%s: %s\n", kv[0], kv[1])
+	}
+	var procs int
+	if b.SysInfo != nil {
+		procs = b.SysInfo.GOMAXPROCS
+	}
+	for _, v := range b.Benchmarks {
+		if v.Failed() {
+			continue
+		}
+		it := int64(v.Iterations)
+		if it < 1 {
+			it = 1
+		}
+		name := benchName(v)
+		if procs > 1 {
+			name = fmt.Sprintf("%s-%d", name, procs)
+		}
+		fmt.Fprintf(bw, "%s\t%d\t%s ns/op\t%s B/op\t%s allocs/op\n", name, v.Ops*it,
+			perOp(v.NsOp, it), perOp(v.BytesOp, it), perOp(v.AllocsOp, it))
+	}
+	return bw.Flush()
+}
+
+// config returns the configuration lines for the benches.
+func config(b *benchutil.Benches) [][2]string {
+	var kv [][2]string
+	if s := b.SysInfo; s != nil {
+		for _, c := range [][2]string{{"goos", s.GOOS}, {"goarch", s.GOARCH}, {"cpu", s.CPUModel}, {"go", s.GoVersion}} {
+			if c[1] != "" {
+				kv = append(kv, c)
+			}
+		}
+	}
+	if b.Hostname != "" {
+		kv = append(kv, [2]string{"host", b.Hostname})
+	}
+	if b.Git != nil {
+		kv = append(kv, [2]string{"commit", b.Git.Commit})
+		if b.Git.Branch != "" {
+			kv = append(kv, [2]string{"branch", b.Git.Branch})
+		}
+	}
+	for _, m := range b.Meta {
+		kv = append(kv, [2]string{configKey(m[0]), m[1]})
+	}
+	return kv
+}
+
+// configKey returns s as a configuration key: keys start with a lower case
+// letter and can't have upper case letters or spaces.
+func configKey(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return '-'
+		}
+		return unicode.ToLower(r)
+	}, s)
+	if r, _ := utf8.DecodeRuneInString(s); !unicode.IsLower(r) {
+		s = "x" + s
+	}
+	return s
+}
+
+// benchName returns the bench's benchmark name, e.g. BenchmarkJson/decode,
+// for its ID.  Spaces aren't allowed in the name and the first letter after
+// Benchmark can't be lower case.
+func benchName(b benchutil.Bench) string {
+	id := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return '_'
+		}
+		return r
+	}, b.ID())
+	r, n := utf8.DecodeRuneInString(id)
+	return "Benchmark" + string(unicode.ToUpper(r)) + id[n:]
+}
+
+// perOp returns the total as a per op value.
+func perOp(v, it int64) string {
+	if v%it == 0 {
+		return fmt.Sprintf("%d", v/it)
+	}
+	return fmt.Sprintf("%.2f", float64(v)/float64(it))
+}
+
+// Client uploads benchmark results to a perf data server.  Authentication,
+// if the server requires it, is up to the HTTP client, e.g. one from
+// golang.org/x/oauth2.
+type Client struct {
+	URL    string       // the server's URL; default is DefaultURL.
+	Client *http.Client // the client used; default is http.DefaultClient.
+}
+
+// UploadStatus is the server's response to an upload.
+type UploadStatus struct {
+	UploadID string   `json:"uploadid"` // the ID of the upload.
+	FileIDs  []string `json:"fileids"`  // the IDs of the uploaded files, in order.
+	ViewURL  string   `json:"viewurl"`  // a URL to view the results; optional.
+}
+
+// Upload uploads each of the benches as a file in a single upload.
+func (c *Client) Upload(benches ...*benchutil.Benches) (*UploadStatus, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for i, b := range benches {
+		fw, err := mw.CreateFormFile("file", fmt.Sprintf("benchutil-%d.txt", i))
+		if err != nil {
+			return nil, err
+		}
+		err = WriteGoBench(fw, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := mw.Close()
+	if err != nil {
+		return nil, err
+	}
+	url := c.URL
+	if url == "" {
+		url = DefaultURL
+	}
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Post(strings.TrimSuffix(url, "/")+"/upload", mw.FormDataContentType(), &body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("perf: upload: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var status UploadStatus
+	err = json.NewDecoder(resp.Body).Decode(&status)
+	if err != nil {
+		return nil, fmt.Errorf("perf: upload: %s", err)
+	}
+	return &status, nil
+}
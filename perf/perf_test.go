@@ -0,0 +1,89 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package perf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mohae/benchutil"
+)
+
+func testBenches() *benchutil.Benches {
+	b := &benchutil.Benches{Hostname: "test", Git: &benchutil.GitInfo{Commit: "3f7a2c1"},
+		SysInfo: &benchutil.SysInfo{GOOS: "linux", GOARCH: "amd64", CPUModel: "Xeon", GOMAXPROCS: 8}}
+	b.SetMeta("Build Number", "42")
+	b.Append(
+		benchutil.Bench{Group: "json", SubGroup: "decode", Name: "small one", Iterations: 4, Result: benchutil.Result{Ops: 10, NsOp: 402, BytesOp: 80, AllocsOp: 8}},
+		benchutil.Bench{Name: "failed", Err: "boom"},
+	)
+	return b
+}
+
+const want = `goos: linux
+goarch: amd64
+cpu: Xeon
+host: test
+commit: 3f7a2c1
+build-number: 42
+BenchmarkJson/decode/small_one-8	40	100.50 ns/op	20 B/op	2 allocs/op
+`
+
+func TestWriteGoBench(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteGoBench(&buf, testBenches())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestUpload(t *testing.T) {
+	var files []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/upload" {
+			http.NotFound(w, r)
+			return
+		}
+		mr, err := r.MultipartReader()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for {
+			p, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			b, _ := ioutil.ReadAll(p)
+			if p.FormName() == "file" {
+				files = append(files, string(b))
+			}
+		}
+		w.Write([]byte(`{"uploadid":"u1","fileids":["u1/0","u1/1"],"viewurl":"https://example.com/u1"}`))
+	}))
+	defer srv.Close()
+	c := &Client{URL: srv.URL}
+	status, err := c.Upload(testBenches(), testBenches())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.UploadID != "u1" || len(status.FileIDs) != 2 || status.ViewURL != "https://example.com/u1" {
+		t.Errorf("got %+v", status)
+	}
+	if len(files) != 2 || files[0] != want {
+		t.Errorf("got %d files: %q", len(files), files)
+	}
+	c.URL = srv.URL + "/nope"
+	_, err = c.Upload(testBenches())
+	if err == nil {
+		t.Error("got no error for a 404")
+	}
+}
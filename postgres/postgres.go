@@ -0,0 +1,211 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+// Package postgres writes benchmark results to PostgreSQL tables.
+//
+// Each write upserts the run, identified by its Hostname and Timestamp,
+// into the runs table and each bench, identified by the run and its ID,
+// into the results table; writing the same run again updates it.  The ops
+// columns hold the per op values.
+//
+// The DB must use a PostgreSQL driver, e.g. github.com/lib/pq or
+// github.com/jackc/pgx/v4/stdlib; the driver is left to the caller.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mohae/benchutil"
+)
+
+// The default table names.
+const (
+	DefaultRunsTable    = "benchutil_runs"
+	DefaultResultsTable = "benchutil_results"
+)
+
+// Writer writes benchmark results to PostgreSQL.  Unless NoCreateTables is
+// set, the schema, if there is one, and the tables are created if they
+// don't exist.
+type Writer struct {
+	DB             *sql.DB // the database.
+	Schema         string  // the schema the tables are in; empty uses the search_path.
+	RunsTable      string  // the runs table; default is DefaultRunsTable.
+	ResultsTable   string  // the results table; default is DefaultResultsTable.
+	NoCreateTables bool    // don't create the tables, e.g. when they're managed by migrations that add foreign keys to deploy or build tables.
+}
+
+// table returns the quoted, schema qualified, name of the table; if name
+// is empty, def is used.
+func (pw *Writer) table(name, def string) string {
+	if name == "" {
+		name = def
+	}
+	if pw.Schema == "" {
+		return pgQuote(name)
+	}
+	return pgQuote(pw.Schema) + "." + pgQuote(name)
+}
+
+// pgQuote returns s as a quoted identifier.
+func pgQuote(s string) string {
+	return `"` + strings.Replace(s, `"`, `""`, -1) + `"`
+}
+
+// Write upserts the run and its results in a transaction.  If the benches'
+// Hostname or Timestamp aren't set, the host's name and the current time are
+// used.  The system info is written if the benches include it, and the
+// benches' samples are combined using their aggregation; see
+// benchutil.Benches.Options.
+func (pw *Writer) Write(b *benchutil.Benches) error {
+	return pw.WriteContext(context.Background(), b)
+}
+
+// WriteContext is Write with a context; if ctx is done before the
+// transaction is committed, nothing is written and ctx's error is returned.
+func (pw *Writer) WriteContext(ctx context.Context, b *benchutil.Benches) error {
+	ts, host := b.Timestamp, b.Hostname
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+	runs, results := pw.table(pw.RunsTable, DefaultRunsTable), pw.table(pw.ResultsTable, DefaultResultsTable)
+	if !pw.NoCreateTables {
+		if pw.Schema != "" {
+			_, err := pw.DB.ExecContext(ctx, "CREATE SCHEMA IF NOT EXISTS "+pgQuote(pw.Schema))
+			if err != nil {
+				return err
+			}
+		}
+		_, err := pw.DB.ExecContext(ctx, fmt.Sprintf(pgSchema, runs, results, runs))
+		if err != nil {
+			return err
+		}
+	}
+	o := b.Options()
+	var sysInfo, git []byte
+	if o.IncludeSystemInfo || o.IncludeDetailedSystemInfo {
+		s, err := b.Info()
+		if err != nil {
+			return err
+		}
+		sysInfo, err = json.Marshal(s)
+		if err != nil {
+			return err
+		}
+	}
+	// the meta is an object so its values can be selected, e.g. meta->>'build'.
+	m := make(map[string]string, len(b.Meta))
+	for _, kv := range b.Meta {
+		m[kv[0]] = kv[1]
+	}
+	meta, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if b.Git != nil {
+		git, err = json.Marshal(b.Git)
+		if err != nil {
+			return err
+		}
+	}
+	tx, err := pw.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var id int64
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(`INSERT INTO %s (hostname, ts, name, description, git, meta, warnings, sysinfo)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (hostname, ts) DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description,
+git = EXCLUDED.git, meta = EXCLUDED.meta, warnings = EXCLUDED.warnings, sysinfo = EXCLUDED.sysinfo
+RETURNING id`, runs),
+		host, ts.UTC().Format(time.RFC3339Nano), b.Name, b.Desc, nullJSON(git), string(meta), strings.Join(b.Warnings, "\n"), nullJSON(sysInfo),
+	).Scan(&id)
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf(`INSERT INTO %s (run_id, bench_id, grp, subgroup, name, description, note, err, iterations, ops, ns_op, bytes_op, allocs_op)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+ON CONFLICT (run_id, bench_id) DO UPDATE SET grp = EXCLUDED.grp, subgroup = EXCLUDED.subgroup, name = EXCLUDED.name,
+description = EXCLUDED.description, note = EXCLUDED.note, err = EXCLUDED.err, iterations = EXCLUDED.iterations,
+ops = EXCLUDED.ops, ns_op = EXCLUDED.ns_op, bytes_op = EXCLUDED.bytes_op, allocs_op = EXCLUDED.allocs_op`, results))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	agg := aggregation(o)
+	for _, v := range b.Benchmarks {
+		if agg != benchutil.AggregateMean {
+			v = v.Aggregate(agg)
+		}
+		it := v.Iterations
+		if it < 1 {
+			it = 1
+		}
+		_, err = stmt.ExecContext(ctx, id, v.ID(), v.Group, v.SubGroup, v.Name, v.Desc, v.Note, v.Err, it,
+			v.Ops*int64(it), float64(v.NsOp)/float64(it), float64(v.BytesOp)/float64(it), float64(v.AllocsOp)/float64(it))
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// aggregation returns the Aggregation named in the options.
+func aggregation(o benchutil.Options) benchutil.Aggregation {
+	for _, a := range []benchutil.Aggregation{benchutil.AggregateMedian, benchutil.AggregateMin} {
+		if o.Aggregation == a.String() {
+			return a
+		}
+	}
+	return benchutil.AggregateMean
+}
+
+// nullJSON returns a JSON value that's NULL if it's empty.
+func nullJSON(v []byte) interface{} {
+	if len(v) == 0 {
+		return nil
+	}
+	return string(v)
+}
+
+// pgSchema creates the runs and results tables; the verbs are the runs
+// table, the results table, and the runs table again for the foreign key.
+const pgSchema = `CREATE TABLE IF NOT EXISTS %s (
+	id BIGSERIAL PRIMARY KEY,
+	hostname TEXT NOT NULL,
+	ts TIMESTAMPTZ NOT NULL,
+	name TEXT NOT NULL,
+	description TEXT NOT NULL,
+	git JSONB,
+	meta JSONB NOT NULL,
+	warnings TEXT NOT NULL,
+	sysinfo JSONB,
+	UNIQUE (hostname, ts)
+);
+CREATE TABLE IF NOT EXISTS %s (
+	run_id BIGINT NOT NULL REFERENCES %s (id) ON DELETE CASCADE,
+	bench_id TEXT NOT NULL,
+	grp TEXT NOT NULL,
+	subgroup TEXT NOT NULL,
+	name TEXT NOT NULL,
+	description TEXT NOT NULL,
+	note TEXT NOT NULL,
+	err TEXT NOT NULL,
+	iterations INTEGER NOT NULL,
+	ops BIGINT NOT NULL,
+	ns_op DOUBLE PRECISION NOT NULL,
+	bytes_op DOUBLE PRECISION NOT NULL,
+	allocs_op DOUBLE PRECISION NOT NULL,
+	PRIMARY KEY (run_id, bench_id)
+)`
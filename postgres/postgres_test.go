@@ -0,0 +1,169 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mohae/benchutil"
+)
+
+// recDriver is a database/sql driver that records the statements executed
+// and returns 1 for every query.
+type recDriver struct {
+	stmts []recStmt
+}
+
+type recStmt struct {
+	query string
+	args  []driver.Value
+}
+
+func (d *recDriver) Open(name string) (driver.Conn, error) { return &recConn{d}, nil }
+
+type recConn struct{ d *recDriver }
+
+func (c *recConn) Prepare(query string) (driver.Stmt, error) { return &recPrepared{c.d, query}, nil }
+func (c *recConn) Close() error                              { return nil }
+func (c *recConn) Begin() (driver.Tx, error)                 { return recTx{c.d}, nil }
+
+type recTx struct{ d *recDriver }
+
+func (t recTx) Commit() error {
+	t.d.stmts = append(t.d.stmts, recStmt{query: "COMMIT"})
+	return nil
+}
+
+func (t recTx) Rollback() error { return nil }
+
+type recPrepared struct {
+	d     *recDriver
+	query string
+}
+
+func (s *recPrepared) Close() error  { return nil }
+func (s *recPrepared) NumInput() int { return -1 }
+
+func (s *recPrepared) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.stmts = append(s.d.stmts, recStmt{s.query, args})
+	return driver.RowsAffected(1), nil
+}
+
+func (s *recPrepared) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.stmts = append(s.d.stmts, recStmt{s.query, args})
+	return &recRows{}, nil
+}
+
+type recRows struct{ done bool }
+
+func (r *recRows) Columns() []string { return []string{"id"} }
+func (r *recRows) Close() error      { return nil }
+
+func (r *recRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+func TestWriter(t *testing.T) {
+	d := &recDriver{}
+	sql.Register("benchutil-rec", d)
+	db, err := sql.Open("benchutil-rec", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &Writer{DB: db, Schema: "perf", RunsTable: `my"runs`}
+	b := benchutil.NewBenches()
+	b.Hostname = "test"
+	b.Timestamp = time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	b.SetMeta("build", "42")
+	b.Append(
+		benchutil.Bench{Group: "json", SubGroup: "decode", Name: "small", Iterations: 4, Result: benchutil.Result{Ops: 10, NsOp: 400, BytesOp: 80, AllocsOp: 8}},
+		benchutil.Bench{Name: "failed", Iterations: 1, Err: "boom"},
+	)
+	err = w.Write(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.stmts) != 6 {
+		t.Fatalf("got %d statements; want 6", len(d.stmts))
+	}
+	if d.stmts[0].query != `CREATE SCHEMA IF NOT EXISTS "perf"` {
+		t.Errorf("got %q; want the schema created", d.stmts[0].query)
+	}
+	if !strings.HasPrefix(d.stmts[1].query, `CREATE TABLE IF NOT EXISTS "perf"."my""runs"`) || !strings.Contains(d.stmts[1].query, `"perf"."benchutil_results"`) {
+		t.Errorf("got %q; want the tables created", d.stmts[1].query)
+	}
+	run := d.stmts[2]
+	if !strings.Contains(run.query, "ON CONFLICT (hostname, ts)") {
+		t.Errorf("got %q; want the run upserted", run.query)
+	}
+	if run.args[0] != "test" || run.args[1] != "2016-05-01T12:00:00Z" || run.args[4] != nil || run.args[5] != `{"build":"42"}` {
+		t.Errorf("got run args %v", run.args)
+	}
+	want := []driver.Value{int64(1), "json/decode/small", "json", "decode", "small", "", "", "", int64(4), int64(40), 100.0, 20.0, 2.0}
+	res := d.stmts[3]
+	if !strings.Contains(res.query, "ON CONFLICT (run_id, bench_id)") || len(res.args) != len(want) {
+		t.Fatalf("got %q %v; want the result upserted", res.query, res.args)
+	}
+	for i := range want {
+		if res.args[i] != want[i] {
+			t.Errorf("arg %d: got %v (%T); want %v (%T)", i, res.args[i], res.args[i], want[i], want[i])
+		}
+	}
+	if d.stmts[4].args[7] != "boom" {
+		t.Errorf("got %v; want the error recorded", d.stmts[4].args)
+	}
+	if d.stmts[5].query != "COMMIT" {
+		t.Errorf("got %q; want COMMIT", d.stmts[5].query)
+	}
+
+	// without creating the tables only the upserts are executed.
+	d.stmts = nil
+	w.NoCreateTables = true
+	err = w.Write(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.stmts) != 4 {
+		t.Errorf("got %d statements; want 4", len(d.stmts))
+	}
+
+	// nothing is written with a done context.
+	d.stmts = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = w.WriteContext(ctx, b)
+	if err != context.Canceled {
+		t.Errorf("got %v; want %v", err, context.Canceled)
+	}
+	if len(d.stmts) != 0 {
+		t.Errorf("got %d statements; want 0", len(d.stmts))
+	}
+
+	// the samples are combined using the benches' aggregation.
+	d.stmts = nil
+	b = benchutil.NewBenches(benchutil.WithAggregation(benchutil.AggregateMin))
+	v := benchutil.NewBench("sampled")
+	v.AddSample(benchutil.Result{Ops: 10, NsOp: 300})
+	v.AddSample(benchutil.Result{Ops: 10, NsOp: 100})
+	b.Append(v)
+	err = w.Write(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.stmts) != 3 || d.stmts[1].args[10] != 100.0 {
+		t.Errorf("got %v; want the minimum ns/op", d.stmts)
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+// SetPrecision sets the number of decimal places the column's per op
+// values are output with, e.g. 2 for "12.35" ns/op; the values are
+// rounded.  It applies to the NsOp, BytesOp, and AllocsOp columns; other
+// columns are ignored.  A negative number of digits restores the default:
+// the values are truncated to integers.
+func (b *Benches) SetPrecision(c Column, digits int) {
+	switch c {
+	case NsOpColumn, BytesOpColumn, AllocsOpColumn:
+	default:
+		return
+	}
+	if digits < 0 {
+		delete(b.precision, c)
+		return
+	}
+	if b.precision == nil {
+		b.precision = make(map[Column]int)
+	}
+	b.precision[c] = digits
+}
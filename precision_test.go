@@ -0,0 +1,47 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSetPrecision(t *testing.T) {
+	benches := []Bench{
+		{Name: "a", Iterations: 3, Result: Result{Ops: 10, NsOp: 100, BytesOp: 16, AllocsOp: 1}},
+		{Name: "b", Iterations: 1, Result: Result{Ops: 10, NsOp: 0, BytesOp: 0, AllocsOp: 0}},
+	}
+	var buf bytes.Buffer
+	c := NewCSVBench(&buf)
+	c.SetPrecision(NsOpColumn, 2)
+	c.SetPrecision(AllocsOpColumn, 0)
+	c.SetPrecision(OpsColumn, 2)
+	SetBenches(c, &Benches{Benchmarks: benches})
+	if err := c.Out(); err != nil {
+		t.Fatal(err)
+	}
+	want := "Name,Operations,Ns/Op,Bytes/Op,Allocs/Op\na,30,33.33,5,0\nb,10,0.00,0,0\n"
+	if buf.String() != want {
+		t.Errorf("got %q; want %q", buf.String(), want)
+	}
+
+	// the text columns are widened to fit.
+	var txt bytes.Buffer
+	s := NewStringBench(&txt)
+	s.SetPrecision(NsOpColumn, 3)
+	s.SetLocale("de")
+	SetBenches(s, &Benches{Benchmarks: benches})
+	s.Out()
+	if !strings.Contains(txt.String(), "a      30  33,333") {
+		t.Errorf("got %q; want the ns/op with 3 decimal places", txt.String())
+	}
+
+	c.SetPrecision(NsOpColumn, -1)
+	if _, ok := c.precision[NsOpColumn]; ok {
+		t.Error("got the precision set; want it restored to the default")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"fmt"
+	"sort"
+)
+
+// profiles are the preset output profiles, by name.
+var profiles = map[string][]Option{
+	// github-pr is for md reports posted as a pull request comment: a named
+	// section per group, the basic system info, and ns/op as durations.
+	// Descriptions are left out to keep the tables narrow.
+	"github-pr": {
+		WithSections(),
+		WithNameSections(),
+		WithSystemInfo(),
+		WithNsOpAsDuration(),
+		WithHiddenColumns("desc"),
+	},
+	// terminal is for txt reports read in a terminal: a named section, with
+	// its own column headers, per group, and ns/op as durations.
+	"terminal": {
+		WithSections(),
+		WithNameSections(),
+		WithSectionHeaders(),
+		WithNsOpAsDuration(),
+	},
+	// archive is for reports that are kept, e.g. to compare with later runs:
+	// all of the system info, the desc and note columns even if they're
+	// empty so the columns are the same across runs, and ns/op with 2
+	// decimal places so nothing is lost to truncation.
+	"archive": {
+		WithDetailedSystemInfo(),
+		WithGPUInfo(),
+		WithDiskInfo(),
+		func(b *Benches) {
+			b.ForceColumn("desc", true)
+			b.ForceColumn("note", true)
+			b.SetPrecision(NsOpColumn, 2)
+		},
+	},
+}
+
+// Profile returns the named preset output profile, as an Option, e.g.
+//
+//	NewMDBench(w, Profile("github-pr"))
+//
+// The profiles are github-pr, terminal, and archive.  Options after the
+// profile override its settings.  If the profile isn't known, it's ignored
+// and Out returns the error, like WithLocale; use LookupProfile, e.g. for
+// names from a flag, to report it earlier.
+func Profile(name string) Option {
+	opt, err := LookupProfile(name)
+	if err != nil {
+		return func(b *Benches) { b.optErr = err }
+	}
+	return opt
+}
+
+// LookupProfile returns the named preset output profile, as an Option; see
+// Profile.
+func LookupProfile(name string) (Option, error) {
+	opts, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("benchutil: unknown profile %q", name)
+	}
+	return func(b *Benches) { b.apply(opts) }, nil
+}
+
+// Profiles returns the names of the preset output profiles, sorted.
+func Profiles() []string {
+	names := make([]string, 0, len(profiles))
+	for k := range profiles {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProfile(t *testing.T) {
+	b := NewBenches(Profile("github-pr"))
+	if !b.sectionPerGroup || !b.nameSections || !b.includeSystemInfo || !b.nsOpDuration || !b.hidden["desc"] {
+		t.Errorf("got %+v; want the github-pr settings", b)
+	}
+	b = NewBenches(Profile("archive"), WithHiddenColumns("note"))
+	if !b.includeDetailedSystemInfo || !b.includeGPUInfo || !b.includeDiskInfo || !b.shown["desc"] || b.precision[NsOpColumn] != 2 {
+		t.Errorf("got %+v; want the archive settings", b)
+	}
+	// later options override the profile.
+	if b.shown["note"] || !b.hidden["note"] {
+		t.Error("got note shown; want it hidden")
+	}
+
+	var buf bytes.Buffer
+	s := NewStringBench(&buf, Profile("terminal"))
+	s.Append(testBenches()...)
+	if err := s.Out(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(buf.String(), "100ns") {
+		t.Errorf("got %q; want durations", buf.String())
+	}
+}
+
+func TestLookupProfile(t *testing.T) {
+	for _, name := range Profiles() {
+		if _, err := LookupProfile(name); err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+		}
+	}
+	if _, err := LookupProfile("unknown"); err == nil {
+		t.Error("got no error for an unknown profile")
+	}
+	want := []string{"archive", "github-pr", "terminal"}
+	if got := Profiles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	err := NewStringBench(ioutil.Discard, Profile("unknown")).Out()
+	if err == nil || !strings.Contains(err.Error(), `unknown profile "unknown"`) {
+		t.Errorf("got %v; want an unknown profile error", err)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Progress reports the progress of a benchmark session: the number of
+// completed benchmarks out of the total, the elapsed time, and the estimated
+// time remaining.  If the writer is a terminal, the progress line is updated
+// in place; otherwise each update is written on its own line.
+type Progress struct {
+	w     io.Writer
+	tty   bool
+	total int
+	done  int
+	start time.Time
+}
+
+// NewProgress returns a Progress that writes to os.Stderr for a session of
+// total benchmarks.
+func NewProgress(total int) *Progress {
+	return NewProgressWriter(os.Stderr, total)
+}
+
+// NewProgressWriter returns a Progress that writes to w for a session of
+// total benchmarks.
+func NewProgressWriter(w io.Writer, total int) *Progress {
+	return &Progress{w: w, tty: isTerminal(w), total: total}
+}
+
+// Start sets the start time of the session and writes the initial progress
+// line.
+func (p *Progress) Start() {
+	p.start = time.Now()
+	p.write()
+}
+
+// Done marks a benchmark as completed and updates the progress line.  When
+// the last benchmark is done, the line is terminated.
+func (p *Progress) Done() {
+	if p.start.IsZero() {
+		p.start = time.Now()
+	}
+	p.done++
+	p.write()
+	if p.tty && p.done >= p.total {
+		fmt.Fprint(p.w, "\n")
+	}
+}
+
+// Completed returns the number of benchmarks that have completed.
+func (p *Progress) Completed() int {
+	return p.done
+}
+
+// Total returns the total number of benchmarks in the session.
+func (p *Progress) Total() int {
+	return p.total
+}
+
+// Elapsed returns the time elapsed since the session started.
+func (p *Progress) Elapsed() time.Duration {
+	if p.start.IsZero() {
+		return 0
+	}
+	return time.Since(p.start)
+}
+
+// Remaining returns the estimated time remaining, based on the average time
+// each completed benchmark took.  If nothing has completed, the estimate is
+// 0.
+func (p *Progress) Remaining() time.Duration {
+	if p.done == 0 || p.done >= p.total {
+		return 0
+	}
+	per := p.Elapsed() / time.Duration(p.done)
+	return per * time.Duration(p.total-p.done)
+}
+
+// String returns the current progress as a string, e.g.
+// "3/42  elapsed: 12s  remaining: 2m36s".
+func (p *Progress) String() string {
+	return fmt.Sprintf("%d/%d  elapsed: %s  remaining: %s", p.done, p.total, p.Elapsed().Round(time.Second), p.Remaining().Round(time.Second))
+}
+
+func (p *Progress) write() {
+	if p.tty {
+		// clear the line and rewrite it in place
+		fmt.Fprintf(p.w, "\r\x1b[K%s", p.String())
+		return
+	}
+	fmt.Fprintln(p.w, p.String())
+}
+
+// isTerminal returns whether or not w is a character device.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Dotter writes a dot to its writer on every tick until it is told to stop.
+// It provides a simple indication that a long running benchmark is still
+// running.
+type Dotter struct {
+	W        io.Writer     // where the dots are written; default is os.Stderr.
+	Interval time.Duration // time between dots; default is 1 second.
+	Width    int           // the number of dots per line; 0 means no line breaks.
+	Prefix   string        // written at the start of each line of dots; optional.
+}
+
+// NewDotter returns a Dotter that writes a dot to os.Stderr every second, with
+// 60 dots per line.
+func NewDotter() *Dotter {
+	return &Dotter{
+		W:        os.Stderr,
+		Interval: time.Second,
+		Width:    60,
+	}
+}
+
+// Run writes dots until done is closed or receives a value.
+func (d *Dotter) Run(done chan struct{}) {
+	w := d.W
+	if w == nil {
+		w = os.Stderr
+	}
+	interval := d.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	var i int
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+			if d.Prefix != "" && (i == 0 || (d.Width > 0 && i%d.Width == 0)) {
+				fmt.Fprint(w, d.Prefix)
+			}
+			i++
+			fmt.Fprint(w, ".")
+			if d.Width > 0 && i%d.Width == 0 {
+				fmt.Fprint(w, "\n")
+			}
+		}
+	}
+}
+
+// Dot prints a . every second to os.Stderr, with a line break every 60 dots.
+// Use a Dotter for other destinations, intervals, or line widths.
+func Dot(done chan struct{}) {
+	NewDotter().Run(done)
+}
+
+// BenchStart implements Observer.  The first call starts the session clock.
+func (p *Progress) BenchStart(i, total int, b Bench) {
+	if p.start.IsZero() {
+		p.Start()
+	}
+}
+
+// BenchDone implements Observer; it marks the benchmark as done.
+func (p *Progress) BenchDone(i, total int, b Bench, elapsed time.Duration) {
+	p.Done()
+}
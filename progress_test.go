@@ -0,0 +1,63 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewProgressWriter(&buf, 2)
+	p.Start()
+	p.Done()
+	if p.Completed() != 1 {
+		t.Errorf("got %d completed; want 1", p.Completed())
+	}
+	p.Done()
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines; want 3: %q", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[1], "1/2") {
+		t.Errorf("got %q; want line starting with 1/2", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "2/2") {
+		t.Errorf("got %q; want line starting with 2/2", lines[2])
+	}
+	if p.Remaining() != 0 {
+		t.Errorf("got %s remaining; want 0", p.Remaining())
+	}
+}
+
+func TestDotter(t *testing.T) {
+	var buf bytes.Buffer
+	d := &Dotter{W: &buf, Interval: time.Millisecond, Width: 3, Prefix: "> "}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		d.Run(done)
+		close(stopped)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	// the buffer isn't safe for concurrent use; stop before reading it.
+	close(done)
+	<-stopped
+	s := buf.String()
+	if s == "" {
+		t.Fatal("got no output; want dots")
+	}
+	if !strings.HasPrefix(s, "> .") {
+		t.Errorf("got %q; want output starting with the prefix", s)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		if len(strings.TrimPrefix(line, "> ")) > 3 {
+			t.Errorf("got line %q; want at most 3 dots per line", line)
+		}
+	}
+}
@@ -0,0 +1,486 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"time"
+	"unicode/utf8"
+
+	pcg "github.com/dgryski/go-pcgr"
+)
+
+const alphanum = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+var alen = uint32(len(alphanum))
+
+// defaultGen is the Gen used by the package level Rand functions.
+var defaultGen = NewGen(NewSeed())
+
+// Gen generates pseudo-random data.  A Gen created with the same seed always
+// generates the same sequence, which makes benchmark inputs reproducible;
+// each Gen is an independent stream.  A Gen is not safe for concurrent use.
+type Gen struct {
+	rng  pcg.Rand
+	seed int64
+	text *Text // the lorem ipsum Text with the default word lengths; see loremText.
+}
+
+// NewGen returns a Gen seeded with seed.  Use NewSeed for a random seed.
+func NewGen(seed int64) *Gen {
+	g := &Gen{seed: seed}
+	g.rng.Seed(seed)
+	return g
+}
+
+// Seed returns the seed the Gen was created with.
+func (g *Gen) Seed() int64 {
+	return g.seed
+}
+
+// entropy is the source of the seeds and of CryptoRandBytes.
+var entropy io.Reader = crand.Reader
+
+// NewSeed gets a random int64 to use for a seed value.  If the system's
+// entropy source can't be read, the seed is derived from the current time
+// instead; use NewSeedErr to know when that happens.
+func NewSeed() int64 {
+	seed, err := NewSeedErr()
+	if err != nil {
+		return time.Now().UnixNano() & (1<<63 - 1)
+	}
+	return seed
+}
+
+// NewSeedErr gets a random int64, read from crypto/rand, to use for a seed
+// value.  An error is returned if the system's entropy source can't be
+// read.
+func NewSeedErr() (int64, error) {
+	bi := big.NewInt(1<<63 - 1)
+	r, err := crand.Int(entropy, bi)
+	if err != nil {
+		return 0, fmt.Errorf("entropy read error: %s", err)
+	}
+	return r.Int64(), nil
+}
+
+// RandString returns a randomly generated string of length l.
+func RandString(l uint32) string {
+	return defaultGen.RandString(l)
+}
+
+// RandString returns a randomly generated string of length l.
+func (g *Gen) RandString(l uint32) string {
+	return string(g.RandBytes(l))
+}
+
+// RandBytes returns a randomly generated []byte of length l.  The values of
+// these bytes are restricted to the ASCII alphanum range; that doesn't matter
+// for the purposes of these benchmarks.
+func RandBytes(l uint32) []byte {
+	return defaultGen.RandBytes(l)
+}
+
+// RandBytes returns a randomly generated []byte of length l.  The values of
+// these bytes are restricted to the ASCII alphanum range.
+func (g *Gen) RandBytes(l uint32) []byte {
+	b := make([]byte, l)
+	g.FillRandBytes(b)
+	return b
+}
+
+// FillRandBytes fills dst with randomly generated bytes in the ASCII alphanum
+// range.  Unlike RandBytes, it doesn't allocate, so it can be used in a
+// benchmark's loop without adding to the allocs/op of the code being
+// benchmarked.
+func FillRandBytes(dst []byte) {
+	defaultGen.FillRandBytes(dst)
+}
+
+// FillRandBytes fills dst with randomly generated bytes in the ASCII alphanum
+// range; a Gen with the same seed generates the same bytes as RandBytes.
+func (g *Gen) FillRandBytes(dst []byte) {
+	for i := range dst {
+		dst[i] = alphanum[int(g.rng.Bound(alen))]
+	}
+}
+
+// AppendRandString appends a randomly generated string of length l, in the
+// ASCII alphanum range, to dst and returns the extended slice.  It only
+// allocates if dst doesn't have the capacity for l more bytes, so reusing
+// dst[:0] across a benchmark's iterations doesn't allocate.
+func AppendRandString(dst []byte, l int) []byte {
+	return defaultGen.AppendRandString(dst, l)
+}
+
+// AppendRandString appends a randomly generated string of length l to dst
+// and returns the extended slice.  See the AppendRandString function.
+func (g *Gen) AppendRandString(dst []byte, l int) []byte {
+	if l <= 0 {
+		return dst
+	}
+	n := len(dst)
+	if cap(dst)-n < l {
+		b := make([]byte, n, n+l)
+		copy(b, dst)
+		dst = b
+	}
+	dst = dst[:n+l]
+	g.FillRandBytes(dst[n:])
+	return dst
+}
+
+// RandRawBytes returns a randomly generated []byte of length l.  Unlike
+// RandBytes, the values span the full 0-255 range, which makes the data
+// representative for hashing and compression.
+func RandRawBytes(l uint32) []byte {
+	return defaultGen.RandRawBytes(l)
+}
+
+// RandRawBytes returns a randomly generated []byte of length l, with values
+// in the full 0-255 range.
+func (g *Gen) RandRawBytes(l uint32) []byte {
+	b := make([]byte, l)
+	g.fill(b)
+	return b
+}
+
+// fill fills b with pseudo-random bytes.
+func (g *Gen) fill(b []byte) {
+	for i := 0; i < len(b); i += 4 {
+		v := g.rng.Next()
+		for j := 0; j < 4 && i+j < len(b); j++ {
+			b[i+j] = byte(v >> (8 * uint(j)))
+		}
+	}
+}
+
+// CryptoRandBytes returns a []byte of length l read from crypto/rand.  It is
+// much slower than RandRawBytes and its output isn't reproducible; use it
+// when the data must be truly unpredictable.  An error is returned if the
+// system's entropy source can't be read.
+func CryptoRandBytes(l uint32) ([]byte, error) {
+	b := make([]byte, l)
+	_, err := io.ReadFull(entropy, b)
+	if err != nil {
+		return nil, fmt.Errorf("entropy read error: %s", err)
+	}
+	return b, nil
+}
+
+// RandBool returns a pseudo-random bool value.
+func RandBool() bool {
+	return defaultGen.RandBool()
+}
+
+// RandBool returns a pseudo-random bool value.
+func (g *Gen) RandBool() bool {
+	if g.rng.Int63()%2 == 0 {
+		return false
+	}
+	return true
+}
+
+// Character sets for use with RandStringFrom.
+const (
+	CharsetAlphanum  = alphanum
+	CharsetDigits    = "0123456789"
+	CharsetLower     = "abcdefghijklmnopqrstuvwxyz"
+	CharsetHex       = "0123456789abcdef"
+	CharsetBase64    = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	CharsetBase64URL = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+)
+
+// RandStringFrom returns a randomly generated string of l characters chosen
+// from charset, e.g. CharsetHex.  The charset may contain multi-byte
+// characters, in which case the returned string will be longer than l bytes.
+// If charset is empty, an empty string is returned.
+func RandStringFrom(charset string, l uint32) string {
+	return defaultGen.RandStringFrom(charset, l)
+}
+
+// RandStringFrom returns a randomly generated string of l characters chosen
+// from charset.  See the RandStringFrom function.
+func (g *Gen) RandStringFrom(charset string, l uint32) string {
+	if len(charset) == 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(charset) == len(charset) {
+		n := uint32(len(charset))
+		b := make([]byte, l)
+		for i := range b {
+			b[i] = charset[g.rng.Bound(n)]
+		}
+		return string(b)
+	}
+	runes := []rune(charset)
+	n := uint32(len(runes))
+	r := make([]rune, l)
+	for i := range r {
+		r[i] = runes[g.rng.Bound(n)]
+	}
+	return string(r)
+}
+
+// RuneRange is an inclusive range of runes.
+type RuneRange struct {
+	Lo, Hi rune
+}
+
+// Rune ranges for use with RandUTF8.
+var (
+	// RunesASCII is printable ASCII.
+	RunesASCII = []RuneRange{{0x20, 0x7e}}
+	// RunesLatin is printable ASCII, Latin-1, and Latin Extended-A; 1 and 2
+	// byte encodings.
+	RunesLatin = []RuneRange{{0x20, 0x7e}, {0xa0, 0x17f}}
+	// RunesCyrillic is Cyrillic; 2 byte encodings.
+	RunesCyrillic = []RuneRange{{0x400, 0x4ff}}
+	// RunesCJK is Hiragana, Katakana, and the CJK Unified Ideographs; 3 byte
+	// encodings.
+	RunesCJK = []RuneRange{{0x3040, 0x30ff}, {0x4e00, 0x9fff}}
+	// RunesEmoji is emoji; 4 byte encodings.
+	RunesEmoji = []RuneRange{{0x1f300, 0x1f64f}, {0x1f680, 0x1f6ff}, {0x1f900, 0x1f9ff}}
+	// RunesMixed is a mix of 1, 2, 3, and 4 byte encodings.
+	RunesMixed = []RuneRange{{0x20, 0x7e}, {0xa0, 0x17f}, {0x400, 0x4ff}, {0x4e00, 0x9fff}, {0x1f300, 0x1f64f}}
+)
+
+// RandUTF8 returns a randomly generated, valid, UTF-8 string of l runes.
+// Each rune is generated by picking one of the ranges, with each range
+// equally likely regardless of its size, and then a rune within it; this
+// keeps small ranges, e.g. ASCII, from being drowned out by large ones, e.g.
+// CJK.  Surrogates and invalid runes are never generated; the parts of a
+// range that are surrogates, negative, or above utf8.MaxRune are left out,
+// and ranges with no valid runes aren't picked.  If ranges is empty,
+// RunesMixed is used; if none of the ranges have a valid rune, an empty
+// string is returned.
+func RandUTF8(l uint32, ranges []RuneRange) string {
+	return defaultGen.RandUTF8(l, ranges)
+}
+
+// RandUTF8 returns a randomly generated, valid, UTF-8 string of l runes from
+// the ranges.  See the RandUTF8 function.
+func (g *Gen) RandUTF8(l uint32, ranges []RuneRange) string {
+	if len(ranges) == 0 {
+		ranges = RunesMixed
+	}
+	valid := make([]RuneRange, 0, len(ranges))
+	for _, rr := range ranges {
+		rr, ok := validRunes(rr)
+		if ok {
+			valid = append(valid, rr)
+		}
+	}
+	if len(valid) == 0 {
+		return ""
+	}
+	n := uint32(len(valid))
+	r := make([]rune, l)
+	for i := range r {
+		rr := valid[g.rng.Bound(n)]
+		size := uint32(rr.Hi-rr.Lo) + 1
+		// the surrogates in the range are skipped.
+		spans := rr.Lo < surrogateLo && rr.Hi > surrogateHi
+		if spans {
+			size -= surrogateHi - surrogateLo + 1
+		}
+		v := rr.Lo + rune(g.rng.Bound(size))
+		if spans && v >= surrogateLo {
+			v += surrogateHi - surrogateLo + 1
+		}
+		r[i] = v
+	}
+	return string(r)
+}
+
+// The surrogate runes, which aren't valid in UTF-8.
+const (
+	surrogateLo = 0xd800
+	surrogateHi = 0xdfff
+)
+
+// validRunes returns rr with its ends ordered and clamped to the valid
+// runes, or false if it has none.  A range that starts, or ends, among the
+// surrogates starts after, or ends before, them.
+func validRunes(rr RuneRange) (RuneRange, bool) {
+	if rr.Hi < rr.Lo {
+		rr.Lo, rr.Hi = rr.Hi, rr.Lo
+	}
+	if rr.Lo < 0 {
+		rr.Lo = 0
+	}
+	if rr.Hi > utf8.MaxRune {
+		rr.Hi = utf8.MaxRune
+	}
+	if rr.Lo >= surrogateLo && rr.Lo <= surrogateHi {
+		rr.Lo = surrogateHi + 1
+	}
+	if rr.Hi >= surrogateLo && rr.Hi <= surrogateHi {
+		rr.Hi = surrogateLo - 1
+	}
+	return rr, rr.Lo <= rr.Hi
+}
+
+// uint64 returns a pseudo-random 64-bit value.
+func (g *Gen) uint64() uint64 {
+	return uint64(g.rng.Next())<<32 | uint64(g.rng.Next())
+}
+
+// uint64n returns a pseudo-random number in [0, n), without modulo bias.
+// n must be > 0.
+func (g *Gen) uint64n(n uint64) uint64 {
+	if n&(n-1) == 0 {
+		return g.uint64() & (n - 1)
+	}
+	// reject the values in the partial bucket at the top of the range.
+	min := -n % n
+	for {
+		v := g.uint64()
+		if v >= min {
+			return v % n
+		}
+	}
+}
+
+// RandIntn returns a pseudo-random int in [0, n).  It panics if n <= 0.
+func RandIntn(n int) int {
+	return defaultGen.RandIntn(n)
+}
+
+// RandIntn returns a pseudo-random int in [0, n).  It panics if n <= 0.
+func (g *Gen) RandIntn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to RandIntn")
+	}
+	return int(g.uint64n(uint64(n)))
+}
+
+// RandInt64Range returns a pseudo-random int64 in [min, max).  It panics if
+// max <= min.
+func RandInt64Range(min, max int64) int64 {
+	return defaultGen.RandInt64Range(min, max)
+}
+
+// RandInt64Range returns a pseudo-random int64 in [min, max).  It panics if
+// max <= min.
+func (g *Gen) RandInt64Range(min, max int64) int64 {
+	if max <= min {
+		panic("invalid argument to RandInt64Range")
+	}
+	// the difference can overflow int64 but not uint64.
+	return min + int64(g.uint64n(uint64(max)-uint64(min)))
+}
+
+// RandFloat64 returns a pseudo-random float64 in [0.0, 1.0).
+func RandFloat64() float64 {
+	return defaultGen.RandFloat64()
+}
+
+// RandFloat64 returns a pseudo-random float64 in [0.0, 1.0).
+func (g *Gen) RandFloat64() float64 {
+	// use the top 53 bits, the float64 mantissa, so every value is equally
+	// likely.
+	return float64(g.uint64()>>11) / (1 << 53)
+}
+
+// RandFloat64Range returns a pseudo-random float64 in [min, max).  It panics
+// if max <= min.
+func RandFloat64Range(min, max float64) float64 {
+	return defaultGen.RandFloat64Range(min, max)
+}
+
+// RandFloat64Range returns a pseudo-random float64 in [min, max).  It panics
+// if max <= min.
+func (g *Gen) RandFloat64Range(min, max float64) float64 {
+	if !(max > min) {
+		panic("invalid argument to RandFloat64Range")
+	}
+	v := min + g.RandFloat64()*(max-min)
+	// rounding can produce max.
+	if v >= max {
+		return min
+	}
+	return v
+}
+
+// RandNormal returns a pseudo-random float64 from a normal distribution with
+// the mean and standard deviation.
+func RandNormal(mean, stddev float64) float64 {
+	return defaultGen.RandNormal(mean, stddev)
+}
+
+// RandNormal returns a pseudo-random float64 from a normal distribution with
+// the mean and standard deviation.
+func (g *Gen) RandNormal(mean, stddev float64) float64 {
+	// Marsaglia's polar method; only one of the pair of values is used so
+	// the Gen doesn't have to hold any state between calls.
+	for {
+		u := 2*g.RandFloat64() - 1
+		v := 2*g.RandFloat64() - 1
+		s := u*u + v*v
+		if s > 0 && s < 1 {
+			return mean + stddev*u*math.Sqrt(-2*math.Log(s)/s)
+		}
+	}
+}
+
+// RandExp returns a pseudo-random float64 from an exponential distribution
+// with the rate, e.g. the events per unit of time; the mean is 1/rate.  It
+// panics if rate <= 0.
+func RandExp(rate float64) float64 {
+	return defaultGen.RandExp(rate)
+}
+
+// RandExp returns a pseudo-random float64 from an exponential distribution
+// with the rate.  It panics if rate <= 0.
+func (g *Gen) RandExp(rate float64) float64 {
+	if !(rate > 0) {
+		panic("invalid argument to RandExp")
+	}
+	// 1 - u is in (0, 1], so the log is never -Inf.
+	return -math.Log(1-g.RandFloat64()) / rate
+}
+
+// RandReader returns an io.Reader that produces an endless stream of
+// pseudo-random bytes, in the full 0-255 range, from a Gen seeded with seed.
+// It's useful for benchmarking streaming APIs, e.g. hashers and copy paths,
+// without materializing the data; wrap it in an io.LimitReader to bound the
+// stream.  Reads never fail.
+func RandReader(seed int64) io.Reader {
+	return NewGen(seed).Reader()
+}
+
+// Reader returns an io.Reader that produces an endless stream of
+// pseudo-random bytes from g.  The stream is the same regardless of the
+// sizes of the reads.
+func (g *Gen) Reader() io.Reader {
+	return &genReader{g: g}
+}
+
+// genReader is an io.Reader over a Gen.  The bytes of the last value that
+// weren't used by a Read are kept for the next one.
+type genReader struct {
+	g    *Gen
+	v    uint32
+	left uint
+}
+
+// Read fills p with pseudo-random bytes.
+func (r *genReader) Read(p []byte) (int, error) {
+	for i := range p {
+		if r.left == 0 {
+			r.v = r.g.rng.Next()
+			r.left = 4
+		}
+		p[i] = byte(r.v)
+		r.v >>= 8
+		r.left--
+	}
+	return len(p), nil
+}
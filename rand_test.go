@@ -0,0 +1,261 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRandStringFrom(t *testing.T) {
+	for _, charset := range []string{CharsetHex, CharsetDigits, CharsetBase64, "αβγ"} {
+		s := RandStringFrom(charset, 100)
+		if n := utf8.RuneCountInString(s); n != 100 {
+			t.Errorf("%q: got %d characters; want 100", charset, n)
+		}
+		for _, r := range s {
+			if !strings.ContainsRune(charset, r) {
+				t.Errorf("%q: got %q; want only characters from the charset", charset, r)
+				break
+			}
+		}
+	}
+	if s := RandStringFrom("", 10); s != "" {
+		t.Errorf("got %q for an empty charset; want an empty string", s)
+	}
+}
+
+func TestRandUTF8(t *testing.T) {
+	for _, ranges := range [][]RuneRange{nil, RunesASCII, RunesCJK, RunesEmoji, {{0xd700, 0xe000}}} {
+		s := RandUTF8(200, ranges)
+		if !utf8.ValidString(s) {
+			t.Errorf("%v: got invalid UTF-8", ranges)
+		}
+		if n := utf8.RuneCountInString(s); n != 200 {
+			t.Errorf("%v: got %d runes; want 200", ranges, n)
+		}
+	}
+	for _, r := range RandUTF8(100, RunesEmoji) {
+		if utf8.RuneLen(r) != 4 {
+			t.Errorf("got %q; want a 4 byte rune", r)
+		}
+	}
+	// ranges without valid runes aren't picked.
+	for _, ranges := range [][]RuneRange{{{0xd800, 0xdfff}}, {{utf8.MaxRune + 1, utf8.MaxRune + 10}}, {{-10, -1}}} {
+		if s := RandUTF8(10, ranges); s != "" {
+			t.Errorf("%v: got %q; want an empty string", ranges, s)
+		}
+	}
+	for _, r := range RandUTF8(100, []RuneRange{{0xd800, 0xdfff}, {0xdfff, 0xe000}}) {
+		if r != 0xe000 {
+			t.Errorf("got %U; want U+E000", r)
+		}
+	}
+	var lo, hi bool
+	for _, r := range RandUTF8(100, []RuneRange{{0xd7ff, 0xe000}}) {
+		lo, hi = lo || r == 0xd7ff, hi || r == 0xe000
+		if r != 0xd7ff && r != 0xe000 {
+			t.Errorf("got %U; want U+D7FF or U+E000", r)
+		}
+	}
+	if !lo || !hi {
+		t.Errorf("got U+D7FF %t and U+E000 %t; want both", lo, hi)
+	}
+}
+
+func TestGen(t *testing.T) {
+	a, b := NewGen(42), NewGen(42)
+	if a.Seed() != 42 {
+		t.Errorf("got seed %d; want 42", a.Seed())
+	}
+	for i := 0; i < 10; i++ {
+		if x, y := a.RandString(16), b.RandString(16); x != y {
+			t.Fatalf("%d: got %q and %q; want the same string from the same seed", i, x, y)
+		}
+	}
+	if x, y := NewGen(1).RandString(32), NewGen(2).RandString(32); x == y {
+		t.Errorf("got %q from different seeds; want different strings", x)
+	}
+}
+
+func TestRandRanges(t *testing.T) {
+	g := NewGen(7)
+	seen := make([]bool, 10)
+	for i := 0; i < 1000; i++ {
+		n := g.RandIntn(10)
+		if n < 0 || n >= 10 {
+			t.Fatalf("RandIntn: got %d; want [0, 10)", n)
+		}
+		seen[n] = true
+		v := g.RandInt64Range(-5, 5)
+		if v < -5 || v >= 5 {
+			t.Fatalf("RandInt64Range: got %d; want [-5, 5)", v)
+		}
+		f := g.RandFloat64Range(1.5, 2.5)
+		if f < 1.5 || f >= 2.5 {
+			t.Fatalf("RandFloat64Range: got %v; want [1.5, 2.5)", f)
+		}
+	}
+	for i, ok := range seen {
+		if !ok {
+			t.Errorf("RandIntn: never got %d", i)
+		}
+	}
+	// the full int64 range must not overflow.
+	g.RandInt64Range(-1<<63, 1<<63-1)
+	defer func() {
+		if recover() == nil {
+			t.Error("RandIntn(0): got no panic; want one")
+		}
+	}()
+	g.RandIntn(0)
+}
+
+func TestRandNormalExp(t *testing.T) {
+	g := NewGen(3)
+	const n = 100000
+	var sum, sumSq, expSum float64
+	for i := 0; i < n; i++ {
+		v := g.RandNormal(10, 2)
+		sum += v
+		sumSq += v * v
+		e := g.RandExp(4)
+		if e < 0 {
+			t.Fatalf("RandExp: got %v; want >= 0", e)
+		}
+		expSum += e
+	}
+	mean := sum / n
+	sd := math.Sqrt(sumSq/n - mean*mean)
+	if math.Abs(mean-10) > 0.05 || math.Abs(sd-2) > 0.05 {
+		t.Errorf("RandNormal: got mean %.3f, stddev %.3f; want 10, 2", mean, sd)
+	}
+	if m := expSum / n; math.Abs(m-0.25) > 0.01 {
+		t.Errorf("RandExp: got mean %.3f; want 0.25", m)
+	}
+}
+
+func TestFillRandBytes(t *testing.T) {
+	// the same seed generates the same bytes as RandBytes.
+	want := NewGen(7).RandBytes(64)
+	got := make([]byte, 64)
+	NewGen(7).FillRandBytes(got)
+	if !bytes.Equal(got, want) {
+		t.Errorf("got %q; want %q", got, want)
+	}
+	for _, v := range got {
+		if strings.IndexByte(alphanum, v) < 0 {
+			t.Fatalf("got %q; want alphanum bytes", v)
+		}
+	}
+	buf := make([]byte, 256)
+	if n := testing.AllocsPerRun(100, func() { FillRandBytes(buf) }); n != 0 {
+		t.Errorf("got %v allocs; want 0", n)
+	}
+}
+
+func TestAppendRandString(t *testing.T) {
+	g := NewGen(7)
+	want := NewGen(7).RandString(16)
+	got := g.AppendRandString([]byte("key:"), 16)
+	if string(got) != "key:"+want {
+		t.Errorf("got %q; want %q", got, "key:"+want)
+	}
+	if got := g.AppendRandString(nil, 0); len(got) != 0 {
+		t.Errorf("got %q; want nothing appended", got)
+	}
+	// reusing the buffer doesn't allocate.
+	buf := make([]byte, 0, 64)
+	n := testing.AllocsPerRun(100, func() {
+		buf = AppendRandString(buf[:0], 64)
+	})
+	if n != 0 {
+		t.Errorf("got %v allocs; want 0", n)
+	}
+	if len(buf) != 64 {
+		t.Errorf("got %d bytes; want 64", len(buf))
+	}
+}
+
+func TestRandRawBytes(t *testing.T) {
+	c, err := CryptoRandBytes(1 << 16)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, b := range [][]byte{NewGen(11).RandRawBytes(1 << 16), c} {
+		if len(b) != 1<<16 {
+			t.Fatalf("got %d bytes; want %d", len(b), 1<<16)
+		}
+		var seen [256]bool
+		for _, v := range b {
+			seen[v] = true
+		}
+		for i, ok := range seen {
+			if !ok {
+				t.Errorf("never got %d; want the full byte range", i)
+				break
+			}
+		}
+	}
+}
+
+func TestRandReader(t *testing.T) {
+	a, err := ioutil.ReadAll(io.LimitReader(RandReader(8), 1<<20))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(a) != 1<<20 {
+		t.Fatalf("got %d bytes; want %d", len(a), 1<<20)
+	}
+	// odd sized reads must produce the same stream.
+	r := RandReader(8)
+	b := make([]byte, 1<<20)
+	for i := 0; i < len(b); i += 7 {
+		end := i + 7
+		if end > len(b) {
+			end = len(b)
+		}
+		r.Read(b[i:end])
+	}
+	if !bytes.Equal(a, b) {
+		t.Error("got different streams from the same seed; want the same")
+	}
+}
+
+type brokenReader struct{}
+
+func (brokenReader) Read(p []byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestNewSeedErr(t *testing.T) {
+	seed, err := NewSeedErr()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if seed < 0 {
+		t.Errorf("got %d; want a non-negative seed", seed)
+	}
+	entropy = brokenReader{}
+	defer func() { entropy = crand.Reader }()
+	_, err = NewSeedErr()
+	if err == nil {
+		t.Error("got no error; want the entropy read error")
+	}
+	_, err = CryptoRandBytes(16)
+	if err == nil {
+		t.Error("got no error from CryptoRandBytes; want the entropy read error")
+	}
+	// NewSeed falls back to a time based seed.
+	if seed := NewSeed(); seed <= 0 {
+		t.Errorf("got %d; want a positive seed", seed)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "unicode/utf8"
+
+// Column identifies a column of the output.
+type Column int
+
+const (
+	GroupColumn Column = iota
+	SubGroupColumn
+	NameColumn
+	DescColumn
+	OpsColumn
+	NsOpColumn
+	BytesOpColumn
+	AllocsOpColumn
+	NoteColumn
+)
+
+// RowFormatter formats the cells of a bench's row.  A Benchmarker calls it
+// for each of a bench's cells, so the formatting of the values, e.g. their
+// units, rounding, or markup, can be changed without implementing a
+// Benchmarker.
+type RowFormatter interface {
+	// FormatCell returns the bench's cell in the column.
+	FormatCell(c Column, v Bench) string
+}
+
+// RowFormatterFunc adapts a func to a RowFormatter.
+type RowFormatterFunc func(c Column, v Bench) string
+
+// FormatCell implements RowFormatter; it calls f.
+func (f RowFormatterFunc) FormatCell(c Column, v Bench) string {
+	return f(c, v)
+}
+
+// SetRowFormatter sets the formatter used for each of the benches' cells;
+// nil restores the default formatting.  To change only some of the cells,
+// call DefaultCell for the others:
+//
+//	b.SetRowFormatter(benchutil.RowFormatterFunc(func(c benchutil.Column, v benchutil.Bench) string {
+//		if c == benchutil.NsOpColumn {
+//			return time.Duration(v.NsOp / int64(v.Iterations)).String()
+//		}
+//		return b.DefaultCell(c, v)
+//	}))
+func (b *Benches) SetRowFormatter(f RowFormatter) {
+	b.rowFormatter = f
+}
+
+// Cell returns the bench's cell in the column, as it's output.
+func (b *Benches) Cell(c Column, v Bench) string {
+	if b.rowFormatter != nil {
+		return b.rowFormatter.FormatCell(c, v)
+	}
+	return b.DefaultCell(c, v)
+}
+
+// DefaultCell returns the bench's cell in the column as it's formatted
+// when there isn't a RowFormatter.
+func (b *Benches) DefaultCell(c Column, v Bench) string {
+	switch c {
+	case GroupColumn:
+		return v.Group
+	case SubGroupColumn:
+		return v.SubGroup
+	case NameColumn:
+		return v.Name
+	case DescColumn:
+		return v.Desc
+	case OpsColumn:
+		return b.OpsString(v)
+	case NsOpColumn:
+		return b.NsOpString(v)
+	case BytesOpColumn:
+		return b.BytesOpString(v)
+	case AllocsOpColumn:
+		return b.AllocsOpString(v)
+	case NoteColumn:
+		return v.NoteString()
+	}
+	return ""
+}
+
+// setCellLength widens the columns, if necessary, to fit the formatted
+// cells.  This is only needed when there's a RowFormatter, a locale, a
+// precision, or durations; the default widths already fit the default
+// cells.
+func (b *Benches) setCellLength() {
+	if b.rowFormatter == nil && b.locale == nil && len(b.precision) == 0 && !b.nsOpDuration {
+		return
+	}
+	for _, v := range b.Benchmarks {
+		for _, l := range []struct {
+			c Column
+			n *int
+		}{
+			{GroupColumn, &b.length.Group},
+			{SubGroupColumn, &b.length.SubGroup},
+			{NameColumn, &b.length.Name},
+			{DescColumn, &b.length.Desc},
+			{OpsColumn, &b.length.Ops},
+			{NsOpColumn, &b.length.NsOp},
+			{BytesOpColumn, &b.length.BytesOp},
+			{AllocsOpColumn, &b.length.AllocsOp},
+			{NoteColumn, &b.length.Note},
+		} {
+			// an optional column that isn't present stays that way.
+			if *l.n == 0 {
+				continue
+			}
+			if n := utf8.RuneCountInString(b.Cell(l.c, v)); n > *l.n {
+				*l.n = n
+			}
+		}
+	}
+}
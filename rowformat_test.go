@@ -0,0 +1,60 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRowFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewStringBench(&buf)
+	b.Hostname = "test"
+	b.Timestamp = time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	b.SetRowFormatter(RowFormatterFunc(func(c Column, v Bench) string {
+		switch c {
+		case NsOpColumn:
+			return time.Duration(v.NsOp / int64(v.Iterations)).String()
+		case NameColumn:
+			return strings.ToUpper(v.Name)
+		}
+		return b.DefaultCell(c, v)
+	}))
+	b.Append(
+		Bench{Name: "decode", Iterations: 1, Result: Result{Ops: 10, NsOp: 1500000}},
+		Bench{Name: "encode", Iterations: 2, Result: Result{Ops: 10, NsOp: 50}},
+	)
+	err := b.Out()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	rows := lines[len(lines)-2:]
+	for i, want := range []string{"DECODE   10    1.5ms     0          0", "ENCODE   20     25ns     0          0"} {
+		if got := strings.TrimSpace(rows[i]); !strings.HasPrefix(got, want) {
+			t.Errorf("row %d: got %q; want %q", i, got, want)
+		}
+	}
+
+	var csvBuf bytes.Buffer
+	c := NewCSVBench(&csvBuf)
+	c.SetRowFormatter(RowFormatterFunc(func(col Column, v Bench) string {
+		if col == OpsColumn {
+			return "many"
+		}
+		return c.DefaultCell(col, v)
+	}))
+	c.Append(Bench{Name: "decode", Iterations: 1, Result: Result{Ops: 10, NsOp: 5}})
+	err = c.Out()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(csvBuf.String(), "decode,many,5,0,0") {
+		t.Errorf("got %q; want the ops formatted", csvBuf.String())
+	}
+}
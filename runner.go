@@ -0,0 +1,781 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"testing"
+	"time"
+
+	pcg "github.com/dgryski/go-pcgr"
+)
+
+// Observer is notified as a Runner works through its benchmarks.  The index,
+// i, is 0 based; total is the number of benchmarks the Runner has.
+type Observer interface {
+	// BenchStart is called before the benchmark is run.
+	BenchStart(i, total int, b Bench)
+	// BenchDone is called after the benchmark has completed.  The Bench's
+	// Result is populated and elapsed is how long the run took.
+	BenchDone(i, total int, b Bench, elapsed time.Duration)
+}
+
+// runnerBench is a benchmark registered with a Runner.
+type runnerBench struct {
+	Bench
+	f    func(*testing.B)
+	opts BenchOptions
+}
+
+// BenchOptions are the per benchmark run options.  Zero values use the
+// Runner's defaults.
+type BenchOptions struct {
+	// BenchTime is the minimum amount of time the benchmark is run for; this
+	// is the equivalent of 'go test -benchtime'.
+	BenchTime time.Duration
+	// MinIterations is the minimum number of iterations, b.N, the benchmark
+	// is run for.  If the benchmark completes its BenchTime with fewer
+	// iterations, it is re-run for MinIterations.
+	MinIterations int
+}
+
+// Runner runs a set of benchmark functions, using testing.Benchmark, and
+// appends their results to a Benchmarker.
+type Runner struct {
+	benchmarks  []runnerBench
+	observers   []Observer
+	opts        BenchOptions // the default BenchOptions
+	cpuProfile  profileOpts
+	heapProfile profileOpts
+	// cpu scaling checks
+	checkScaling   bool
+	requireScaling bool
+	maxLoad        float64 // the load average above which a warning is added; 0 disables the check.
+	recordThermal  bool
+	cooldown       Cooldown
+	// execution order
+	shuffle bool
+	seed    int64
+	repeat  int
+	// additional sampling
+	adaptive Adaptive
+	cache    *ResultCache
+	hooks    Hooks
+}
+
+// profileOpts configures profile capture.
+type profileOpts struct {
+	dir   string          // the directory the profiles are written to; empty means no profiling.
+	names map[string]bool // the benchmarks to profile; empty means all.
+}
+
+// enabled returns whether or not a profile should be captured for b.
+func (p profileOpts) enabled(b Bench) bool {
+	if p.dir == "" {
+		return false
+	}
+	if len(p.names) == 0 {
+		return true
+	}
+	return p.names[b.Name] || p.names[benchID(b)]
+}
+
+// path returns the path of the profile file for b, using ext as the file
+// extension.
+func (p profileOpts) path(b Bench, ext string) string {
+	return filepath.Join(p.dir, fileName(benchID(b))+ext)
+}
+
+// NewRunner returns an empty Runner.
+func NewRunner() *Runner {
+	return &Runner{}
+}
+
+// Add registers a benchmark function with the Runner.  The Bench provides the
+// information about the benchmark; its Result is set when the function is
+// run.
+func (r *Runner) Add(b Bench, f func(*testing.B)) {
+	if b.Iterations == 0 {
+		b.Iterations = 1
+	}
+	r.benchmarks = append(r.benchmarks, runnerBench{Bench: b, f: f})
+}
+
+// AddWithOptions registers a benchmark function with the Runner using the
+// passed options instead of the Runner's defaults.
+func (r *Runner) AddWithOptions(b Bench, f func(*testing.B), opts BenchOptions) {
+	r.Add(b, f)
+	r.benchmarks[len(r.benchmarks)-1].opts = opts
+}
+
+// BenchTime sets the default minimum amount of time each benchmark is run
+// for; this is the equivalent of 'go test -benchtime'.  The testing
+// package's default is 1s, which is often too short for stable results.
+func (r *Runner) BenchTime(d time.Duration) {
+	r.opts.BenchTime = d
+}
+
+// MinIterations sets the default minimum number of iterations, b.N, each
+// benchmark is run for.
+func (r *Runner) MinIterations(n int) {
+	r.opts.MinIterations = n
+}
+
+// Len returns the number of benchmarks registered with the Runner.
+func (r *Runner) Len() int {
+	return len(r.benchmarks)
+}
+
+// AddObserver adds an Observer that will be notified as each benchmark is
+// run.
+func (r *Runner) AddObserver(o Observer) {
+	r.observers = append(r.observers, o)
+}
+
+// CPUProfile enables the capture of a pprof CPU profile for each benchmark.
+// The profiles are written to dir, which is created if it doesn't exist, and
+// the path of each profile is recorded in the Bench's CPUProfile field.  If
+// any names are passed, only those benchmarks are profiled; a name matches
+// either the Bench's Name or its Group/SubGroup/Name.
+func (r *Runner) CPUProfile(dir string, names ...string) {
+	r.cpuProfile = newProfileOpts(dir, names)
+}
+
+func newProfileOpts(dir string, names []string) profileOpts {
+	p := profileOpts{dir: dir}
+	if len(names) > 0 {
+		p.names = make(map[string]bool, len(names))
+		for _, v := range names {
+			p.names[v] = true
+		}
+	}
+	return p
+}
+
+// HeapProfile enables writing a pprof heap profile after each benchmark has
+// run.  The profiles include the allocation samples, alloc_space and
+// alloc_objects, so they can be viewed as a flame graph with
+// 'go tool pprof -http'.  The profiles are written to dir, which is created if
+// it doesn't exist, and the path of each profile is recorded in the Bench's
+// HeapProfile field.  If any names are passed, only those benchmarks are
+// profiled; a name matches either the Bench's Name or its
+// Group/SubGroup/Name.
+//
+// The heap profile is cumulative for the process; use 'go tool pprof -base'
+// with the prior benchmark's profile to see only what a benchmark allocated.
+func (r *Runner) HeapProfile(dir string, names ...string) {
+	r.heapProfile = newProfileOpts(dir, names)
+}
+
+// CheckCPUScaling enables checking the CPU frequency scaling governor and
+// turbo/boost state before the benchmarks are run.  If the CPU isn't in
+// performance mode, a warning is added to the Benchmarker.  If require is
+// true, Run will also refuse to run the benchmarks and return
+// ErrNotPerformanceMode.
+func (r *Runner) CheckCPUScaling(require bool) {
+	r.checkScaling = true
+	r.requireScaling = require
+}
+
+// CheckLoad enables checking the system load before the benchmarks are run.
+// If the 1 minute load average is above max, a warning, which includes the
+// busiest processes, is added to the Benchmarker.  A max of 0 disables the
+// check.
+func (r *Runner) CheckLoad(max float64) {
+	r.maxLoad = max
+}
+
+// RecordThermal: if true, the CPU package temperature is recorded at the
+// start and end of Run and added to the Benchmarker's system info.  If the
+// CPU likely throttled during the run, a warning is also added.  Output that
+// has already been written, e.g. when streaming, won't include it.
+func (r *Runner) RecordThermal(v bool) {
+	r.recordThermal = v
+}
+
+// SetCooldown sets the cooldown the Runner waits between benchmarks; by
+// default there is none.
+func (r *Runner) SetCooldown(c Cooldown) {
+	r.cooldown = c
+}
+
+// Shuffle enables running the benchmarks in a pseudo-random order, so that
+// drift over a long session doesn't bias the benchmarks that were added
+// last.  If seed is 0, a random seed is used; either way, the seed is
+// available from Seed so the order can be reproduced.  When the benchmarks
+// are repeated, each round is shuffled.
+func (r *Runner) Shuffle(seed int64) {
+	if seed == 0 {
+		seed = NewSeed()
+	}
+	r.shuffle = true
+	r.seed = seed
+}
+
+// Seed returns the seed used to shuffle the benchmarks; 0 if they aren't
+// shuffled.
+func (r *Runner) Seed() int64 {
+	return r.seed
+}
+
+// Repeat sets the number of times each benchmark is run.  The repeats are
+// interleaved round-robin, e.g. a, b, c, a, b, c, instead of running each
+// benchmark n times in a row.  A Bench's Result is the sum of its runs, and
+// its Iterations is n; a Bench is appended to the Benchmarker when its last
+// run completes.
+func (r *Runner) Repeat(n int) {
+	r.repeat = n
+}
+
+// repeats returns the number of times each benchmark is run.
+func (r *Runner) repeats() int {
+	if r.repeat < 1 {
+		return 1
+	}
+	return r.repeat
+}
+
+// schedule returns the order the benchmarks, idx, are to be run in, as
+// indexes into the benchmarks.
+func (r *Runner) schedule(idx []int) []int {
+	repeat := r.repeats()
+	var rng pcg.Rand
+	rng.Seed(r.seed)
+	order := make([]int, len(idx))
+	copy(order, idx)
+	sched := make([]int, 0, len(order)*repeat)
+	for i := 0; i < repeat; i++ {
+		if r.shuffle {
+			for j := len(order) - 1; j > 0; j-- {
+				k := int(rng.Bound(uint32(j + 1)))
+				order[j], order[k] = order[k], order[j]
+			}
+		}
+		sched = append(sched, order...)
+	}
+	return sched
+}
+
+// SetCache sets the ResultCache used by the Runner.  Benchmarks with results
+// in the cache, that were measured with the same benchtime, minimum
+// iterations, repeats, and adaptive sampling, aren't run; their cached
+// results are used.  The results of benchmarks that are run, and didn't
+// fail, are added to the cache.
+func (r *Runner) SetCache(c *ResultCache) {
+	r.cache = c
+}
+
+// Run runs the benchmarks and appends each result to dst.  Unless the
+// Runner shuffles them, the benchmarks are run in the order they were
+// added.
+func (r *Runner) Run(dst Benchmarker) error {
+	if r.checkScaling {
+		s, err := GetCPUScaling()
+		if err != nil {
+			return err
+		}
+		if w := s.Warning(); w != "" {
+			addWarning(dst, w)
+			if r.requireScaling {
+				return ErrNotPerformanceMode
+			}
+		}
+	}
+	if r.maxLoad > 0 {
+		if w := LoadWarning(r.maxLoad); w != "" {
+			addWarning(dst, w)
+		}
+	}
+	var start thermalSample
+	if r.recordThermal {
+		start = sampleThermal()
+	}
+	// benchmarks with cached results aren't run; they are appended in their
+	// place in the schedule, so the order is the same as if they were run.
+	idx := make([]int, len(r.benchmarks))
+	cached := make(map[int]Bench)
+	for i, rb := range r.benchmarks {
+		idx[i] = i
+		if r.cache != nil {
+			b, ok := r.cache.Get(rb.Bench, r.cacheKey(rb))
+			if ok {
+				cached[i] = b
+			}
+		}
+	}
+	sched := r.schedule(idx)
+	s := session{
+		r:       r,
+		total:   len(sched) - len(cached)*r.repeats(),
+		results: make([]Bench, len(r.benchmarks)),
+		samples: make([][]float64, len(r.benchmarks)),
+	}
+	runs := make([]int, len(r.benchmarks))
+	for _, n := range sched {
+		if b, ok := cached[n]; ok {
+			runs[n]++
+			if runs[n] == r.repeats() {
+				dst.Append(b)
+			}
+			continue
+		}
+		err := s.exec(n)
+		if err != nil {
+			return err
+		}
+		if len(s.samples[n]) < r.repeats() {
+			continue
+		}
+		// take additional samples while the variance is too high.
+		for !s.results[n].Failed() && r.adaptive.more(s.samples[n]) {
+			s.total++
+			err := s.exec(n)
+			if err != nil {
+				return err
+			}
+		}
+		b := s.bench(n)
+		if r.cache != nil && !b.Failed() {
+			err := r.cache.Put(b, r.cacheKey(r.benchmarks[n]))
+			if err != nil {
+				return err
+			}
+		}
+		dst.Append(b)
+	}
+	err := s.endGroup()
+	if err != nil {
+		return err
+	}
+	if r.recordThermal {
+		t := thermal(start, sampleThermal())
+		if t == nil {
+			return nil
+		}
+		if t.Throttled {
+			addWarning(dst, "cpu likely throttled during the run: results may not be reproducible")
+		}
+		// the thermal info is optional; not having system info isn't an
+		// error here.
+		if p, ok := dst.(InfoProvider); ok {
+			if si, err := p.Info(); err == nil {
+				si.Thermal = t
+			}
+		}
+	}
+	return nil
+}
+
+// addWarning adds the warning to dst if it's an Annotator.
+func addWarning(dst Benchmarker, s string) {
+	if a, ok := dst.(Annotator); ok {
+		a.AddWarning(s)
+	}
+}
+
+// Hooks are funcs a Runner calls around the benchmarks it runs; e.g. to reset
+// a database or drop the page cache.  The time spent in a hook isn't part of
+// the benchmark's results.  Any nil hook is skipped.  If a hook returns an
+// error, the Runner stops and returns it.
+type Hooks struct {
+	// BeforeEach is called before each run of a benchmark.
+	BeforeEach func(b Bench) error
+	// AfterEach is called after each run of a benchmark.
+	AfterEach func(b Bench) error
+	// BeforeGroup is called before a run of a benchmark whose Group is
+	// different than that of the prior run.  When the benchmarks are
+	// shuffled, this may be called more than once for a group.
+	BeforeGroup func(group string) error
+	// AfterGroup is called after the last run of a benchmark in a group,
+	// before BeforeGroup is called for the next group.
+	AfterGroup func(group string) error
+}
+
+// SetHooks sets the hooks the Runner calls around the benchmarks.
+func (r *Runner) SetHooks(h Hooks) {
+	r.hooks = h
+}
+
+// session holds the state of a Runner's run.
+type session struct {
+	r       *Runner
+	i       int         // the index of the current execution.
+	total   int         // the total number of executions; this grows with adaptive samples.
+	results []Bench     // the accumulated results of each benchmark.
+	samples [][]float64 // the ns/op of each run of each benchmark.
+	group   string      // the group of the prior run.
+	inGroup bool        // whether or not BeforeGroup has been called for group.
+}
+
+// startGroup calls the group hooks, if the group is changing.
+func (s *session) startGroup(group string) error {
+	if s.inGroup && s.group == group {
+		return nil
+	}
+	err := s.endGroup()
+	if err != nil {
+		return err
+	}
+	if s.r.hooks.BeforeGroup != nil {
+		err = s.r.hooks.BeforeGroup(group)
+		if err != nil {
+			return fmt.Errorf("before group %s: %s", group, err)
+		}
+	}
+	s.group = group
+	s.inGroup = true
+	return nil
+}
+
+// endGroup calls the AfterGroup hook for the current group; if there is
+// one.
+func (s *session) endGroup() error {
+	if !s.inGroup {
+		return nil
+	}
+	s.inGroup = false
+	if s.r.hooks.AfterGroup == nil {
+		return nil
+	}
+	err := s.r.hooks.AfterGroup(s.group)
+	if err != nil {
+		return fmt.Errorf("after group %s: %s", s.group, err)
+	}
+	return nil
+}
+
+// exec runs the benchmark at index n once and accumulates its results.
+func (s *session) exec(n int) error {
+	rb := s.r.benchmarks[n]
+	if s.i > 0 {
+		s.r.cooldown.Wait()
+	}
+	err := s.startGroup(rb.Group)
+	if err != nil {
+		return err
+	}
+	if s.r.hooks.BeforeEach != nil {
+		err = s.r.hooks.BeforeEach(rb.Bench)
+		if err != nil {
+			return fmt.Errorf("before %s: %s", benchID(rb.Bench), err)
+		}
+	}
+	for _, o := range s.r.observers {
+		o.BenchStart(s.i, s.total, rb.Bench)
+	}
+	start := time.Now()
+	b, err := s.r.run(rb)
+	if err != nil {
+		return err
+	}
+	elapsed := time.Since(start)
+	for _, o := range s.r.observers {
+		o.BenchDone(s.i, s.total, b, elapsed)
+	}
+	if s.r.hooks.AfterEach != nil {
+		err = s.r.hooks.AfterEach(b)
+		if err != nil {
+			return fmt.Errorf("after %s: %s", benchID(b), err)
+		}
+	}
+	s.i++
+	s.samples[n] = append(s.samples[n], float64(b.NsOp))
+	r := b.Result
+	b.Samples = s.results[n].Samples
+	b.AddSample(r)
+	// a failure of any run fails the benchmark.
+	if s.results[n].Failed() {
+		b.Err = s.results[n].Err
+	}
+	s.results[n] = b
+	return nil
+}
+
+// bench returns the benchmark n with its samples and accumulated results.
+func (s *session) bench(n int) Bench {
+	return s.results[n]
+}
+
+// Adaptive configures the taking of additional samples for benchmarks whose
+// results vary too much.
+type Adaptive struct {
+	// MaxCV is the coefficient of variation, stddev/mean, of the ns/op of a
+	// benchmark's samples above which additional samples are taken; 0
+	// disables adaptive sampling.
+	MaxCV float64
+	// MinSamples is the number of samples needed before the CV is checked;
+	// the minimum is 2.
+	MinSamples int
+	// MaxSamples is the maximum number of samples taken; if it's <= 0,
+	// DefaultMaxSamples is used.
+	MaxSamples int
+}
+
+// DefaultMaxSamples is the maximum number of samples adaptive sampling takes
+// when Adaptive.MaxSamples isn't set.
+const DefaultMaxSamples = 20
+
+// more returns whether or not another sample should be taken.
+func (a Adaptive) more(samples []float64) bool {
+	max := a.MaxSamples
+	if max <= 0 {
+		max = DefaultMaxSamples
+	}
+	if a.MaxCV <= 0 || len(samples) >= max {
+		return false
+	}
+	if len(samples) < a.MinSamples || len(samples) < 2 {
+		return true
+	}
+	return CV(samples) > a.MaxCV
+}
+
+// SetAdaptive enables adaptive sampling: after a benchmark's scheduled runs,
+// it is re-run until the coefficient of variation of its ns/op samples is at,
+// or below, a.MaxCV, or a.MaxSamples have been taken.  The number of samples
+// that were taken is the Bench's Iterations.
+func (r *Runner) SetAdaptive(a Adaptive) {
+	r.adaptive = a
+}
+
+// CV returns the coefficient of variation, the sample standard deviation
+// divided by the mean, of v.  If v has fewer than 2 values, or its mean is 0,
+// 0 is returned.
+func CV(v []float64) float64 {
+	if len(v) < 2 {
+		return 0
+	}
+	var sum float64
+	for _, x := range v {
+		sum += x
+	}
+	mean := sum / float64(len(v))
+	if mean == 0 {
+		return 0
+	}
+	var sq float64
+	for _, x := range v {
+		sq += (x - mean) * (x - mean)
+	}
+	return math.Sqrt(sq/float64(len(v)-1)) / mean
+}
+
+// run runs a single benchmark and returns the Bench with its Result set.
+func (r *Runner) run(rb runnerBench) (Bench, error) {
+	b := rb.Bench
+	if r.cpuProfile.enabled(b) {
+		err := os.MkdirAll(r.cpuProfile.dir, 0755)
+		if err != nil {
+			return b, err
+		}
+		b.CPUProfile = r.cpuProfile.path(b, ".cpu.pprof")
+		f, err := os.Create(b.CPUProfile)
+		if err != nil {
+			return b, err
+		}
+		defer f.Close()
+		err = pprof.StartCPUProfile(f)
+		if err != nil {
+			return b, err
+		}
+		defer pprof.StopCPUProfile()
+	}
+	br, err := r.benchmark(rb)
+	if err != nil {
+		return b, err
+	}
+	b.Result = ResultFromBenchmarkResult(br.BenchmarkResult)
+	b.Err = br.err
+	if r.heapProfile.enabled(b) {
+		var err error
+		b.HeapProfile, err = r.writeHeapProfile(b)
+		if err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+// benchResult is the result of running a benchmark function.  If the
+// benchmark failed, err holds the reason.
+type benchResult struct {
+	testing.BenchmarkResult
+	err string
+}
+
+// capture runs f, recording whether it panicked or failed.  A panic is
+// recovered, so it doesn't crash the process, and the benchmark is marked as
+// failed.
+func capture(f func(*testing.B)) benchResult {
+	var res benchResult
+	br := testing.Benchmark(func(b *testing.B) {
+		var done bool
+		defer func() {
+			if p := recover(); p != nil {
+				res.err = fmt.Sprintf("panic: %v", p)
+				b.Fail()
+				return
+			}
+			if !done && res.err == "" {
+				// f called runtime.Goexit through b.FailNow, b.Fatal, or b.Skip.
+				res.err = "benchmark failed or was skipped"
+			}
+		}()
+		f(b)
+		done = true
+	})
+	res.BenchmarkResult = br
+	if br.N == 0 && res.err == "" {
+		res.err = "benchmark failed"
+	}
+	return res
+}
+
+// benchOptions returns the options rb is run with: its own, with the zero
+// values replaced by the Runner's defaults.
+func (r *Runner) benchOptions(rb runnerBench) BenchOptions {
+	o := rb.opts
+	if o.BenchTime == 0 {
+		o.BenchTime = r.opts.BenchTime
+	}
+	if o.MinIterations == 0 {
+		o.MinIterations = r.opts.MinIterations
+	}
+	return o
+}
+
+// cacheKey returns the settings rb's result is measured with; a cached
+// result that was measured with other settings isn't used.
+func (r *Runner) cacheKey(rb runnerBench) string {
+	a := r.adaptive
+	if a.MaxCV <= 0 {
+		a = Adaptive{}
+	}
+	o := r.benchOptions(rb)
+	return fmt.Sprintf("benchtime=%s miniterations=%d repeat=%d adaptive=%+v", o.BenchTime, o.MinIterations, r.repeats(), a)
+}
+
+// benchmark runs the benchmark function using its options.
+func (r *Runner) benchmark(rb runnerBench) (benchResult, error) {
+	o := r.benchOptions(rb)
+	benchTime, minIters := o.BenchTime, o.MinIterations
+	if benchTime > 0 {
+		restore, err := setBenchTime(benchTime.String())
+		if err != nil {
+			return benchResult{}, err
+		}
+		defer restore()
+	}
+	br := capture(rb.f)
+	if br.err != "" || br.N >= minIters {
+		return br, nil
+	}
+	// too few iterations; run it for exactly the minimum.
+	restore, err := setBenchTime(fmt.Sprintf("%dx", minIters))
+	if err != nil {
+		return br, err
+	}
+	defer restore()
+	return capture(rb.f), nil
+}
+
+// setBenchTime sets the testing package's benchtime flag, which
+// testing.Benchmark uses, to v.  The returned func restores the prior value.
+func setBenchTime(v string) (func(), error) {
+	f := flag.Lookup("test.benchtime")
+	if f == nil {
+		// not a test binary; register the testing flags.
+		testing.Init()
+		f = flag.Lookup("test.benchtime")
+		if f == nil {
+			return nil, fmt.Errorf("benchtime: testing flag not found")
+		}
+	}
+	prior := f.Value.String()
+	err := f.Value.Set(v)
+	if err != nil {
+		return nil, fmt.Errorf("benchtime: %s", err)
+	}
+	return func() { f.Value.Set(prior) }, nil
+}
+
+// writeHeapProfile writes the heap profile for b and returns its path.
+func (r *Runner) writeHeapProfile(b Bench) (string, error) {
+	err := os.MkdirAll(r.heapProfile.dir, 0755)
+	if err != nil {
+		return "", err
+	}
+	path := r.heapProfile.path(b, ".heap.pprof")
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	// get up-to-date statistics
+	runtime.GC()
+	err = pprof.Lookup("heap").WriteTo(f, 0)
+	if err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// benchID returns the Group, SubGroup, and Name of the bench joined by a '/';
+// empty values are skipped.
+func benchID(b Bench) string {
+	var parts []string
+	for _, v := range []string{b.Group, b.SubGroup, b.Name} {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// fileName returns s with any character that isn't safe for use in a file
+// name replaced by an '_'.
+func fileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// StatusObserver writes a status line for each benchmark as it is run, e.g.
+//
+//	running json/Decode (3/42)... done in 1.2s, 840 ns/op
+type StatusObserver struct {
+	w io.Writer
+}
+
+// NewStatusObserver returns a StatusObserver that writes to w.
+func NewStatusObserver(w io.Writer) *StatusObserver {
+	return &StatusObserver{w: w}
+}
+
+// BenchStart writes the running part of the status line.
+func (s *StatusObserver) BenchStart(i, total int, b Bench) {
+	fmt.Fprintf(s.w, "running %s (%d/%d)... ", benchID(b), i+1, total)
+}
+
+// BenchDone completes the status line with the elapsed time and ns/op.
+func (s *StatusObserver) BenchDone(i, total int, b Bench, elapsed time.Duration) {
+	if b.Failed() {
+		fmt.Fprintf(s.w, "FAILED in %s: %s\n", elapsed.Round(100*time.Millisecond), b.Err)
+		return
+	}
+	fmt.Fprintf(s.w, "done in %s, %d ns/op\n", elapsed.Round(100*time.Millisecond), b.NsOp/int64(b.Iterations))
+}
@@ -0,0 +1,408 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func benchSleep(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// newTestRunner returns a Runner with a short benchtime so the tests run
+// quickly.
+func newTestRunner() *Runner {
+	r := NewRunner()
+	r.BenchTime(10 * time.Millisecond)
+	return r
+}
+
+func TestRunner(t *testing.T) {
+	var status bytes.Buffer
+	r := newTestRunner()
+	r.AddObserver(NewStatusObserver(&status))
+	bench := NewBench("sleep")
+	bench.Group = "time"
+	r.Add(bench, benchSleep)
+	if r.Len() != 1 {
+		t.Fatalf("got %d benchmarks; want 1", r.Len())
+	}
+	dst := NewStringBench(&bytes.Buffer{})
+	err := r.Run(dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(dst.Benchmarks) != 1 {
+		t.Fatalf("got %d results; want 1", len(dst.Benchmarks))
+	}
+	if dst.Benchmarks[0].Ops == 0 {
+		t.Error("got 0 ops; want the benchmark's ops")
+	}
+	if !strings.HasPrefix(status.String(), "running time/sleep (1/1)... done in ") {
+		t.Errorf("got %q; want a status line", status.String())
+	}
+}
+
+func TestRunnerCPUProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "benchutil")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	r := newTestRunner()
+	r.CPUProfile(dir, "profiled")
+	r.Add(NewBench("profiled"), benchSleep)
+	r.Add(NewBench("not profiled"), benchSleep)
+	dst := NewStringBench(&bytes.Buffer{})
+	err = r.Run(dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := filepath.Join(dir, "profiled.cpu.pprof")
+	if dst.Benchmarks[0].CPUProfile != want {
+		t.Errorf("got %q; want %q", dst.Benchmarks[0].CPUProfile, want)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Errorf("profile: %s", err)
+	}
+	if dst.Benchmarks[1].CPUProfile != "" {
+		t.Errorf("got %q; want no profile", dst.Benchmarks[1].CPUProfile)
+	}
+}
+
+func TestRunnerHeapProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "benchutil")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	r := newTestRunner()
+	r.HeapProfile(dir)
+	bench := NewBench("alloc")
+	bench.Group = "heap"
+	r.Add(bench, benchSleep)
+	dst := NewStringBench(&bytes.Buffer{})
+	err = r.Run(dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := filepath.Join(dir, "heap_alloc.heap.pprof")
+	if dst.Benchmarks[0].HeapProfile != want {
+		t.Errorf("got %q; want %q", dst.Benchmarks[0].HeapProfile, want)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Errorf("profile: %s", err)
+	}
+}
+
+func TestRunnerCheckCPUScaling(t *testing.T) {
+	restore := fakeSysfs(t, map[string]string{
+		"devices/system/cpu/cpu0/cpufreq/scaling_governor": "powersave\n",
+	})
+	defer restore()
+	r := newTestRunner()
+	r.CheckCPUScaling(true)
+	r.Add(NewBench("sleep"), benchSleep)
+	dst := NewStringBench(&bytes.Buffer{})
+	err := r.Run(dst)
+	if err != ErrNotPerformanceMode {
+		t.Errorf("got %v; want %s", err, ErrNotPerformanceMode)
+	}
+	if len(dst.Warnings) != 1 {
+		t.Errorf("got %d warnings; want 1", len(dst.Warnings))
+	}
+	if len(dst.Benchmarks) != 0 {
+		t.Errorf("got %d results; want none", len(dst.Benchmarks))
+	}
+}
+
+func TestRunnerSchedule(t *testing.T) {
+	r := newTestRunner()
+	for _, v := range []string{"a", "b", "c", "d"} {
+		r.Add(NewBench(v), benchSleep)
+	}
+	r.Repeat(2)
+	all := []int{0, 1, 2, 3}
+	sched := r.schedule(all)
+	want := []int{0, 1, 2, 3, 0, 1, 2, 3}
+	if fmt.Sprint(sched) != fmt.Sprint(want) {
+		t.Errorf("got %v; want %v", sched, want)
+	}
+	r.Shuffle(42)
+	if r.Seed() != 42 {
+		t.Errorf("got seed %d; want 42", r.Seed())
+	}
+	sched = r.schedule(all)
+	if fmt.Sprint(sched) != fmt.Sprint(r.schedule(all)) {
+		t.Error("got different schedules for the same seed; want the same")
+	}
+	counts := make([]int, 4)
+	for i, v := range sched {
+		counts[v]++
+		// every benchmark is run once per round
+		if i == 3 {
+			for j, c := range counts {
+				if c != 1 {
+					t.Errorf("benchmark %d was run %d times in the first round; want 1", j, c)
+				}
+			}
+		}
+	}
+}
+
+func TestRunnerMinIterations(t *testing.T) {
+	r := newTestRunner()
+	r.MinIterations(50)
+	r.Add(NewBench("default"), benchSleep)
+	r.AddWithOptions(NewBench("exact"), benchSleep, BenchOptions{BenchTime: time.Millisecond, MinIterations: 5})
+	dst := NewStringBench(&bytes.Buffer{})
+	err := r.Run(dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if dst.Benchmarks[0].Ops < 50 {
+		t.Errorf("got %d ops; want at least 50", dst.Benchmarks[0].Ops)
+	}
+	if dst.Benchmarks[1].Ops < 5 {
+		t.Errorf("got %d ops; want at least 5", dst.Benchmarks[1].Ops)
+	}
+	if f := flag.Lookup("test.benchtime"); f.Value.String() != "1s" {
+		t.Errorf("got benchtime %s after the run; want it restored to 1s", f.Value)
+	}
+}
+
+func TestCV(t *testing.T) {
+	tests := []struct {
+		v  []float64
+		cv float64
+	}{
+		{nil, 0},
+		{[]float64{10}, 0},
+		{[]float64{10, 10, 10}, 0},
+		{[]float64{2, 4, 4, 4, 5, 5, 7, 9}, 0.4276},
+	}
+	for _, test := range tests {
+		cv := CV(test.v)
+		if math.Abs(cv-test.cv) > 0.0001 {
+			t.Errorf("%v: got %.4f; want %.4f", test.v, cv, test.cv)
+		}
+	}
+}
+
+func TestAdaptiveMore(t *testing.T) {
+	a := Adaptive{MaxCV: 0.05, MinSamples: 3, MaxSamples: 5}
+	tests := []struct {
+		samples []float64
+		more    bool
+	}{
+		{[]float64{100}, true},
+		{[]float64{100, 100}, true},
+		{[]float64{100, 100, 100}, false},
+		{[]float64{100, 200, 100}, true},
+		{[]float64{100, 200, 100, 200, 100}, false},
+	}
+	for _, test := range tests {
+		if a.more(test.samples) != test.more {
+			t.Errorf("%v: got %t; want %t", test.samples, !test.more, test.more)
+		}
+	}
+	if (Adaptive{}).more([]float64{1, 100}) {
+		t.Error("got more samples with adaptive sampling disabled")
+	}
+	// without a MaxSamples, the default cap is used.
+	a = Adaptive{MaxCV: 0.05}
+	noisy := []float64{100, 200}
+	for len(noisy) < DefaultMaxSamples {
+		if !a.more(noisy) {
+			t.Fatalf("%d samples: got no more; want more below the default cap", len(noisy))
+		}
+		noisy = append(noisy, 100, 200)
+	}
+	if a.more(noisy) {
+		t.Errorf("%d samples: got more; want the default cap", len(noisy))
+	}
+}
+
+func TestRunnerAdaptive(t *testing.T) {
+	r := newTestRunner()
+	// a CV that small is never reached, so MaxSamples are taken.
+	r.SetAdaptive(Adaptive{MaxCV: 1e-12, MaxSamples: 3})
+	r.Add(NewBench("sleep"), benchSleep)
+	dst := NewStringBench(&bytes.Buffer{})
+	err := r.Run(dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if dst.Benchmarks[0].Iterations != 3 {
+		t.Errorf("got %d iterations; want 3", dst.Benchmarks[0].Iterations)
+	}
+}
+
+func TestRunnerFailures(t *testing.T) {
+	var status bytes.Buffer
+	r := newTestRunner()
+	r.AddObserver(NewStatusObserver(&status))
+	r.Add(NewBench("panic"), func(b *testing.B) {
+		panic("boom")
+	})
+	r.Add(NewBench("fatal"), func(b *testing.B) {
+		b.Fatal("no good")
+	})
+	r.Add(NewBench("ok"), benchSleep)
+	var buf bytes.Buffer
+	dst := NewStringBench(&buf)
+	err := r.Run(dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(dst.Benchmarks) != 3 {
+		t.Fatalf("got %d results; want 3", len(dst.Benchmarks))
+	}
+	if dst.Benchmarks[0].Err != "panic: boom" {
+		t.Errorf("got %q; want the panic", dst.Benchmarks[0].Err)
+	}
+	if !dst.Benchmarks[1].Failed() {
+		t.Error("fatal: got not failed; want failed")
+	}
+	if dst.Benchmarks[2].Failed() {
+		t.Errorf("ok: got failed: %s", dst.Benchmarks[2].Err)
+	}
+	if !strings.Contains(status.String(), "FAILED in ") {
+		t.Errorf("got %q; want failures in the status", status.String())
+	}
+	err = dst.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(buf.String(), "FAILED: panic: boom") {
+		t.Errorf("got %q; want the failure in the output", buf.String())
+	}
+}
+
+func TestRunnerCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "benchutil")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	c, err := NewResultCacheWithID(dir, "test")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var calls int
+	f := func(b *testing.B) {
+		calls++
+		benchSleep(b)
+	}
+	var prior int
+	for i := 0; i < 2; i++ {
+		prior = calls
+		r := newTestRunner()
+		r.SetCache(c)
+		r.Add(NewBench("cached"), f)
+		dst := NewStringBench(&bytes.Buffer{})
+		err = r.Run(dst)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %s", i, err)
+		}
+		if dst.Benchmarks[0].Ops == 0 {
+			t.Errorf("%d: got 0 ops; want the result", i)
+		}
+	}
+	if calls != prior {
+		t.Errorf("got %d calls on the second run; want the cached result to be used", calls-prior)
+	}
+
+	// a result measured with other settings isn't used.
+	for _, set := range []func(*Runner){
+		func(r *Runner) { r.BenchTime(20 * time.Millisecond) },
+		func(r *Runner) { r.Repeat(2) },
+		func(r *Runner) { r.SetAdaptive(Adaptive{MaxCV: 0.5, MaxSamples: 2}) },
+	} {
+		prior = calls
+		r := newTestRunner()
+		set(r)
+		r.SetCache(c)
+		r.Add(NewBench("cached"), f)
+		err = r.Run(NewStringBench(&bytes.Buffer{}))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if calls == prior {
+			t.Error("got the cached result; want the benchmark run with the other settings")
+		}
+	}
+
+	// the cached results are appended in their place in the order.
+	r := newTestRunner()
+	r.SetCache(c)
+	r.Add(NewBench("first"), benchSleep)
+	r.Add(NewBench("cached"), f)
+	r.Add(NewBench("last"), benchSleep)
+	dst := NewStringBench(&bytes.Buffer{})
+	err = r.Run(dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var names []string
+	for _, v := range dst.Benchmarks {
+		names = append(names, v.Name)
+	}
+	if want := []string{"first", "cached", "last"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v; want %v", names, want)
+	}
+}
+
+func TestRunnerHooks(t *testing.T) {
+	var calls []string
+	r := newTestRunner()
+	r.SetHooks(Hooks{
+		BeforeEach: func(b Bench) error {
+			calls = append(calls, "before "+b.Name)
+			return nil
+		},
+		AfterEach: func(b Bench) error {
+			calls = append(calls, "after "+b.Name)
+			return nil
+		},
+		BeforeGroup: func(g string) error {
+			calls = append(calls, "before group "+g)
+			return nil
+		},
+		AfterGroup: func(g string) error {
+			calls = append(calls, "after group "+g)
+			return nil
+		},
+	})
+	for _, v := range []struct{ group, name string }{{"x", "a"}, {"x", "b"}, {"y", "c"}} {
+		b := NewBench(v.name)
+		b.Group = v.group
+		r.Add(b, benchSleep)
+	}
+	err := r.Run(NewStringBench(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []string{
+		"before group x", "before a", "after a", "before b", "after b", "after group x",
+		"before group y", "before c", "after c", "after group y",
+	}
+	if strings.Join(calls, ", ") != strings.Join(want, ", ") {
+		t.Errorf("got %v; want %v", calls, want)
+	}
+}
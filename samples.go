@@ -0,0 +1,126 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Aggregation is how a bench's samples are combined into the result that's
+// output.
+type Aggregation int
+
+const (
+	AggregateMean   Aggregation = iota // the mean of the samples; this is the default.
+	AggregateMedian                    // the median of each of the samples' values.
+	AggregateMin                       // the minimum of each of the samples' values.
+)
+
+func (a Aggregation) String() string {
+	switch a {
+	case AggregateMean:
+		return "mean"
+	case AggregateMedian:
+		return "median"
+	case AggregateMin:
+		return "min"
+	}
+	return fmt.Sprintf("Aggregation(%d)", int(a))
+}
+
+// AddSample adds the Result of a run of the bench, e.g. from
+// ResultFromBenchmarkResult, to its Samples.  The bench's Result and
+// Iterations are set from all of its samples, so a bench built from samples
+// is output the same way as one with accumulated results: Ops is the mean of
+// the samples' Ops and the per op values are the totals of the samples'.
+// Use Aggregate, or Benches.SetAggregation, for the median or minimum of the
+// samples.
+func (b *Bench) AddSample(r Result) {
+	b.Samples = append(b.Samples, r)
+	b.Iterations = len(b.Samples)
+	b.Result = sumResults(b.Samples)
+	b.Ops /= int64(b.Iterations)
+}
+
+// Aggregate returns a copy of the bench whose Result is its samples combined
+// using a.  For the median and minimum, each value is combined separately
+// and the copy's Iterations is 1.  If the bench has no samples, it is
+// returned unchanged.
+func (b Bench) Aggregate(a Aggregation) Bench {
+	if len(b.Samples) == 0 {
+		return b
+	}
+	switch a {
+	case AggregateMedian:
+		b.Result = Result{
+			Ops:      median(b.Samples, func(r Result) int64 { return r.Ops }),
+			NsOp:     median(b.Samples, func(r Result) int64 { return r.NsOp }),
+			BytesOp:  median(b.Samples, func(r Result) int64 { return r.BytesOp }),
+			AllocsOp: median(b.Samples, func(r Result) int64 { return r.AllocsOp }),
+		}
+		b.Iterations = 1
+	case AggregateMin:
+		b.Result = b.Samples[0]
+		for _, v := range b.Samples[1:] {
+			b.Ops = min64(b.Ops, v.Ops)
+			b.NsOp = min64(b.NsOp, v.NsOp)
+			b.BytesOp = min64(b.BytesOp, v.BytesOp)
+			b.AllocsOp = min64(b.AllocsOp, v.AllocsOp)
+		}
+		b.Iterations = 1
+	default:
+		b.Iterations = len(b.Samples)
+		b.Result = sumResults(b.Samples)
+		b.Ops /= int64(b.Iterations)
+	}
+	return b
+}
+
+// SetAggregation sets how the samples of the benches that have them are
+// combined for output; the default is AggregateMean.  Benches without
+// samples are output as they are.
+func (b *Benches) SetAggregation(a Aggregation) {
+	b.aggregation = a
+}
+
+// aggregate returns v combined using the set's aggregation.
+func (b *Benches) aggregate(v Bench) Bench {
+	if b.aggregation == AggregateMean {
+		return v
+	}
+	return v.Aggregate(b.aggregation)
+}
+
+// sumResults returns the sum of the results.
+func sumResults(results []Result) Result {
+	var r Result
+	for _, v := range results {
+		r = r.add(v)
+	}
+	return r
+}
+
+// median returns the median of the value f gets from each result.  For an
+// even number of results, it's the mean of the middle two.
+func median(results []Result, f func(Result) int64) int64 {
+	v := make([]int64, len(results))
+	for i := range results {
+		v[i] = f(results[i])
+	}
+	sort.Slice(v, func(i, j int) bool { return v[i] < v[j] })
+	n := len(v) / 2
+	if len(v)%2 == 1 {
+		return v[n]
+	}
+	return (v[n-1] + v[n]) / 2
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
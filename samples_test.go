@@ -0,0 +1,81 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "testing"
+
+func sampledBench() Bench {
+	b := NewBench("a")
+	for _, v := range []Result{
+		{Ops: 100, NsOp: 300, BytesOp: 16, AllocsOp: 2},
+		{Ops: 200, NsOp: 100, BytesOp: 32, AllocsOp: 1},
+		{Ops: 300, NsOp: 200, BytesOp: 8, AllocsOp: 1},
+		{Ops: 400, NsOp: 1000, BytesOp: 16, AllocsOp: 4},
+	} {
+		b.AddSample(v)
+	}
+	return b
+}
+
+func TestAddSample(t *testing.T) {
+	b := sampledBench()
+	if len(b.Samples) != 4 || b.Iterations != 4 {
+		t.Fatalf("got %d samples and %d iterations; want 4 and 4", len(b.Samples), b.Iterations)
+	}
+	want := Result{Ops: 250, NsOp: 1600, BytesOp: 72, AllocsOp: 8}
+	if b.Result != want {
+		t.Errorf("got %+v; want %+v", b.Result, want)
+	}
+	var v Benches
+	if s := v.NsOpString(b); s != "400" {
+		t.Errorf("got ns/op %q; want %q", s, "400")
+	}
+	if s := v.OpsString(b); s != "1000" {
+		t.Errorf("got ops %q; want %q", s, "1000")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	b := sampledBench()
+	tests := []struct {
+		a    Aggregation
+		want Result
+		it   int
+	}{
+		{AggregateMean, Result{Ops: 250, NsOp: 1600, BytesOp: 72, AllocsOp: 8}, 4},
+		{AggregateMedian, Result{Ops: 250, NsOp: 250, BytesOp: 16, AllocsOp: 1}, 1},
+		{AggregateMin, Result{Ops: 100, NsOp: 100, BytesOp: 8, AllocsOp: 1}, 1},
+	}
+	for _, test := range tests {
+		got := b.Aggregate(test.a)
+		if got.Result != test.want || got.Iterations != test.it {
+			t.Errorf("%s: got %+v with %d iterations; want %+v with %d", test.a, got.Result, got.Iterations, test.want, test.it)
+		}
+	}
+	// a bench without samples is returned as it is.
+	v := NewBench("b")
+	v.Result = Result{Ops: 10, NsOp: 20}
+	if got := v.Aggregate(AggregateMin); got.Result != v.Result {
+		t.Errorf("got %+v; want %+v", got.Result, v.Result)
+	}
+}
+
+func TestSetAggregation(t *testing.T) {
+	b := NewBenches(WithAggregation(AggregateMedian))
+	b.Append(sampledBench())
+	if s := b.NsOpString(b.Benchmarks[0]); s != "250" {
+		t.Errorf("got ns/op %q; want %q", s, "250")
+	}
+	if s := b.OpsString(b.Benchmarks[0]); s != "250" {
+		t.Errorf("got ops %q; want %q", s, "250")
+	}
+	b.SetAggregation(AggregateMin)
+	if s := b.NsOpString(b.Benchmarks[0]); s != "100" {
+		t.Errorf("got ns/op %q; want %q", s, "100")
+	}
+	if AggregateMin.String() != "min" || Aggregation(9).String() != "Aggregation(9)" {
+		t.Errorf("got %q and %q", AggregateMin, Aggregation(9))
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// sysfs is the mount point of sysfs; it's a var so it can be changed for
+// testing.
+var sysfs = "/sys"
+
+// ErrNotPerformanceMode is returned by a Runner that requires performance
+// mode, see Runner.CheckCPUScaling, when the CPU isn't in performance mode.
+var ErrNotPerformanceMode = errors.New("cpu is not in performance mode")
+
+// CPUScaling holds the CPU frequency scaling state of the system, as reported
+// by sysfs.  On systems without cpufreq information, Governors is empty and
+// TurboKnown is false.
+type CPUScaling struct {
+	Governors  []string // the distinct scaling governors in use, sorted.
+	TurboKnown bool     // whether or not the turbo/boost state could be determined.
+	Turbo      bool     // whether or not turbo/boost is enabled.
+}
+
+// GetCPUScaling reads the CPU frequency scaling governors and the turbo/boost
+// state from sysfs.  Missing files are not an error; the information that
+// isn't available is left empty.
+func GetCPUScaling() (CPUScaling, error) {
+	var s CPUScaling
+	paths, err := filepath.Glob(filepath.Join(sysfs, "devices/system/cpu/cpu[0-9]*/cpufreq/scaling_governor"))
+	if err != nil {
+		return s, err
+	}
+	seen := map[string]bool{}
+	for _, p := range paths {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		g := strings.TrimSpace(string(b))
+		if g == "" || seen[g] {
+			continue
+		}
+		seen[g] = true
+		s.Governors = append(s.Governors, g)
+	}
+	sort.Strings(s.Governors)
+	// intel_pstate reports no_turbo; 1 means turbo is disabled.
+	v, ok := readSysfsValue("devices/system/cpu/intel_pstate/no_turbo")
+	if ok {
+		s.TurboKnown = true
+		s.Turbo = v != "1"
+		return s, nil
+	}
+	// acpi-cpufreq, and others, report boost; 1 means boost is enabled.
+	v, ok = readSysfsValue("devices/system/cpu/cpufreq/boost")
+	if ok {
+		s.TurboKnown = true
+		s.Turbo = v == "1"
+	}
+	return s, nil
+}
+
+// readSysfsValue returns the trimmed contents of the sysfs file at the path,
+// which is relative to the sysfs mount point.  If the file can't be read,
+// false is returned.
+func readSysfsValue(path string) (string, bool) {
+	b, err := ioutil.ReadFile(filepath.Join(sysfs, path))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(b)), true
+}
+
+// PerformanceMode returns whether or not the system is known to be in
+// performance mode: every CPU uses the performance governor and turbo/boost,
+// if it can be determined, is disabled.
+func (s CPUScaling) PerformanceMode() bool {
+	if len(s.Governors) != 1 || s.Governors[0] != "performance" {
+		return false
+	}
+	return !(s.TurboKnown && s.Turbo)
+}
+
+// Warning returns a warning about the scaling state if the system isn't in
+// performance mode.  If the state couldn't be determined, or the system is in
+// performance mode, an empty string is returned.
+func (s CPUScaling) Warning() string {
+	var msgs []string
+	if len(s.Governors) > 0 && (len(s.Governors) != 1 || s.Governors[0] != "performance") {
+		msgs = append(msgs, fmt.Sprintf("cpu scaling governor is %s, not performance", strings.Join(s.Governors, ", ")))
+	}
+	if s.TurboKnown && s.Turbo {
+		msgs = append(msgs, "turbo/boost is enabled")
+	}
+	if len(msgs) == 0 {
+		return ""
+	}
+	return strings.Join(msgs, "; ") + ": results may not be reproducible"
+}
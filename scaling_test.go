@@ -0,0 +1,86 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeSysfs creates a sysfs tree, in a temp dir, with the files and their
+// contents; the returned func restores the sysfs mount point and removes the
+// tree.
+func fakeSysfs(t *testing.T, files map[string]string) func() {
+	dir, err := ioutil.TempDir("", "sysfs")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for k, v := range files {
+		p := filepath.Join(dir, k)
+		err = os.MkdirAll(filepath.Dir(p), 0755)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		err = ioutil.WriteFile(p, []byte(v), 0644)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	orig := sysfs
+	sysfs = dir
+	return func() {
+		sysfs = orig
+		os.RemoveAll(dir)
+	}
+}
+
+func TestGetCPUScaling(t *testing.T) {
+	tests := []struct {
+		files       map[string]string
+		performance bool
+		warning     bool
+	}{
+		{nil, false, false},
+		{
+			map[string]string{
+				"devices/system/cpu/cpu0/cpufreq/scaling_governor": "performance\n",
+				"devices/system/cpu/cpu1/cpufreq/scaling_governor": "performance\n",
+				"devices/system/cpu/intel_pstate/no_turbo":         "1\n",
+			},
+			true, false,
+		},
+		{
+			map[string]string{
+				"devices/system/cpu/cpu0/cpufreq/scaling_governor": "performance\n",
+				"devices/system/cpu/cpu1/cpufreq/scaling_governor": "powersave\n",
+			},
+			false, true,
+		},
+		{
+			map[string]string{
+				"devices/system/cpu/cpu0/cpufreq/scaling_governor": "performance\n",
+				"devices/system/cpu/cpufreq/boost":                 "1\n",
+			},
+			false, true,
+		},
+	}
+	for i, test := range tests {
+		restore := fakeSysfs(t, test.files)
+		s, err := GetCPUScaling()
+		restore()
+		if err != nil {
+			t.Errorf("%d: unexpected error: %s", i, err)
+			continue
+		}
+		if s.PerformanceMode() != test.performance {
+			t.Errorf("%d: got performance mode %t; want %t", i, s.PerformanceMode(), test.performance)
+		}
+		if (s.Warning() != "") != test.warning {
+			t.Errorf("%d: got warning %q; want warning %t", i, s.Warning(), test.warning)
+		}
+	}
+}
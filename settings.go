@@ -0,0 +1,321 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Options holds the output settings of a set of benchmarks, e.g. its
+// columns, sections, and number formatting, so they can be saved, compared,
+// and shared, e.g. with the benchutil command's -options flag, or as part of
+// a Config.  Use the Benchmarker's Options method, e.g. StringBench.Options,
+// to get its settings and Apply to apply them.  The format specific
+// settings, e.g. WrapWidth, are only applied to the formats they apply to.
+//
+// As JSON, Options look like:
+//
+//	{
+//	  "hide_columns": ["desc"],
+//	  "include_system_info": true,
+//	  "section_per_group": true,
+//	  "locale": "de",
+//	  "precision": {"ns_op": 2},
+//	  "aggregation": "median"
+//	}
+type Options struct {
+	Title                     string            `json:"title,omitempty" yaml:"title" toml:"title"`                                                                      // see Benches.SetTitle.
+	Caption                   string            `json:"caption,omitempty" yaml:"caption" toml:"caption"`                                                                // see Benches.SetCaption.
+	Footer                    string            `json:"footer,omitempty" yaml:"footer" toml:"footer"`                                                                   // see Benches.SetFooter.
+	Headers                   HeaderConfig      `json:"headers" yaml:"headers" toml:"headers"`                                                                          // the column headers; empty headers are left as they are.
+	HideColumns               []string          `json:"hide_columns,omitempty" yaml:"hide_columns" toml:"hide_columns"`                                                 // see Benches.HideColumns.
+	ShowColumns               []string          `json:"show_columns,omitempty" yaml:"show_columns" toml:"show_columns"`                                                 // see Benches.ForceColumn.
+	ColumnPadding             int               `json:"column_padding,omitempty" yaml:"column_padding" toml:"column_padding"`                                           // the spaces between columns; 0 leaves it as it is.
+	IncludeOpsColumnDesc      bool              `json:"include_ops_column_desc,omitempty" yaml:"include_ops_column_desc" toml:"include_ops_column_desc"`                // see Benches.IncludeOpsColumnDesc.
+	IncludeSystemInfo         bool              `json:"include_system_info,omitempty" yaml:"include_system_info" toml:"include_system_info"`                            // see Benches.IncludeSystemInfo.
+	IncludeDetailedSystemInfo bool              `json:"include_detailed_system_info,omitempty" yaml:"include_detailed_system_info" toml:"include_detailed_system_info"` // see Benches.IncludeDetailedSystemInfo.
+	IncludeGPUInfo            bool              `json:"include_gpu_info,omitempty" yaml:"include_gpu_info" toml:"include_gpu_info"`                                     // see Benches.IncludeGPUInfo.
+	IncludeDiskInfo           bool              `json:"include_disk_info,omitempty" yaml:"include_disk_info" toml:"include_disk_info"`                                  // see Benches.IncludeDiskInfo.
+	CSVPreamble               bool              `json:"csv_preamble,omitempty" yaml:"csv_preamble" toml:"csv_preamble"`                                                 // see Benches.CSVPreamble.
+	SectionPerGroup           bool              `json:"section_per_group,omitempty" yaml:"section_per_group" toml:"section_per_group"`                                  // see Benches.SectionPerGroup.
+	SectionHeaders            bool              `json:"section_headers,omitempty" yaml:"section_headers" toml:"section_headers"`                                        // see Benches.SectionHeaders.
+	NameSections              bool              `json:"name_sections,omitempty" yaml:"name_sections" toml:"name_sections"`                                              // see Benches.NameSections.
+	Locale                    string            `json:"locale,omitempty" yaml:"locale" toml:"locale"`                                                                   // the locale's tag; see Benches.SetLocale.
+	Precision                 map[string]int    `json:"precision,omitempty" yaml:"precision" toml:"precision"`                                                          // the decimal places, by column: ns_op, bytes_op, or allocs_op; see Benches.SetPrecision.
+	NsOpAsDuration            bool              `json:"ns_op_as_duration,omitempty" yaml:"ns_op_as_duration" toml:"ns_op_as_duration"`                                  // see Benches.NsOpAsDuration.
+	SortedAppend              bool              `json:"sorted_append,omitempty" yaml:"sorted_append" toml:"sorted_append"`                                              // see Benches.SortedAppend.
+	Aggregation               string            `json:"aggregation,omitempty" yaml:"aggregation" toml:"aggregation"`                                                    // mean, median, or min; empty is mean, see Benches.SetAggregation.
+	Parallelism               int               `json:"parallelism,omitempty" yaml:"parallelism" toml:"parallelism"`                                                    // see Benches.SetParallelism.
+	WrapWidth                 int               `json:"wrap_width,omitempty" yaml:"wrap_width" toml:"wrap_width"`                                                       // txt only; see StringBench.WrapWidth.
+	Alignment                 map[string]string `json:"alignment,omitempty" yaml:"alignment" toml:"alignment"`                                                          // txt only; left or right, by column, e.g. ns_op; see StringBench.SetAlignment.
+	SectionHeaderHash         string            `json:"section_header_hash,omitempty" yaml:"section_header_hash" toml:"section_header_hash"`                            // md only; empty leaves it as it is, see MDBench.SectionHeaderHash.
+}
+
+// precisionColumns are the columns a precision can be set for, by their
+// name in Options.
+var precisionColumns = map[string]Column{"ns_op": NsOpColumn, "bytes_op": BytesOpColumn, "allocs_op": AllocsOpColumn}
+
+// columnsByName are the columns, by their name in Options.
+var columnsByName = map[string]Column{
+	"group": GroupColumn, "subgroup": SubGroupColumn, "name": NameColumn, "desc": DescColumn, "ops": OpsColumn,
+	"ns_op": NsOpColumn, "bytes_op": BytesOpColumn, "allocs_op": AllocsOpColumn, "note": NoteColumn,
+}
+
+// alignments are the Alignments, by their name in Options.
+var alignments = map[string]Alignment{"left": AlignLeft, "right": AlignRight}
+
+// LoadOptions loads the options in the file.  The file's format is
+// determined by its extension: .json, .yaml or .yml, or .toml.  Unknown
+// settings are an error, so typos don't go unnoticed.
+func LoadOptions(path string) (*Options, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var o Options
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&o)
+	} else {
+		err = decodeSettings(filepath.Ext(path), data, &o)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("options %s: %s", path, err)
+	}
+	err = o.validate()
+	if err != nil {
+		return nil, fmt.Errorf("options %s: %s", path, err)
+	}
+	return &o, nil
+}
+
+// validate checks the settings that have a fixed set of values.
+func (o *Options) validate() error {
+	for _, col := range o.HideColumns {
+		if !hideable[col] {
+			return fmt.Errorf("hide_columns: %q can't be hidden", col)
+		}
+	}
+	for _, col := range o.ShowColumns {
+		if !hideable[col] {
+			return fmt.Errorf("show_columns: %q can't be forced", col)
+		}
+	}
+	if o.Locale != "" {
+		_, err := LookupLocale(o.Locale)
+		if err != nil {
+			return fmt.Errorf("locale: %s", err)
+		}
+	}
+	for k := range o.Precision {
+		if _, ok := precisionColumns[k]; !ok {
+			return fmt.Errorf("precision: %q isn't a per op column", k)
+		}
+	}
+	_, err := parseAggregation(o.Aggregation)
+	if err != nil {
+		return fmt.Errorf("aggregation: %s", err)
+	}
+	for k, v := range o.Alignment {
+		if _, ok := columnsByName[k]; !ok {
+			return fmt.Errorf("alignment: %q isn't a column", k)
+		}
+		if _, ok := alignments[v]; !ok {
+			return fmt.Errorf("alignment: %s: %q isn't left or right", k, v)
+		}
+	}
+	return nil
+}
+
+// Apply applies the options to the Benchmarker; it replaces the
+// Benchmarker's output settings, except for column headers that are empty in
+// the options and a ColumnPadding of 0, which are left as they are.  The
+// Benchmarker must embed Benches; if it doesn't, or the options aren't
+// valid, an error is returned and nothing is changed.
+func (o *Options) Apply(b Benchmarker) error {
+	d, ok := b.(interface{ benches() *Benches })
+	if !ok || d.benches() == nil {
+		return fmt.Errorf("benchutil: %T doesn't embed Benches", b)
+	}
+	err := o.validate()
+	if err != nil {
+		return fmt.Errorf("benchutil: options: %s", err)
+	}
+	o.apply(d.benches())
+	// the format specific settings are applied to the Benchmarker that's
+	// wrapped, e.g. by a BufferedBench.
+	for {
+		w, ok := b.(interface{ wrapped() Benchmarker })
+		if !ok {
+			break
+		}
+		b = w.wrapped()
+	}
+	switch v := b.(type) {
+	case *StringBench:
+		v.wrapWidth = o.WrapWidth
+		v.align = nil
+		for k, a := range o.Alignment {
+			v.SetAlignment(columnsByName[k], alignments[a])
+		}
+	case *MDBench:
+		if o.SectionHeaderHash != "" {
+			v.SectionHeaderHash = o.SectionHeaderHash
+		}
+	}
+	return nil
+}
+
+// apply applies the validated options to b.
+func (o *Options) apply(b *Benches) {
+	b.optErr = nil
+	WithHeaders(o.Headers)(b)
+	b.title, b.caption, b.footer = o.Title, o.Caption, o.Footer
+	b.hidden, b.shown = nil, nil
+	b.HideColumns(o.HideColumns...)
+	for _, col := range o.ShowColumns {
+		b.ForceColumn(col, true)
+	}
+	if o.ColumnPadding > 0 {
+		b.columnPadding = o.ColumnPadding
+	}
+	b.includeOpsColumnDesc = o.IncludeOpsColumnDesc
+	b.includeSystemInfo = o.IncludeSystemInfo
+	b.includeDetailedSystemInfo = o.IncludeDetailedSystemInfo
+	b.includeGPUInfo = o.IncludeGPUInfo
+	b.includeDiskInfo = o.IncludeDiskInfo
+	b.csvPreamble = o.CSVPreamble
+	b.sectionPerGroup = o.SectionPerGroup
+	b.sectionHeaders = o.SectionHeaders
+	b.nameSections = o.NameSections
+	b.SetLocale(o.Locale)
+	b.precision = nil
+	for k, v := range o.Precision {
+		b.SetPrecision(precisionColumns[k], v)
+	}
+	b.nsOpDuration = o.NsOpAsDuration
+	b.sortedAppend = o.SortedAppend
+	b.aggregation, _ = parseAggregation(o.Aggregation)
+	b.parallelism = o.Parallelism
+}
+
+// Options returns the set's output settings.  The row formatter, if one is
+// set, isn't included as it can't be saved, nor are the format specific
+// settings; see StringBench.Options and MDBench.Options.
+func (b *Benches) Options() Options {
+	o := Options{
+		Title:   b.title,
+		Caption: b.caption,
+		Footer:  b.footer,
+		Headers: HeaderConfig{
+			Group:    b.header.Group,
+			SubGroup: b.header.SubGroup,
+			Name:     b.header.Name,
+			Desc:     b.header.Desc,
+			Ops:      b.header.Ops,
+			NsOp:     b.header.NsOp,
+			BytesOp:  b.header.BytesOp,
+			AllocsOp: b.header.AllocsOp,
+			Note:     b.header.Note,
+		},
+		HideColumns:               columnNames(b.hidden),
+		ShowColumns:               columnNames(b.shown),
+		ColumnPadding:             b.columnPadding,
+		IncludeOpsColumnDesc:      b.includeOpsColumnDesc,
+		IncludeSystemInfo:         b.includeSystemInfo,
+		IncludeDetailedSystemInfo: b.includeDetailedSystemInfo,
+		IncludeGPUInfo:            b.includeGPUInfo,
+		IncludeDiskInfo:           b.includeDiskInfo,
+		CSVPreamble:               b.csvPreamble,
+		SectionPerGroup:           b.sectionPerGroup,
+		SectionHeaders:            b.sectionHeaders,
+		NameSections:              b.nameSections,
+		NsOpAsDuration:            b.nsOpDuration,
+		SortedAppend:              b.sortedAppend,
+		Parallelism:               b.parallelism,
+	}
+	if b.locale != nil {
+		o.Locale = b.localeTag
+	}
+	for k, c := range precisionColumns {
+		if p, ok := b.precision[c]; ok {
+			if o.Precision == nil {
+				o.Precision = make(map[string]int)
+			}
+			o.Precision[k] = p
+		}
+	}
+	if b.aggregation != AggregateMean {
+		o.Aggregation = b.aggregation.String()
+	}
+	return o
+}
+
+// Options returns the output settings, including the text specific ones:
+// the wrap width and the alignment overrides.
+func (b *StringBench) Options() Options {
+	o := b.Benches.Options()
+	o.WrapWidth = b.wrapWidth
+	for k, c := range columnsByName {
+		if a := b.align[c]; a != AlignDefault {
+			if o.Alignment == nil {
+				o.Alignment = make(map[string]string)
+			}
+			o.Alignment[k] = alignmentName(a)
+		}
+	}
+	return o
+}
+
+// alignmentName returns the name of the Alignment in Options.
+func alignmentName(a Alignment) string {
+	if a == AlignRight {
+		return "right"
+	}
+	return "left"
+}
+
+// Options returns the output settings, including the Markdown specific
+// one: the section header hash, if it isn't the default.
+func (b *MDBench) Options() Options {
+	o := b.Benches.Options()
+	if b.SectionHeaderHash != defaultSectionHeaderHash {
+		o.SectionHeaderHash = b.SectionHeaderHash
+	}
+	return o
+}
+
+// columnNames returns the names of the columns that are set in m, sorted.
+func columnNames(m map[string]bool) []string {
+	var cols []string
+	for k, v := range m {
+		if v {
+			cols = append(cols, k)
+		}
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+// parseAggregation returns the Aggregation for s: mean, median, or min; empty
+// is mean.
+func parseAggregation(s string) (Aggregation, error) {
+	switch s {
+	case "", "mean":
+		return AggregateMean, nil
+	case "median":
+		return AggregateMedian, nil
+	case "min":
+		return AggregateMin, nil
+	}
+	return AggregateMean, fmt.Errorf("unknown aggregation %q", s)
+}
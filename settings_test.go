@@ -0,0 +1,173 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOptionsRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewMDBench(&buf, WithSystemInfo(), WithSections(), WithHiddenColumns("desc"), WithAggregation(AggregateMin), WithSortedAppend())
+	b.SetTitle("encoding")
+	b.SetNsOpColumnHeader("ns per op")
+	b.ForceColumn("note", true)
+	b.SetPrecision(BytesOpColumn, 1)
+	b.NsOpAsDuration(true)
+	if err := b.SetLocale("de"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	o := b.Options()
+	data, err := json.Marshal(o)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var decoded Options
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(decoded, o) {
+		t.Fatalf("got %+v; want %+v", decoded, o)
+	}
+
+	s := NewStringBench(&buf)
+	if err := decoded.Apply(s); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := s.Options(); !reflect.DeepEqual(got, o) {
+		t.Errorf("got %+v; want %+v", got, o)
+	}
+	if s.header.NsOp != "ns per op" || !s.hidden["desc"] || !s.shown["note"] || s.aggregation != AggregateMin || s.locale == nil {
+		t.Errorf("got %+v; want the options applied", s.Benches)
+	}
+}
+
+func TestOptionsApply(t *testing.T) {
+	b := NewStringBench(ioutil.Discard, WithHiddenColumns("group"), WithSections())
+	b.SetPrecision(NsOpColumn, 2)
+	o := Options{ShowColumns: []string{"desc"}}
+	if err := o.Apply(b); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// the settings are replaced, not merged.
+	if b.hidden["group"] || b.sectionPerGroup || len(b.precision) != 0 || !b.shown["desc"] {
+		t.Errorf("got %+v; want the settings replaced", b.Benches)
+	}
+	// empty headers and padding are left as they are.
+	if b.header.Name != "Name" || b.columnPadding != defaultPadding {
+		t.Errorf("got header %q and padding %d; want %q and %d", b.header.Name, b.columnPadding, "Name", defaultPadding)
+	}
+
+	tests := []struct {
+		o   Options
+		err string
+	}{
+		{Options{HideColumns: []string{"ops"}}, "can't be hidden"},
+		{Options{ShowColumns: []string{"ns_op"}}, "can't be forced"},
+		{Options{Locale: "xx"}, "unknown locale"},
+		{Options{Precision: map[string]int{"ops": 1}}, "isn't a per op column"},
+		{Options{Aggregation: "max"}, "unknown aggregation"},
+	}
+	for _, test := range tests {
+		err := test.o.Apply(b)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%+v: got %v; want an error containing %q", test.o, err, test.err)
+		}
+	}
+	if !b.shown["desc"] {
+		t.Error("got the settings changed by invalid options")
+	}
+}
+
+func TestFormatOptions(t *testing.T) {
+	s := NewStringBench(ioutil.Discard)
+	s.WrapWidth(20)
+	s.SetAlignment(NameColumn, AlignRight)
+	s.SetAlignment(OpsColumn, AlignLeft)
+	s.SetParallelism(4)
+	o := s.Options()
+	want := s.Benches.Options()
+	want.WrapWidth = 20
+	want.Alignment = map[string]string{"name": "right", "ops": "left"}
+	if want.Parallelism != 4 {
+		t.Errorf("got parallelism %d; want 4", want.Parallelism)
+	}
+	if !reflect.DeepEqual(o, want) {
+		t.Fatalf("got %+v; want %+v", o, want)
+	}
+	// the format specific settings are applied through a wrapper.
+	bb, err := NewBufferedBench(ioutil.Discard, "txt", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := o.Apply(bb); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := bb.b.(*StringBench).Options(); !reflect.DeepEqual(got, want) {
+		t.Errorf("buffered: got %+v; want %+v", got, want)
+	}
+
+	m := NewMDBench(ioutil.Discard)
+	if o := m.Options(); o.SectionHeaderHash != "" {
+		t.Errorf("got section header hash %q; want none for the default", o.SectionHeaderHash)
+	}
+	hash := Options{SectionHeaderHash: "##"}
+	if err := hash.Apply(m); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if m.SectionHeaderHash != "##" || m.Options().SectionHeaderHash != "##" {
+		t.Errorf("got section header hash %q; want %q", m.SectionHeaderHash, "##")
+	}
+	// the settings of other formats are ignored.
+	if err := o.Apply(m); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if m.parallelism != 4 || m.SectionHeaderHash != "##" {
+		t.Errorf("got parallelism %d and hash %q; want 4 and %q", m.parallelism, m.SectionHeaderHash, "##")
+	}
+
+	for _, o := range []Options{{Alignment: map[string]string{"nsop": "left"}}, {Alignment: map[string]string{"ns_op": "center"}}} {
+		if err := o.Apply(s); err == nil || !strings.Contains(err.Error(), "alignment") {
+			t.Errorf("%+v: got %v; want an alignment error", o, err)
+		}
+	}
+}
+
+func TestLoadOptions(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"opts.json": `{"title": "t", "precision": {"ns_op": 2}, "aggregation": "median"}`,
+		"opts.yaml": "title: t\nprecision:\n  ns_op: 2\naggregation: median\n",
+		"opts.toml": "title = \"t\"\naggregation = \"median\"\n[precision]\nns_op = 2\n",
+	}
+	want := Options{Title: "t", Precision: map[string]int{"ns_op": 2}, Aggregation: "median"}
+	for name, s := range files {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(s), 0644); err != nil {
+			t.Fatal(err)
+		}
+		o, err := LoadOptions(path)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(*o, want) {
+			t.Errorf("%s: got %+v; want %+v", name, *o, want)
+		}
+	}
+	path := filepath.Join(dir, "bad.json")
+	if err := ioutil.WriteFile(path, []byte(`{"titel": "t"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOptions(path); err == nil {
+		t.Error("got no error for an unknown setting")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "reflect"
+
+// Shuffle pseudo-randomly shuffles slice in place.  Using a seeded Gen's
+// Shuffle makes the order reproducible.  It panics if slice isn't a slice.
+func Shuffle(slice interface{}) {
+	defaultGen.Shuffle(slice)
+}
+
+// Shuffle pseudo-randomly shuffles slice in place.  It panics if slice isn't
+// a slice.
+func (g *Gen) Shuffle(slice interface{}) {
+	swap := reflect.Swapper(slice)
+	n := reflect.ValueOf(slice).Len()
+	// Fisher-Yates
+	for i := n - 1; i > 0; i-- {
+		swap(i, g.RandIntn(i+1))
+	}
+}
+
+// Perm returns a pseudo-random permutation of the ints [0, n).
+func Perm(n int) []int {
+	return defaultGen.Perm(n)
+}
+
+// Perm returns a pseudo-random permutation of the ints [0, n).
+func (g *Gen) Perm(n int) []int {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	g.Shuffle(p)
+	return p
+}
+
+// SampleN returns a new slice, of the same type as slice, holding n elements
+// pseudo-randomly chosen from slice without replacement; the elements are in
+// a random order.  If n is greater than the length of slice, every element is
+// returned.  It panics if slice isn't a slice.
+func SampleN(slice interface{}, n int) interface{} {
+	return defaultGen.SampleN(slice, n)
+}
+
+// SampleN returns a new slice holding n elements pseudo-randomly chosen from
+// slice without replacement.  See the SampleN function.
+func (g *Gen) SampleN(slice interface{}, n int) interface{} {
+	v := reflect.ValueOf(slice)
+	if v.Kind() != reflect.Slice {
+		panic("SampleN: not a slice: " + v.Kind().String())
+	}
+	l := v.Len()
+	if n > l {
+		n = l
+	}
+	if n < 0 {
+		n = 0
+	}
+	// a partial Fisher-Yates over the indexes; only the first n are
+	// shuffled.
+	idx := make([]int, l)
+	for i := range idx {
+		idx[i] = i
+	}
+	out := reflect.MakeSlice(v.Type(), n, n)
+	for i := 0; i < n; i++ {
+		j := i + g.RandIntn(l-i)
+		idx[i], idx[j] = idx[j], idx[i]
+		out.Index(i).Set(v.Index(idx[i]))
+	}
+	return out.Interface()
+}
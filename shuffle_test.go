@@ -0,0 +1,56 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestShuffle(t *testing.T) {
+	a := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	b := append([]string(nil), a...)
+	NewGen(4).Shuffle(a)
+	NewGen(4).Shuffle(b)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("got %v and %v; want the same order from the same seed", a, b)
+		}
+	}
+	sort.Strings(a)
+	if a[0] != "a" || a[7] != "h" {
+		t.Errorf("got %v; want the same elements", a)
+	}
+	p := NewGen(4).Perm(10)
+	sort.Ints(p)
+	for i, v := range p {
+		if v != i {
+			t.Fatalf("got %v; want a permutation of 0-9", p)
+		}
+	}
+}
+
+func TestSampleN(t *testing.T) {
+	src := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	s := NewGen(4).SampleN(src, 4).([]int)
+	if len(s) != 4 {
+		t.Fatalf("got %d elements; want 4", len(s))
+	}
+	seen := map[int]bool{}
+	for _, v := range s {
+		if seen[v] {
+			t.Errorf("got %v; want no duplicates", s)
+		}
+		seen[v] = true
+	}
+	for i, v := range src {
+		if v != i {
+			t.Fatalf("got %v; want the source left alone", src)
+		}
+	}
+	if s := SampleN(src, 20).([]int); len(s) != 10 {
+		t.Errorf("got %d elements; want all 10", len(s))
+	}
+}
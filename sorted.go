@@ -0,0 +1,49 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "sort"
+
+// SortedAppend sets whether or not Append keeps the benches ordered by
+// Group, SubGroup, and Name, instead of in the order they're appended, so
+// the output doesn't need to be sorted.  Benches with the same Group,
+// SubGroup, and Name stay in the order they were appended.  Only benches
+// appended after it's set are ordered.
+//
+// When streaming, rows that have been written can't be moved: a bench that
+// orders before them is inserted, in order, after the rows already written.
+func (b *Benches) SortedAppend(v bool) {
+	b.sortedAppend = v
+}
+
+// add adds the benches to the set; if sortedAppend is set, each bench is
+// inserted, in order, at or after index from.  Streaming Benchmarkers pass
+// the number of rows they've written so written rows don't move.
+func (b *Benches) add(from int, benches []Bench) {
+	b.setRunInfo()
+	if !b.sortedAppend {
+		b.Benchmarks = append(b.Benchmarks, benches...)
+		return
+	}
+	for _, v := range benches {
+		tail := b.Benchmarks[from:]
+		i := from + sort.Search(len(tail), func(j int) bool { return benchLess(v, tail[j]) })
+		b.Benchmarks = append(b.Benchmarks, Bench{})
+		copy(b.Benchmarks[i+1:], b.Benchmarks[i:])
+		b.Benchmarks[i] = v
+	}
+}
+
+// benchLess returns whether or not a orders before b by Group, SubGroup, and
+// Name.
+func benchLess(a, b Bench) bool {
+	if a.Group != b.Group {
+		return a.Group < b.Group
+	}
+	if a.SubGroup != b.SubGroup {
+		return a.SubGroup < b.SubGroup
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func benchIDs(benches []Bench) []string {
+	var ids []string
+	for _, v := range benches {
+		ids = append(ids, v.ID())
+	}
+	return ids
+}
+
+func TestSortedAppend(t *testing.T) {
+	b := NewBenches(WithSortedAppend())
+	for _, v := range [][3]string{
+		{"xml", "", "b"},
+		{"json", "small", "b"},
+		{"json", "large", "a"},
+		{"json", "small", "a"},
+		{"", "", "z"},
+	} {
+		bench := NewBench(v[2])
+		bench.Group = v[0]
+		bench.SubGroup = v[1]
+		b.Append(bench)
+	}
+	// equal keys keep the order they were appended in.
+	dup := NewBench("a")
+	dup.Group, dup.SubGroup, dup.Note = "json", "small", "second"
+	b.Append(dup)
+	want := []string{"z", "json/large/a", "json/small/a", "json/small/a", "json/small/b", "xml/b"}
+	if got := benchIDs(b.Benchmarks); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	if b.Benchmarks[3].Note != "second" {
+		t.Errorf("got %q; want the duplicate after the first", b.Benchmarks[3].Note)
+	}
+
+	// unsorted appends are left as they are.
+	u := NewBenches()
+	u.Append(NewBench("b"), NewBench("a"))
+	if got := benchIDs(u.Benchmarks); !reflect.DeepEqual(got, []string{"b", "a"}) {
+		t.Errorf("got %v; want [b a]", got)
+	}
+}
+
+func TestSortedAppendStream(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewStringBench(&buf, WithSortedAppend())
+	b.Stream(true)
+	b.Append(NewBench("c"), NewBench("b"))
+	// a orders before the written rows, but they can't move.
+	b.Append(NewBench("d"), NewBench("a"))
+	if err := b.Out(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []string{"b", "c", "a", "d"}
+	if got := benchIDs(b.Benchmarks); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	var rows []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if f := strings.Fields(line); len(f) > 0 && len(f[0]) == 1 {
+			rows = append(rows, f[0])
+		}
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got rows %v; want %v", rows, want)
+	}
+}
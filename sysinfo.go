@@ -0,0 +1,425 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	human "github.com/dustin/go-humanize"
+)
+
+// SysInfo holds information about the system the benchmarks were run on.
+type SysInfo struct {
+	CPUModel       string            `json:"cpu_model"`                  // the model name of the first processor.
+	Cores          int               `json:"cores"`                      // the number of processors.
+	CPUMHz         float64           `json:"cpu_mhz"`                    // the speed of the first processor.
+	Cache          string            `json:"cache"`                      // the cache size of the first processor.
+	CPUVendor      string            `json:"cpu_vendor,omitempty"`       // the vendor of the processors, e.g. GenuineIntel.
+	CPUFamily      string            `json:"cpu_family,omitempty"`       // the processors' family number; not all platforms provide it.
+	CPUModelNumber string            `json:"cpu_model_number,omitempty"` // the processors' model number; not all platforms provide it.
+	CPUStepping    string            `json:"cpu_stepping,omitempty"`     // the processors' stepping; not all platforms provide it.
+	CPUFeatures    []string          `json:"cpu_features,omitempty"`     // the SIMD and crypto ISA extensions the processors support, e.g. avx2, sve.
+	SystemVendor   string            `json:"system_vendor,omitempty"`    // the system's manufacturer, from DMI/SMBIOS.
+	ProductName    string            `json:"product_name,omitempty"`     // the system's product name, from DMI/SMBIOS.
+	BIOSVendor     string            `json:"bios_vendor,omitempty"`      // the BIOS vendor, from DMI/SMBIOS.
+	BIOSVersion    string            `json:"bios_version,omitempty"`     // the BIOS version, from DMI/SMBIOS.
+	BIOSDate       string            `json:"bios_date,omitempty"`        // the BIOS release date, from DMI/SMBIOS.
+	MemTotal       uint64            `json:"mem_total"`                  // total memory, in bytes.
+	OS             string            `json:"os"`                         // the OS name and version.
+	Kernel         string            `json:"kernel,omitempty"`           // the kernel version; optional.
+	Processors     []Processor       `json:"processors,omitempty"`       // information about each processor.
+	GoVersion      string            `json:"go_version,omitempty"`       // the Go version the binary was built with.
+	GOOS           string            `json:"goos,omitempty"`             // the OS the binary was built for.
+	GOARCH         string            `json:"goarch,omitempty"`           // the architecture the binary was built for.
+	Compiler       string            `json:"compiler,omitempty"`         // the Go compiler that built the binary.
+	GoEnv          map[string]string `json:"go_env,omitempty"`           // the GO* settings that affect results, e.g. GOGC, GOAMD64; only those that are set.
+	NumCPU         int               `json:"num_cpu,omitempty"`          // the number of logical CPUs usable by the process.
+	GOMAXPROCS     int               `json:"gomaxprocs,omitempty"`       // the GOMAXPROCS in effect.
+	Container      string            `json:"container,omitempty"`        // the container runtime, e.g. docker; empty if not in a container.
+	CPUQuota       float64           `json:"cpu_quota,omitempty"`        // the cgroup CPU quota, in CPUs; 0 if there's no quota.
+	MemLimit       uint64            `json:"mem_limit,omitempty"`        // the cgroup memory limit, in bytes; 0 if there's no limit.
+	Virtualization string            `json:"virtualization,omitempty"`   // the hypervisor, e.g. kvm, xen; empty on bare metal or if unknown.
+	Cloud          string            `json:"cloud,omitempty"`            // the cloud provider: aws, gcp, or azure; empty if not in a known cloud.
+	InstanceType   string            `json:"instance_type,omitempty"`    // the cloud instance type, e.g. c5.xlarge; if it could be determined.
+	Thermal        *Thermal          `json:"thermal,omitempty"`          // the CPU temperature at the start and end of the run; only set if a Runner recorded it.
+	NUMANodes      []NUMANode        `json:"numa_nodes,omitempty"`       // the NUMA nodes and their CPUs.
+	Disks          []Disk            `json:"disks,omitempty"`            // the system's block devices; only set if disk info is included.
+	Filesystem     string            `json:"filesystem,omitempty"`       // the type of filesystem the working directory is on; only set if disk info is included.
+	FSDevice       string            `json:"fs_device,omitempty"`        // the device the working directory's filesystem is mounted from; only set if disk info is included.
+	GPUs           []GPU             `json:"gpus,omitempty"`             // the system's GPUs; only set if GPU info is included.
+}
+
+// goEnvVars are the environment variables, read at run time, that affect
+// benchmark results.
+var goEnvVars = []string{"GOGC", "GOMAXPROCS", "GOMEMLIMIT", "GODEBUG", "GOEXPERIMENT"}
+
+// goBuildSettings are the build settings, from the binary's build info, that
+// affect benchmark results.
+var goBuildSettings = []string{"GOAMD64", "GOARM", "GOARM64", "GO386", "GOMIPS", "GOPPC64", "GORISCV64", "GOWASM", "GOEXPERIMENT", "CGO_ENABLED", "-gcflags", "-tags"}
+
+// Processor holds information about a processor.
+type Processor struct {
+	ID    int     `json:"id"`    // the processor number.
+	Model string  `json:"model"` // the processor's model name.
+	MHz   float64 `json:"mhz"`   // the processor's speed.
+	Cache string  `json:"cache"` // the size of the processor's cache.
+}
+
+// GetSysInfo gets the information about the system.
+func GetSysInfo() (*SysInfo, error) {
+	s, err := getSysInfo()
+	if err != nil {
+		return nil, err
+	}
+	s.Cores = len(s.Processors)
+	if s.Cores > 0 {
+		s.CPUModel = s.Processors[0].Model
+		s.CPUMHz = s.Processors[0].MHz
+		s.Cache = s.Processors[0].Cache
+	}
+	s.CPUFeatures = cpuFeatures()
+	s.setDMIInfo()
+	s.setGoInfo()
+	c := getContainerInfo()
+	s.Container = c.Container
+	s.CPUQuota = c.CPUQuota
+	s.MemLimit = c.MemLimit
+	v := getVirtInfo()
+	s.Virtualization = v.Virtualization
+	s.Cloud = v.Cloud
+	s.InstanceType = v.InstanceType
+	s.NUMANodes = getNUMANodes()
+	return &s, nil
+}
+
+// setGoInfo sets the Go toolchain and runtime information.  The GOMAXPROCS
+// is the value in effect when this is called.
+func (s *SysInfo) setGoInfo() {
+	s.GoVersion = runtime.Version()
+	s.GOOS = runtime.GOOS
+	s.GOARCH = runtime.GOARCH
+	s.Compiler = runtime.Compiler
+	s.NumCPU = runtime.NumCPU()
+	s.GOMAXPROCS = runtime.GOMAXPROCS(0)
+	s.GoEnv = map[string]string{}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, v := range bi.Settings {
+			for _, k := range goBuildSettings {
+				if v.Key == k && v.Value != "" {
+					s.GoEnv[k] = v.Value
+				}
+			}
+		}
+	}
+	// the run time environment takes precedence.
+	for _, k := range goEnvVars {
+		if v := os.Getenv(k); v != "" {
+			s.GoEnv[k] = v
+		}
+	}
+}
+
+// containerInfo returns the container, cgroup limit, and virtualization
+// info, if there is any, as key value pairs.
+func (s *SysInfo) containerInfo() [][2]string {
+	var kv [][2]string
+	if s.Container != "" {
+		kv = append(kv, [2]string{"Container", s.Container})
+	}
+	if s.CPUQuota > 0 {
+		kv = append(kv, [2]string{"CPU quota", fmt.Sprintf("%.2f CPUs", s.CPUQuota)})
+	}
+	if s.MemLimit > 0 {
+		kv = append(kv, [2]string{"Mem limit", human.Bytes(s.MemLimit)})
+	}
+	if s.Virtualization != "" {
+		kv = append(kv, [2]string{"Virt", s.Virtualization})
+	}
+	if s.Cloud != "" {
+		inst := s.Cloud
+		if s.InstanceType != "" {
+			inst += " " + s.InstanceType
+		}
+		kv = append(kv, [2]string{"Instance", inst})
+	}
+	if s.Thermal != nil {
+		kv = append(kv, [2]string{"Temp", s.Thermal.String()})
+	}
+	return kv
+}
+
+// cpuIDString returns the processors' vendor, family, model number, and
+// stepping, e.g. "GenuineIntel family 6 model 85 stepping 4".  Whatever
+// isn't known is left out.
+func (s *SysInfo) cpuIDString() string {
+	var parts []string
+	if s.CPUVendor != "" {
+		parts = append(parts, s.CPUVendor)
+	}
+	if s.CPUFamily != "" {
+		parts = append(parts, "family "+s.CPUFamily)
+	}
+	if s.CPUModelNumber != "" {
+		parts = append(parts, "model "+s.CPUModelNumber)
+	}
+	if s.CPUStepping != "" {
+		parts = append(parts, "stepping "+s.CPUStepping)
+	}
+	return strings.Join(parts, " ")
+}
+
+// goString returns the Go toolchain info, e.g. "go1.7 linux/amd64 (gc)".
+func (s *SysInfo) goString() string {
+	return fmt.Sprintf("%s %s/%s (%s)", s.GoVersion, s.GOOS, s.GOARCH, s.Compiler)
+}
+
+// goEnvString returns the Go settings as a space separated list of
+// key=value pairs, sorted by key.
+func (s *SysInfo) goEnvString() string {
+	keys := make([]string, 0, len(s.GoEnv))
+	for k := range s.GoEnv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		keys[i] = k + "=" + s.GoEnv[k]
+	}
+	return strings.Join(keys, " ")
+}
+
+// osGoInfo returns the OS and Go info as key value pairs.
+func (s *SysInfo) osGoInfo() [][2]string {
+	kv := [][2]string{{"OS", s.OS}}
+	// os kernel info
+	if s.Kernel != "" {
+		kv = append(kv, [2]string{"Kernel", s.Kernel})
+	}
+	// go info
+	if s.GoVersion != "" {
+		kv = append(kv, [2]string{"Go", s.goString()})
+	}
+	if len(s.GoEnv) > 0 {
+		kv = append(kv, [2]string{"Go env", s.goEnvString()})
+	}
+	if s.NumCPU > 0 {
+		kv = append(kv, [2]string{"NumCPU", strconv.Itoa(s.NumCPU)})
+		kv = append(kv, [2]string{"GOMAXPROCS", strconv.Itoa(s.GOMAXPROCS)})
+	}
+	return kv
+}
+
+// KeyValues returns the system information as ordered key value pairs, in
+// the order they are written by String.  If detailed is true, the pairs
+// written by DetailedString, which include information about every
+// processor, are returned.  Keys may repeat, e.g. there is a Processor key
+// for each processor.  Benchmarkers use this to write the system info in
+// their native structure.
+func (s *SysInfo) KeyValues(detailed bool) [][2]string {
+	if !detailed {
+		kv := [][2]string{
+			{"Processors", strconv.Itoa(s.Cores)},
+			{"Model", s.CPUModel},
+			{"CPU MHz", fmt.Sprintf("%.2f", s.CPUMHz)},
+			{"Cache", s.Cache},
+			{"Memory", human.Bytes(s.MemTotal)},
+		}
+		kv = append(kv, s.containerInfo()...)
+		return append(kv, s.osGoInfo()...)
+	}
+	var kv [][2]string
+	for _, cpu := range s.Processors {
+		kv = append(kv, [][2]string{
+			{"Processor", strconv.Itoa(cpu.ID)},
+			{"Model", cpu.Model},
+			{"CPU MHz", fmt.Sprintf("%.2f", cpu.MHz)},
+			{"Cache", cpu.Cache},
+		}...)
+	}
+	if id := s.cpuIDString(); id != "" {
+		kv = append(kv, [2]string{"CPU ID", id})
+	}
+	if len(s.CPUFeatures) > 0 {
+		kv = append(kv, [2]string{"Features", strings.Join(s.CPUFeatures, " ")})
+	}
+	if sys := s.systemString(); sys != "" {
+		kv = append(kv, [2]string{"System", sys})
+	}
+	if bios := s.biosString(); bios != "" {
+		kv = append(kv, [2]string{"BIOS", bios})
+	}
+	kv = append(kv, [2]string{"Memory", human.Bytes(s.MemTotal)})
+	kv = append(kv, s.containerInfo()...)
+	if len(s.NUMANodes) > 0 {
+		kv = append(kv, [2]string{"NUMA nodes", strconv.Itoa(len(s.NUMANodes))})
+		for _, n := range s.NUMANodes {
+			kv = append(kv, [2]string{fmt.Sprintf("Node %d", n.ID), n.CPUs})
+		}
+	}
+	for i, g := range s.GPUs {
+		kv = append(kv, [2]string{"GPU", strconv.Itoa(i)})
+		kv = append(kv, [2]string{"GPU Model", g.Model})
+		if g.VRAM > 0 {
+			kv = append(kv, [2]string{"VRAM", human.IBytes(g.VRAM)})
+		}
+		if g.Driver != "" {
+			kv = append(kv, [2]string{"Driver", g.Driver})
+		}
+	}
+	for _, d := range s.Disks {
+		disk := fmt.Sprintf("%s %s %s", d.Name, d.Type, human.Bytes(d.Size))
+		if d.Model != "" {
+			disk += " (" + d.Model + ")"
+		}
+		kv = append(kv, [2]string{"Disk", disk})
+	}
+	if s.Filesystem != "" {
+		kv = append(kv, [2]string{"Filesystem", s.Filesystem + " on " + s.FSDevice})
+	}
+	return append(kv, s.osGoInfo()...)
+}
+
+// formatKeyValues returns the key value pairs as a string, one pair per line, with
+// the keys padded to width w.  The string ends with a blank line.
+func formatKeyValues(kv [][2]string, w int) string {
+	var buff bytes.Buffer
+	for _, v := range kv {
+		buff.WriteString(fmt.Sprintf("%-*s%s\n", w, v[0]+":", v[1]))
+	}
+	buff.WriteRune('\n')
+	return buff.String()
+}
+
+// String returns the system information as a formatted string.
+func (s *SysInfo) String() string {
+	return formatKeyValues(s.KeyValues(false), 13)
+}
+
+// DetailedString returns the system information, including information about
+// every processor, as a formatted string.
+func (s *SysInfo) DetailedString() string {
+	return formatKeyValues(s.KeyValues(true), 12)
+}
+
+// MarshalJSON implements json.Marshaler.  Along with the raw values, the
+// memory is included in its human readable form.
+func (s *SysInfo) MarshalJSON() ([]byte, error) {
+	// sysInfo doesn't have SysInfo's methods, so this doesn't recurse.
+	type sysInfo SysInfo
+	return json.Marshal(struct {
+		*sysInfo
+		Memory string `json:"memory"`
+	}{(*sysInfo)(s), human.Bytes(s.MemTotal)})
+}
+
+// sysInfoCache holds the system information gathered by this process.  It
+// is gathered once, on first use, and shared by every Benches; only
+// successful reads are cached.
+var sysInfoCache struct {
+	sync.Mutex
+	s      *SysInfo
+	gpus   []GPU
+	gpuOK  bool
+	disks  []Disk
+	diskOK bool
+}
+
+// cachedSysInfo returns a copy of the process's system information, with the
+// GPUs and disks included if requested.  The copy can be modified without
+// affecting other users of the cache.
+func cachedSysInfo(gpus, disks bool) (*SysInfo, error) {
+	c := &sysInfoCache
+	c.Lock()
+	defer c.Unlock()
+	if c.s == nil {
+		s, err := GetSysInfo()
+		if err != nil {
+			return nil, err
+		}
+		c.s = s
+	}
+	if gpus && !c.gpuOK {
+		g, err := GetGPUs()
+		if err != nil {
+			return nil, err
+		}
+		c.gpus, c.gpuOK = g, true
+	}
+	if disks && !c.diskOK {
+		d, err := GetDisks()
+		if err != nil {
+			return nil, err
+		}
+		c.disks, c.diskOK = d, true
+	}
+	s := c.s.clone()
+	if gpus {
+		s.GPUs = append([]GPU(nil), c.gpus...)
+	}
+	if disks {
+		s.Disks = append([]Disk(nil), c.disks...)
+		s.Filesystem, s.FSDevice = workingFilesystem()
+	}
+	return s, nil
+}
+
+// Info returns the information about the system the benchmarks were run
+// on.  If the Benches' SysInfo isn't set, it is set from the system
+// information, which is gathered once per process; the GPUs are only
+// enumerated if IncludeGPUInfo is true and the disks if IncludeDiskInfo is
+// true.
+func (b *Benches) Info() (*SysInfo, error) {
+	if b.SysInfo != nil {
+		return b.SysInfo, nil
+	}
+	s, err := cachedSysInfo(b.includeGPUInfo, b.includeDiskInfo)
+	if err != nil {
+		return nil, err
+	}
+	b.SysInfo = s
+	return s, nil
+}
+
+// sysInfoKeyValues returns the system info key value pairs that should be
+// included in the output: the detailed info if IncludeDetailedSystemInfo is
+// true, otherwise the basic info if IncludeSystemInfo is true.  If no system
+// info should be included, nil is returned.
+func (b *Benches) sysInfoKeyValues() ([][2]string, error) {
+	if !b.includeDetailedSystemInfo && !b.includeSystemInfo {
+		return nil, nil
+	}
+	s, err := b.Info()
+	if err != nil {
+		return nil, err
+	}
+	return s.KeyValues(b.includeDetailedSystemInfo), nil
+}
+
+// DetailedSystemInfo generates the System Information string, including
+// information about every CPU core on the system.
+func (b *Benches) DetailedSystemInfo() (string, error) {
+	s, err := b.Info()
+	if err != nil {
+		return "", err
+	}
+	return s.DetailedString(), nil
+}
+
+// SystemInfo generates a System Information string.
+func (b *Benches) SystemInfo() (string, error) {
+	s, err := b.Info()
+	if err != nil {
+		return "", err
+	}
+	return s.String(), nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"strconv"
+	"strings"
+
+	human "github.com/dustin/go-humanize"
+	"golang.org/x/sys/unix"
+)
+
+// getSysInfo gets the system information using sysctl.
+func getSysInfo() (SysInfo, error) {
+	var s SysInfo
+	model, err := unix.Sysctl("machdep.cpu.brand_string")
+	if err != nil {
+		return s, err
+	}
+	n, err := unix.SysctlUint32("hw.logicalcpu")
+	if err != nil {
+		return s, err
+	}
+	// hw.cpufrequency isn't available on Apple silicon.
+	var mhz float64
+	hz, err := unix.SysctlUint64("hw.cpufrequency")
+	if err == nil {
+		mhz = float64(hz) / 1e6
+	}
+	var cache string
+	l2, err := unix.SysctlUint64("hw.l2cachesize")
+	if err == nil {
+		cache = human.IBytes(l2)
+	}
+	for i := 0; i < int(n); i++ {
+		s.Processors = append(s.Processors, Processor{ID: i, Model: model, MHz: mhz, Cache: cache})
+	}
+	// the cpuid values are only available on Intel.
+	s.CPUVendor, _ = unix.Sysctl("machdep.cpu.vendor")
+	if v, err := unix.SysctlUint32("machdep.cpu.family"); err == nil {
+		s.CPUFamily = strconv.Itoa(int(v))
+	}
+	if v, err := unix.SysctlUint32("machdep.cpu.model"); err == nil {
+		s.CPUModelNumber = strconv.Itoa(int(v))
+	}
+	if v, err := unix.SysctlUint32("machdep.cpu.stepping"); err == nil {
+		s.CPUStepping = strconv.Itoa(int(v))
+	}
+	// Macs don't have DMI; the model identifier, e.g. MacBookPro18,1, is the
+	// closest equivalent.
+	if m, err := unix.Sysctl("hw.model"); err == nil {
+		s.SystemVendor = "Apple"
+		s.ProductName = m
+	}
+	s.MemTotal, err = unix.SysctlUint64("hw.memsize")
+	if err != nil {
+		return s, err
+	}
+	s.OS = "macOS"
+	v, err := unix.Sysctl("kern.osproductversion")
+	if err == nil {
+		s.OS += " " + strings.TrimSpace(v)
+	}
+	typ, err := unix.Sysctl("kern.ostype")
+	if err != nil {
+		return s, err
+	}
+	rel, err := unix.Sysctl("kern.osrelease")
+	if err != nil {
+		return s, err
+	}
+	s.Kernel = typ + " " + rel
+	return s, nil
+}
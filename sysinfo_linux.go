@@ -0,0 +1,62 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"strings"
+
+	"github.com/mohae/joefriday/cpu/cpuinfo"
+	"github.com/mohae/joefriday/mem/membasic"
+	release "github.com/mohae/joefriday/system/os"
+	"github.com/mohae/joefriday/system/version"
+)
+
+// getSysInfo gets the system information from the proc files.
+func getSysInfo() (SysInfo, error) {
+	var s SysInfo
+	inf, err := cpuinfo.Get()
+	if err != nil {
+		return s, err
+	}
+	v, err := version.Get()
+	if err != nil {
+		return s, err
+	}
+	r, err := release.Get()
+	if err != nil {
+		return s, err
+	}
+	m, err := membasic.Get()
+	if err != nil {
+		return s, err
+	}
+	for _, cpu := range inf.CPU {
+		s.Processors = append(s.Processors, Processor{
+			ID:    int(cpu.Processor),
+			Model: cpu.ModelName,
+			MHz:   float64(cpu.CPUMHz),
+			Cache: cpu.CacheSize,
+		})
+	}
+	if len(inf.CPU) > 0 {
+		s.CPUVendor = inf.CPU[0].VendorID
+		s.CPUFamily = inf.CPU[0].CPUFamily
+		s.CPUModelNumber = inf.CPU[0].Model
+		s.CPUStepping = inf.CPU[0].Stepping
+	}
+	// meminfo is in kB
+	s.MemTotal = m.MemTotal * 1000
+	// release info
+	info := r.PrettyName
+	if info == "" {
+		info = r.Version
+		if info == "" {
+			info = r.VersionID
+		}
+	}
+	s.OS = strings.Title(r.ID) + " " + info
+	s.Kernel = v.Version
+	return s, nil
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+//go:build !linux && !darwin && !windows
+// +build !linux,!darwin,!windows
+
+package benchutil
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// getSysInfo returns an error; system information isn't supported on this
+// platform.
+func getSysInfo() (SysInfo, error) {
+	return SysInfo{}, fmt.Errorf("system info: %s is not supported", runtime.GOOS)
+}
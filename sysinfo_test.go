@@ -0,0 +1,188 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func testSysInfo() *SysInfo {
+	return &SysInfo{
+		CPUModel:       "Test CPU",
+		Cores:          2,
+		CPUMHz:         2400,
+		Cache:          "1024 KB",
+		CPUVendor:      "GenuineIntel",
+		CPUFamily:      "6",
+		CPUModelNumber: "85",
+		CPUStepping:    "4",
+		CPUFeatures:    []string{"avx", "avx2", "avx512f"},
+		MemTotal:       8000000000,
+		OS:             "Test OS 1.0",
+		Kernel:         "4.4.0",
+		Processors: []Processor{
+			{ID: 0, Model: "Test CPU", MHz: 2400, Cache: "1024 KB"},
+			{ID: 1, Model: "Test CPU", MHz: 2400, Cache: "1024 KB"},
+		},
+		GoVersion:  "go1.7",
+		GOOS:       "linux",
+		GOARCH:     "amd64",
+		Compiler:   "gc",
+		GoEnv:      map[string]string{"GOGC": "off", "GOAMD64": "v3"},
+		NumCPU:     4,
+		GOMAXPROCS: 2,
+	}
+}
+
+func TestSysInfoString(t *testing.T) {
+	s := testSysInfo()
+	want := "Processors:  2\nModel:       Test CPU\nCPU MHz:     2400.00\nCache:       1024 KB\nMemory:      8.0 GB\nOS:          Test OS 1.0\nKernel:      4.4.0\nGo:          go1.7 linux/amd64 (gc)\nGo env:      GOAMD64=v3 GOGC=off\nNumCPU:      4\nGOMAXPROCS:  2\n\n"
+	if s.String() != want {
+		t.Errorf("got %q; want %q", s.String(), want)
+	}
+	if n := strings.Count(s.DetailedString(), "Processor:"); n != 2 {
+		t.Errorf("got %d processors in the detailed string; want 2", n)
+	}
+	if !strings.Contains(s.DetailedString(), "CPU ID:     GenuineIntel family 6 model 85 stepping 4\nFeatures:   avx avx2 avx512f\n") {
+		t.Errorf("got %q; want the CPU ID and features", s.DetailedString())
+	}
+}
+
+func TestSysInfoJSON(t *testing.T) {
+	b, err := json.Marshal(testSysInfo())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var m map[string]interface{}
+	err = json.Unmarshal(b, &m)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if m["cpu_model"] != "Test CPU" {
+		t.Errorf("got cpu_model %v; want Test CPU", m["cpu_model"])
+	}
+	if m["memory"] != "8.0 GB" {
+		t.Errorf("got memory %v; want 8.0 GB", m["memory"])
+	}
+}
+
+func TestBenchesInfo(t *testing.T) {
+	b := Benches{SysInfo: testSysInfo()}
+	s, err := b.SystemInfo()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(s, "Test OS 1.0") {
+		t.Errorf("got %q; want the attached system info to be used", s)
+	}
+}
+
+func TestSetGoInfo(t *testing.T) {
+	orig, ok := os.LookupEnv("GOGC")
+	os.Setenv("GOGC", "200")
+	defer func() {
+		if ok {
+			os.Setenv("GOGC", orig)
+			return
+		}
+		os.Unsetenv("GOGC")
+	}()
+	var s SysInfo
+	s.setGoInfo()
+	if s.GoVersion != runtime.Version() {
+		t.Errorf("got %q; want %q", s.GoVersion, runtime.Version())
+	}
+	if s.GOMAXPROCS != runtime.GOMAXPROCS(0) {
+		t.Errorf("got GOMAXPROCS %d; want %d", s.GOMAXPROCS, runtime.GOMAXPROCS(0))
+	}
+	if s.GoEnv["GOGC"] != "200" {
+		t.Errorf("got GOGC %q; want 200", s.GoEnv["GOGC"])
+	}
+}
+
+func TestSysInfoSections(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewMDBench(&buf)
+	m.SysInfo = testSysInfo()
+	m.IncludeSystemInfo(true)
+	m.Append(testBenches()...)
+	err := m.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(buf.String(), "Processors\n: 2\nModel\n: Test CPU\n") {
+		t.Errorf("got %q; want the system info as a definition list", buf.String())
+	}
+	buf.Reset()
+	c := NewCSVBench(&buf, WithCSVPreamble())
+	c.SysInfo = testSysInfo()
+	c.IncludeSystemInfo(true)
+	c.Append(testBenches()...)
+	err = c.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(buf.String(), "Z\nProcessors,2\nModel,Test CPU\n") {
+		t.Errorf("got %q; want the system info as key, value records", buf.String())
+	}
+	if !strings.Contains(buf.String(), "GOMAXPROCS,2\n\nGroup,") {
+		t.Errorf("got %q; want an empty record between the system info and the header", buf.String())
+	}
+}
+
+func TestInfoCached(t *testing.T) {
+	var a, b Benches
+	sa, err := a.Info()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	sb, err := b.Info()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if sa == sb {
+		t.Error("got the same SysInfo for both Benches; want copies")
+	}
+	if sa.OS != sb.OS || sa.Cores != sb.Cores {
+		t.Errorf("got %+v and %+v; want the same system info", sa, sb)
+	}
+	sa.Thermal = &Thermal{}
+	if sysInfoCache.s.Thermal != nil {
+		t.Error("modifying a Benches' SysInfo modified the cache")
+	}
+
+	// the slices and maps are copied too.
+	sysInfoCache.Lock()
+	orig := sysInfoCache.s
+	sysInfoCache.s = &SysInfo{
+		Processors:  []Processor{{Model: "Test CPU"}},
+		CPUFeatures: []string{"avx2"},
+		NUMANodes:   []NUMANode{{CPUs: "0-7"}},
+		GoEnv:       map[string]string{"GOGC": "100"},
+	}
+	sysInfoCache.Unlock()
+	defer func() {
+		sysInfoCache.Lock()
+		sysInfoCache.s = orig
+		sysInfoCache.Unlock()
+	}()
+	s, err := cachedSysInfo(false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	s.Processors[0].Model = "x"
+	s.CPUFeatures[0] = "x"
+	s.NUMANodes[0].CPUs = "x"
+	s.GoEnv["GOGC"] = "x"
+	c := sysInfoCache.s
+	if c.Processors[0].Model != "Test CPU" || c.CPUFeatures[0] != "avx2" || c.NUMANodes[0].CPUs != "0-7" || c.GoEnv["GOGC"] != "100" {
+		t.Errorf("got %+v; modifying a copy modified the cache", c)
+	}
+}
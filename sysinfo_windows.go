@@ -0,0 +1,97 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"runtime"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+var procGlobalMemoryStatusEx = windows.NewLazySystemDLL("kernel32.dll").NewProc("GlobalMemoryStatusEx")
+
+// memoryStatusEx is the Win32 MEMORYSTATUSEX struct.
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+// getSysInfo gets the system information from the registry and the Win32
+// API.
+func getSysInfo() (SysInfo, error) {
+	var s SysInfo
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `HARDWARE\DESCRIPTION\System\CentralProcessor\0`, registry.QUERY_VALUE)
+	if err != nil {
+		return s, err
+	}
+	defer k.Close()
+	model, _, err := k.GetStringValue("ProcessorNameString")
+	if err != nil {
+		return s, err
+	}
+	mhz, _, err := k.GetIntegerValue("~MHz")
+	if err != nil {
+		return s, err
+	}
+	for i := 0; i < runtime.NumCPU(); i++ {
+		s.Processors = append(s.Processors, Processor{ID: i, Model: strings.TrimSpace(model), MHz: float64(mhz)})
+	}
+	s.CPUVendor, _, _ = k.GetStringValue("VendorIdentifier")
+	// the identifier is in the form "Intel64 Family 6 Model 85 Stepping 4".
+	id, _, err := k.GetStringValue("Identifier")
+	if err == nil {
+		f := strings.Fields(id)
+		for i := 1; i+1 < len(f); i += 2 {
+			switch f[i] {
+			case "Family":
+				s.CPUFamily = f[i+1]
+			case "Model":
+				s.CPUModelNumber = f[i+1]
+			case "Stepping":
+				s.CPUStepping = f[i+1]
+			}
+		}
+	}
+	// the SMBIOS info; it's optional.
+	if b, err := registry.OpenKey(registry.LOCAL_MACHINE, `HARDWARE\DESCRIPTION\System\BIOS`, registry.QUERY_VALUE); err == nil {
+		s.SystemVendor, _, _ = b.GetStringValue("SystemManufacturer")
+		s.ProductName, _, _ = b.GetStringValue("SystemProductName")
+		s.BIOSVendor, _, _ = b.GetStringValue("BIOSVendor")
+		s.BIOSVersion, _, _ = b.GetStringValue("BIOSVersion")
+		s.BIOSDate, _, _ = b.GetStringValue("BIOSReleaseDate")
+		b.Close()
+	}
+	var m memoryStatusEx
+	m.Length = uint32(unsafe.Sizeof(m))
+	ok, _, err := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&m)))
+	if ok == 0 {
+		return s, err
+	}
+	s.MemTotal = m.TotalPhys
+	v, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Windows NT\CurrentVersion`, registry.QUERY_VALUE)
+	if err != nil {
+		return s, err
+	}
+	defer v.Close()
+	s.OS, _, err = v.GetStringValue("ProductName")
+	if err != nil {
+		return s, err
+	}
+	build, _, err := v.GetStringValue("CurrentBuild")
+	if err == nil {
+		s.Kernel = "Windows NT build " + build
+	}
+	return s, nil
+}
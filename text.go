@@ -0,0 +1,168 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"math"
+	"strings"
+)
+
+// loremWords is the lorem ipsum vocabulary the text is generated from.
+var loremWords = strings.Fields(`a ac accumsan ad adipiscing aenean aliquam aliquet amet ante
+aptent arcu at auctor augue bibendum blandit commodo condimentum congue consectetur
+consequat conubia convallis cras cubilia curabitur curae cursus dapibus diam dictum
+dictumst dignissim dis dolor donec dui duis egestas eget eleifend elementum elit enim
+erat eros est et etiam eu euismod facilisi facilisis fames faucibus felis fermentum
+feugiat fringilla fusce gravida habitant habitasse hac hendrerit himenaeos iaculis id
+imperdiet in inceptos integer interdum ipsum justo lacinia lacus laoreet lectus leo
+libero ligula litora lobortis lorem luctus maecenas magna magnis malesuada massa mattis
+mauris metus mi molestie mollis montes morbi mus nam nascetur natoque nec neque netus
+nibh nisi nisl non nostra nulla nullam nunc odio orci ornare parturient pellentesque
+penatibus per pharetra phasellus placerat platea porta porttitor posuere potenti
+praesent pretium primis proin pulvinar purus quam quis quisque rhoncus ridiculus risus
+rutrum sagittis sapien scelerisque sed sem semper senectus sit sociis sociosqu
+sodales sollicitudin suscipit suspendisse taciti tellus tempor tempus tincidunt
+torquent tortor tristique turpis ullamcorper ultrices ultricies urna ut varius vehicula
+vel velit venenatis vestibulum vitae vivamus viverra volutpat vulputate`)
+
+// loremByLen is loremWords grouped by length.
+var loremByLen = func() map[int][]string {
+	m := map[int][]string{}
+	for _, w := range loremWords {
+		m[len(w)] = append(m[len(w)], w)
+	}
+	return m
+}()
+
+// Text generates lorem ipsum words, sentences, and paragraphs.  The word
+// lengths follow a normal distribution, clamped to [1, MaxWordLen]; this
+// controls the shape of the text, which matters for text processing and
+// search benchmarks.  Words of a length that isn't in the vocabulary are
+// made of random lower case letters.  A Text is not safe for concurrent use.
+type Text struct {
+	g             *Gen
+	MeanWordLen   float64 // the mean word length.
+	StdDevWordLen float64 // the standard deviation of the word lengths; 0 makes every word MeanWordLen long.
+	MaxWordLen    int     // the maximum word length.
+}
+
+// The default word length distribution; it approximates the lorem ipsum
+// vocabulary's.
+const (
+	defaultMeanWordLen   = 6
+	defaultStdDevWordLen = 2.5
+	defaultMaxWordLen    = 15
+)
+
+// NewText returns a Text, using g, whose word lengths have the mean and
+// standard deviation and are at most max long.
+func (g *Gen) NewText(mean, stddev float64, max int) *Text {
+	return &Text{g: g, MeanWordLen: mean, StdDevWordLen: stddev, MaxWordLen: max}
+}
+
+// loremText returns the Text, using g, with the default word length
+// distribution; it's created on first use.
+func (g *Gen) loremText() *Text {
+	if g.text == nil {
+		g.text = g.NewText(defaultMeanWordLen, defaultStdDevWordLen, defaultMaxWordLen)
+	}
+	return g.text
+}
+
+// RandLoremWord returns a pseudo-random lorem ipsum word.  See the
+// RandLoremWord function.
+func (g *Gen) RandLoremWord() string {
+	return g.loremText().Word()
+}
+
+// RandLoremSentence returns a pseudo-random lorem ipsum sentence of n
+// words.  See the RandLoremSentence function.
+func (g *Gen) RandLoremSentence(n int) string {
+	return g.loremText().Sentence(n)
+}
+
+// RandLoremParagraph returns a pseudo-random lorem ipsum paragraph of n
+// sentences.  See the RandLoremParagraph function.
+func (g *Gen) RandLoremParagraph(n int) string {
+	return g.loremText().Paragraph(n)
+}
+
+// wordLen returns the length of the next word.
+func (t *Text) wordLen() int {
+	n := int(math.Floor(t.g.RandNormal(t.MeanWordLen, t.StdDevWordLen) + 0.5))
+	if n < 1 {
+		n = 1
+	}
+	if t.MaxWordLen > 0 && n > t.MaxWordLen {
+		n = t.MaxWordLen
+	}
+	return n
+}
+
+// Word returns a pseudo-random word.
+func (t *Text) Word() string {
+	n := t.wordLen()
+	if w := loremByLen[n]; len(w) > 0 {
+		return t.g.pick(w)
+	}
+	return t.g.RandStringFrom(CharsetLower, uint32(n))
+}
+
+// Sentence returns a pseudo-random sentence of n words, starting with a
+// capital letter and ending with a period.  If n is <= 0, the number of
+// words is pseudo-random, between 4 and 16.
+func (t *Text) Sentence(n int) string {
+	if n <= 0 {
+		n = 4 + int(t.g.rng.Bound(13))
+	}
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		w := t.Word()
+		if i == 0 {
+			w = strings.ToUpper(w[:1]) + w[1:]
+		} else {
+			b.WriteByte(' ')
+			// an occasional comma, for more realistic punctuation.
+			if i < n-1 && t.g.rng.Bound(8) == 0 {
+				w += ","
+			}
+		}
+		b.WriteString(w)
+	}
+	b.WriteByte('.')
+	return b.String()
+}
+
+// Paragraph returns a pseudo-random paragraph of n sentences.  If n is <= 0,
+// the number of sentences is pseudo-random, between 3 and 7.
+func (t *Text) Paragraph(n int) string {
+	if n <= 0 {
+		n = 3 + int(t.g.rng.Bound(5))
+	}
+	s := make([]string, n)
+	for i := range s {
+		s[i] = t.Sentence(0)
+	}
+	return strings.Join(s, " ")
+}
+
+// RandLoremWord returns a pseudo-random lorem ipsum word.
+func RandLoremWord() string {
+	return defaultGen.RandLoremWord()
+}
+
+// RandLoremSentence returns a pseudo-random lorem ipsum sentence of n words,
+// starting with a capital letter and ending with a period.  If n is <= 0,
+// the number of words is pseudo-random, between 4 and 16.
+func RandLoremSentence(n int) string {
+	return defaultGen.RandLoremSentence(n)
+}
+
+// RandLoremParagraph returns a pseudo-random lorem ipsum paragraph of n
+// sentences.  If n is <= 0, the number of sentences is pseudo-random,
+// between 3 and 7.
+func RandLoremParagraph(n int) string {
+	return defaultGen.RandLoremParagraph(n)
+}
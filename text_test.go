@@ -0,0 +1,53 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		s := RandLoremSentence(0)
+		if n := len(strings.Fields(s)); n < 4 || n > 16 {
+			t.Errorf("got sentence %q with %d words; want 4-16", s, n)
+		}
+		if !strings.HasSuffix(s, ".") || strings.ToUpper(s[:1]) != s[:1] {
+			t.Errorf("got sentence %q; want it capitalized and ending with a period", s)
+		}
+	}
+	if n := len(strings.Fields(RandLoremSentence(7))); n != 7 {
+		t.Errorf("got %d words; want 7", n)
+	}
+	if n := strings.Count(RandLoremParagraph(5), "."); n != 5 {
+		t.Errorf("got %d sentences; want 5", n)
+	}
+	g := NewGen(3)
+	if w := g.RandLoremWord(); !strings.Contains(strings.Join(loremWords, " "), w) {
+		t.Errorf("got %q; want a lorem ipsum word", w)
+	}
+	if n := len(strings.Fields(g.RandLoremSentence(6))); n != 6 {
+		t.Errorf("got %d words; want 6", n)
+	}
+	if n := strings.Count(g.RandLoremParagraph(2), "."); n != 2 {
+		t.Errorf("got %d sentences; want 2", n)
+	}
+	// the word lengths should follow the distribution.
+	txt := NewGen(10).NewText(4, 1, 8)
+	var sum float64
+	const n = 10000
+	for i := 0; i < n; i++ {
+		w := txt.Word()
+		if len(w) < 1 || len(w) > 8 {
+			t.Fatalf("got %q; want 1-8 characters", w)
+		}
+		sum += float64(len(w))
+	}
+	if mean := sum / n; math.Abs(mean-4) > 0.1 {
+		t.Errorf("got a mean word length of %.2f; want about 4", mean)
+	}
+}
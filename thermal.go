@@ -0,0 +1,127 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+)
+
+// ThrottleTemp is the CPU temperature, in °C, at or above which a run is
+// considered to have likely throttled when the CPU's throttle counters
+// aren't available.
+var ThrottleTemp = 90.0
+
+// pkgThermalZones are the thermal zone types that report the CPU package
+// temperature.
+var pkgThermalZones = []string{"x86_pkg_temp", "cpu-thermal", "cpu_thermal", "soc_thermal"}
+
+// Thermal holds the CPU temperature at the start and end of a run.
+type Thermal struct {
+	StartTemp float64 `json:"start_temp"`          // the CPU package temperature, in °C, when the run started.
+	EndTemp   float64 `json:"end_temp"`            // the CPU package temperature, in °C, when the run ended.
+	Throttles uint64  `json:"throttles,omitempty"` // the number of thermal throttle events during the run; only counted if the CPU exposes them.
+	Throttled bool    `json:"throttled"`           // whether or not the CPU likely throttled during the run.
+}
+
+// String returns the temperatures, e.g. "45.0°C start, 78.0°C end", with
+// "(throttled)" appended if the CPU likely throttled.
+func (t *Thermal) String() string {
+	s := fmt.Sprintf("%.1f°C start, %.1f°C end", t.StartTemp, t.EndTemp)
+	if t.Throttled {
+		s += " (throttled)"
+	}
+	return s
+}
+
+// thermalSample is a CPU temperature and throttle count reading.
+type thermalSample struct {
+	temp        float64
+	tempOK      bool
+	throttles   uint64
+	throttlesOK bool
+}
+
+func sampleThermal() thermalSample {
+	var s thermalSample
+	s.temp, s.tempOK = CPUPackageTemp()
+	s.throttles, s.throttlesOK = throttleCount()
+	return s
+}
+
+// thermal returns the Thermal info from the samples taken at the start and
+// end of a run.  If the temperature couldn't be read, nil is returned.
+func thermal(start, end thermalSample) *Thermal {
+	if !start.tempOK || !end.tempOK {
+		return nil
+	}
+	t := &Thermal{StartTemp: start.temp, EndTemp: end.temp}
+	if start.throttlesOK && end.throttlesOK {
+		if end.throttles > start.throttles {
+			t.Throttles = end.throttles - start.throttles
+		}
+		t.Throttled = t.Throttles > 0
+		return t
+	}
+	t.Throttled = t.EndTemp >= ThrottleTemp || t.StartTemp >= ThrottleTemp
+	return t
+}
+
+// CPUPackageTemp returns the CPU package temperature, in °C.  If none of the
+// thermal zones are for the CPU package, the highest temperature reported by
+// any thermal zone is returned.  If no temperature could be read, false is
+// returned.
+func CPUPackageTemp() (float64, bool) {
+	zones, _ := filepath.Glob(filepath.Join(sysfs, "class/thermal/thermal_zone*"))
+	for _, z := range zones {
+		rel, err := filepath.Rel(sysfs, z)
+		if err != nil {
+			continue
+		}
+		typ, _ := readSysfsValue(filepath.Join(rel, "type"))
+		for _, pkg := range pkgThermalZones {
+			if typ != pkg {
+				continue
+			}
+			v, ok := readSysfsValue(filepath.Join(rel, "temp"))
+			if !ok {
+				continue
+			}
+			// the temperature is in millidegrees Celsius.
+			t, err := strconv.ParseFloat(v, 64)
+			if err == nil {
+				return t / 1000, true
+			}
+		}
+	}
+	return CPUTemp()
+}
+
+// throttleCount returns the total number of package thermal throttle events
+// across all CPUs.  If the CPUs don't expose throttle counters, false is
+// returned.
+func throttleCount() (uint64, bool) {
+	paths, _ := filepath.Glob(filepath.Join(sysfs, "devices/system/cpu/cpu[0-9]*/thermal_throttle/package_throttle_count"))
+	var n uint64
+	var ok bool
+	for _, p := range paths {
+		rel, err := filepath.Rel(sysfs, p)
+		if err != nil {
+			continue
+		}
+		v, found := readSysfsValue(rel)
+		if !found {
+			continue
+		}
+		c, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			continue
+		}
+		n += c
+		ok = true
+	}
+	return n, ok
+}
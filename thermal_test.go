@@ -0,0 +1,49 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "testing"
+
+func TestCPUPackageTemp(t *testing.T) {
+	restore := fakeSysfs(t, map[string]string{
+		"class/thermal/thermal_zone0/type": "acpitz\n",
+		"class/thermal/thermal_zone0/temp": "70000\n",
+		"class/thermal/thermal_zone1/type": "x86_pkg_temp\n",
+		"class/thermal/thermal_zone1/temp": "52000\n",
+	})
+	defer restore()
+	v, ok := CPUPackageTemp()
+	if !ok {
+		t.Fatal("got no temperature; want one")
+	}
+	if v != 52 {
+		t.Errorf("got %v; want 52", v)
+	}
+}
+
+func TestThermal(t *testing.T) {
+	tests := []struct {
+		start, end thermalSample
+		throttled  bool
+		throttles  uint64
+	}{
+		{thermalSample{temp: 40, tempOK: true}, thermalSample{temp: 70, tempOK: true}, false, 0},
+		{thermalSample{temp: 40, tempOK: true}, thermalSample{temp: 95, tempOK: true}, true, 0},
+		{thermalSample{temp: 40, tempOK: true, throttles: 3, throttlesOK: true}, thermalSample{temp: 95, tempOK: true, throttles: 3, throttlesOK: true}, false, 0},
+		{thermalSample{temp: 40, tempOK: true, throttles: 3, throttlesOK: true}, thermalSample{temp: 80, tempOK: true, throttles: 5, throttlesOK: true}, true, 2},
+	}
+	for i, test := range tests {
+		th := thermal(test.start, test.end)
+		if th.Throttled != test.throttled {
+			t.Errorf("%d: got throttled %t; want %t", i, th.Throttled, test.throttled)
+		}
+		if th.Throttles != test.throttles {
+			t.Errorf("%d: got %d throttles; want %d", i, th.Throttles, test.throttles)
+		}
+	}
+	if th := thermal(thermalSample{}, thermalSample{temp: 50, tempOK: true}); th != nil {
+		t.Errorf("got %+v; want nil", th)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package upload
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// HTTPBucket is a Bucket that PUTs each object to its key under a URL, e.g.
+// https://storage.googleapis.com/bench-reports with an Authorization
+// header for GCS.
+type HTTPBucket struct {
+	URL    string       // the bucket's URL; the key is appended to it.
+	Header http.Header  // headers added to each request, e.g. Authorization; optional.
+	Client *http.Client // the client used; default is http.DefaultClient.
+}
+
+// Put implements Bucket.  A response status other than 2xx is an error.
+func (h *HTTPBucket) Put(key, contentType string, body io.Reader) error {
+	url := strings.TrimSuffix(h.URL, "/") + "/" + strings.TrimPrefix(key, "/")
+	req, err := http.NewRequest("PUT", url, body)
+	if err != nil {
+		return err
+	}
+	for k, v := range h.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", contentType)
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("upload: put %s: %s: %s", url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
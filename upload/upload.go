@@ -0,0 +1,196 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+// Package upload writes benchmark reports to object storage, e.g. S3 or
+// GCS, so they can be archived by CI.
+//
+// The storage is accessed through a Bucket.  HTTPBucket PUTs objects to a
+// URL, which works with the GCS XML API and S3 compatible stores that
+// accept bearer tokens or are otherwise authorized.  For anything else,
+// e.g. S3 with SigV4 signing, adapt the provider's client with a
+// BucketFunc:
+//
+//	bucket := upload.BucketFunc(func(key, contentType string, body io.Reader) error {
+//		_, err := client.PutObject(ctx, &s3.PutObjectInput{
+//			Bucket:      aws.String("bench-reports"),
+//			Key:         aws.String(key),
+//			ContentType: aws.String(contentType),
+//			Body:        body,
+//		})
+//		return err
+//	})
+package upload
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"text/template"
+	"time"
+
+	"github.com/mohae/benchutil"
+)
+
+// DefaultKey is the key template used when one isn't specified, e.g.
+// "2016-05-01/3f7a2c1/json.csv".
+const DefaultKey = "{{.Date}}/{{.Commit}}/{{.Name}}{{.Ext}}"
+
+// Bucket is an object storage bucket.
+type Bucket interface {
+	// Put writes the body to the object with the key.
+	Put(key, contentType string, body io.Reader) error
+}
+
+// BucketFunc adapts a func to a Bucket.
+type BucketFunc func(key, contentType string, body io.Reader) error
+
+// Put implements Bucket; it calls f.
+func (f BucketFunc) Put(key, contentType string, body io.Reader) error {
+	return f(key, contentType, body)
+}
+
+// Format is the format a report is uploaded in: the name of a registered
+// benchutil format, e.g. "html" or one added with benchutil.RegisterFormat,
+// or JSON.
+type Format string
+
+const (
+	Text     Format = "txt"  // the StringBench output.
+	CSV      Format = "csv"  // the CSVBench output.
+	Markdown Format = "md"   // the MDBench output.
+	HTML     Format = "html" // the HTMLBench output.
+	JSON     Format = "json" // the Benches as JSON.
+)
+
+// Ext returns the format's file extension, its name with a leading dot,
+// e.g. ".csv".
+func (f Format) Ext() string {
+	return "." + string(f)
+}
+
+// ContentType returns the format's content type.  The content type of a
+// format that isn't known is looked up by its extension; if there isn't
+// one, it's application/octet-stream.
+func (f Format) ContentType() string {
+	switch f {
+	case Text:
+		return "text/plain; charset=utf-8"
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case Markdown:
+		return "text/markdown; charset=utf-8"
+	case HTML:
+		return "text/html; charset=utf-8"
+	case JSON:
+		return "application/json"
+	}
+	if t := mime.TypeByExtension(f.Ext()); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// KeyData is the data the key template is executed with.
+type KeyData struct {
+	Name      string    // the Benches' Name; "benchmarks" if it isn't set.
+	Hostname  string    // the host the benchmarks were run on.
+	Commit    string    // the commit that was benchmarked; "unknown" if it wasn't captured.
+	Branch    string    // the branch that was benchmarked; if it was captured.
+	Date      string    // the date the benchmarks were run, e.g. 2016-05-01.
+	Timestamp time.Time // when the benchmarks were run.
+	Ext       string    // the format's extension, e.g. ".csv".
+}
+
+// Uploader uploads reports to a bucket.
+type Uploader struct {
+	bucket Bucket
+	key    *template.Template
+}
+
+// New returns an Uploader that uploads to the bucket using the key
+// template; see KeyData for the template's fields.  If key is empty,
+// DefaultKey is used.
+func New(bucket Bucket, key string) (*Uploader, error) {
+	if key == "" {
+		key = DefaultKey
+	}
+	tmpl, err := template.New("key").Option("missingkey=error").Parse(key)
+	if err != nil {
+		return nil, fmt.Errorf("upload: key template: %s", err)
+	}
+	return &Uploader{bucket: bucket, key: tmpl}, nil
+}
+
+// Upload writes the benches to the bucket in the format and returns the key
+// they were written to.  The formats, other than JSON, use the benches'
+// settings, e.g. its column headers and whether or not system info is
+// included.
+func (u *Uploader) Upload(b *benchutil.Benches, f Format) (string, error) {
+	key, err := u.Key(b, f)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = Write(&buf, b, f)
+	if err != nil {
+		return "", err
+	}
+	err = u.bucket.Put(key, f.ContentType(), &buf)
+	if err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// Key returns the key the benches would be uploaded to in the format.
+func (u *Uploader) Key(b *benchutil.Benches, f Format) (string, error) {
+	d := KeyData{
+		Name:      b.Name,
+		Hostname:  b.Hostname,
+		Commit:    "unknown",
+		Date:      b.Timestamp.Format("2006-01-02"),
+		Timestamp: b.Timestamp,
+		Ext:       f.Ext(),
+	}
+	if d.Name == "" {
+		d.Name = "benchmarks"
+	}
+	if b.Git != nil && b.Git.Commit != "" {
+		d.Commit = b.Git.Commit
+		d.Branch = b.Git.Branch
+	}
+	var buf bytes.Buffer
+	err := u.key.Execute(&buf, d)
+	if err != nil {
+		return "", fmt.Errorf("upload: key template: %s", err)
+	}
+	return buf.String(), nil
+}
+
+// Write writes the benches to w in the format.  The formats, other than
+// JSON, are written using the registered benchutil format with the benches'
+// settings; see benchutil.Options.
+func Write(w io.Writer, b *benchutil.Benches, f Format) error {
+	if f == JSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(b)
+	}
+	bm, err := benchutil.NewFormat(string(f), w)
+	if err != nil {
+		return fmt.Errorf("upload: %s", err)
+	}
+	o := b.Options()
+	err = o.Apply(bm)
+	if err != nil {
+		return fmt.Errorf("upload: %s", err)
+	}
+	err = benchutil.SetBenches(bm, b)
+	if err != nil {
+		return fmt.Errorf("upload: %s", err)
+	}
+	return bm.Out()
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package upload
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mohae/benchutil"
+)
+
+func testBenches() *benchutil.Benches {
+	c := benchutil.NewCSVBench(ioutil.Discard)
+	c.Name = "json"
+	c.Hostname = "test"
+	c.Timestamp = time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	c.Git = &benchutil.GitInfo{Commit: "3f7a2c1", Branch: "master"}
+	c.Append(benchutil.Bench{Group: "json", Name: "decode", Iterations: 1, Result: benchutil.Result{Ops: 10, NsOp: 100}})
+	return &c.Benches
+}
+
+func TestUpload(t *testing.T) {
+	type object struct{ key, contentType, body string }
+	var got []object
+	bucket := BucketFunc(func(key, contentType string, body io.Reader) error {
+		b, err := ioutil.ReadAll(body)
+		got = append(got, object{key, contentType, string(b)})
+		return err
+	})
+	u, err := New(bucket, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := testBenches()
+	for _, f := range []Format{Text, CSV, Markdown, JSON, HTML} {
+		key, err := u.Upload(b, f)
+		if err != nil {
+			t.Fatalf("%s: %s", f.Ext(), err)
+		}
+		if want := "2016-05-01/3f7a2c1/json" + f.Ext(); key != want {
+			t.Errorf("got key %q; want %q", key, want)
+		}
+	}
+	if len(got) != 5 {
+		t.Fatalf("got %d objects; want 5", len(got))
+	}
+	if got[1].contentType != "text/csv; charset=utf-8" || !strings.Contains(got[1].body, "json,decode") {
+		t.Errorf("got %+v; want the CSV output", got[1])
+	}
+	if !strings.Contains(got[2].body, "|") {
+		t.Errorf("got %q; want the markdown output", got[2].body)
+	}
+	var dec benchutil.Benches
+	err = json.Unmarshal([]byte(got[3].body), &dec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dec.Benchmarks) != 1 || dec.Benchmarks[0].NsOp != 100 {
+		t.Errorf("got %+v; want the benches", dec)
+	}
+	if got[4].contentType != "text/html; charset=utf-8" || !strings.Contains(got[4].body, "<td>decode</td>") {
+		t.Errorf("got %+v; want the HTML output", got[4])
+	}
+	_, err = u.Upload(b, Format("nope"))
+	if err == nil || !strings.Contains(err.Error(), "unknown format") {
+		t.Errorf("got %v; want an unknown format error", err)
+	}
+}
+
+func TestWriteSettings(t *testing.T) {
+	b := testBenches()
+	b.SetNameColumnHeader("Benchmark")
+	var buf strings.Builder
+	err := Write(&buf, b, Text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Benchmark") {
+		t.Errorf("got %q; want the benches' column header", buf.String())
+	}
+}
+
+func TestKey(t *testing.T) {
+	u, err := New(BucketFunc(nil), "{{.Branch}}/{{.Timestamp.Unix}}-{{.Hostname}}{{.Ext}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := u.Key(testBenches(), JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "master/1462104000-test.json" {
+		t.Errorf("got %q", key)
+	}
+	u, _ = New(nil, "")
+	key, err = u.Key(&benchutil.Benches{Timestamp: time.Date(2016, 5, 1, 0, 0, 0, 0, time.UTC)}, CSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "2016-05-01/unknown/benchmarks.csv" {
+		t.Errorf("got %q", key)
+	}
+	_, err = New(nil, "{{.Nope")
+	if err == nil {
+		t.Error("got no error for a bad template")
+	}
+	u, _ = New(nil, "{{.Nope}}")
+	_, err = u.Key(testBenches(), CSV)
+	if err == nil {
+		t.Error("got no error for an unknown field")
+	}
+}
+
+func TestHTTPBucket(t *testing.T) {
+	var method, path, ct, auth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, ct, auth = r.Method, r.URL.Path, r.Header.Get("Content-Type"), r.Header.Get("Authorization")
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		if strings.Contains(path, "denied") {
+			http.Error(w, "access denied", http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+	h := &HTTPBucket{URL: srv.URL + "/reports/", Header: http.Header{"Authorization": {"Bearer token"}}}
+	err := h.Put("2016/a.csv", "text/csv", strings.NewReader("a,b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "PUT" || path != "/reports/2016/a.csv" || ct != "text/csv" || auth != "Bearer token" || body != "a,b" {
+		t.Errorf("got %s %s %q %q %q", method, path, ct, auth, body)
+	}
+	err = h.Put("denied", "text/csv", strings.NewReader(""))
+	if err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Errorf("got %v; want the access denied error", err)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "fmt"
+
+// IssueKind is the kind of problem an Issue is.
+type IssueKind int
+
+const (
+	EmptyName              IssueKind = iota // the bench has no Name.
+	DuplicateID                             // another bench has the same ID.
+	ZeroResult                              // the bench didn't fail but has no ops or time.
+	InvalidIterations                       // the bench's Iterations is less than 1.
+	InconsistentIterations                  // the bench's Iterations differs from the first bench's.
+)
+
+func (k IssueKind) String() string {
+	switch k {
+	case EmptyName:
+		return "empty name"
+	case DuplicateID:
+		return "duplicate id"
+	case ZeroResult:
+		return "zero result"
+	case InvalidIterations:
+		return "invalid iterations"
+	case InconsistentIterations:
+		return "inconsistent iterations"
+	}
+	return fmt.Sprintf("IssueKind(%d)", int(k))
+}
+
+// Issue is a problem with a bench in a set.
+type Issue struct {
+	Index int       // the bench's index in Benchmarks.
+	ID    string    // the bench's ID.
+	Kind  IssueKind // the kind of problem.
+	Msg   string    // a description of the problem.
+}
+
+// String returns the issue as a string, e.g.
+// "2: json/decode: duplicate id: same id as 0".
+func (i Issue) String() string {
+	return fmt.Sprintf("%d: %s: %s: %s", i.Index, i.ID, i.Kind, i.Msg)
+}
+
+// Validate checks the benches for problems that would make the output
+// confusing: benches without a Name, benches with the same ID, benches that
+// didn't fail but have no results, and Iterations that are less than 1 or
+// differ from the first bench's.  The issues are returned in the order of
+// the benches; nil means there weren't any.
+func (b *Benches) Validate() []Issue {
+	var issues []Issue
+	ids := make(map[string]int, len(b.Benchmarks))
+	for i, v := range b.Benchmarks {
+		id := v.ID()
+		if v.Name == "" {
+			issues = append(issues, Issue{i, id, EmptyName, "the bench has no name"})
+		}
+		if j, ok := ids[id]; ok {
+			issues = append(issues, Issue{i, id, DuplicateID, fmt.Sprintf("same id as %d", j)})
+		} else {
+			ids[id] = i
+		}
+		if !v.Failed() && v.Ops == 0 && v.NsOp == 0 {
+			issues = append(issues, Issue{i, id, ZeroResult, "the bench has no ops or time"})
+		}
+		if v.Iterations < 1 {
+			issues = append(issues, Issue{i, id, InvalidIterations, fmt.Sprintf("%d iterations", v.Iterations)})
+			continue
+		}
+		if first := b.Benchmarks[0].Iterations; i > 0 && first > 0 && v.Iterations != first {
+			issues = append(issues, Issue{i, id, InconsistentIterations, fmt.Sprintf("%d iterations; the first bench has %d", v.Iterations, first)})
+		}
+	}
+	return issues
+}
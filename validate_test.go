@@ -0,0 +1,42 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	b := NewBenches()
+	b.Append(testBenches()...)
+	if issues := b.Validate(); issues != nil {
+		t.Errorf("got %v; want no issues", issues)
+	}
+	b.Append(
+		Bench{Group: "group", Name: "a", Iterations: 1, Result: Result{Ops: 1, NsOp: 1}},
+		Bench{Group: "group", Iterations: 1, Result: Result{Ops: 1, NsOp: 1}},
+		Bench{Name: "zero", Iterations: 1},
+		Bench{Name: "failed", Iterations: 1, Err: "boom"},
+		Bench{Name: "none", Result: Result{Ops: 1, NsOp: 1}},
+		Bench{Name: "more", Iterations: 2, Result: Result{Ops: 1, NsOp: 1}},
+	)
+	want := []Issue{
+		{3, "group/a", DuplicateID, "same id as 0"},
+		{4, "group", EmptyName, "the bench has no name"},
+		{5, "zero", ZeroResult, "the bench has no ops or time"},
+		{7, "none", InvalidIterations, "0 iterations"},
+		{8, "more", InconsistentIterations, "2 iterations; the first bench has 1"},
+	}
+	got := b.Validate()
+	if len(got) != len(want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%d: got %v; want %v", i, got[i], want[i])
+		}
+	}
+	if s := want[0].String(); s != "3: group/a: duplicate id: same id as 0" {
+		t.Errorf("got %q", s)
+	}
+}
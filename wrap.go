@@ -0,0 +1,78 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// WrapWidth sets the width at which long Desc and Note cells are wrapped
+// onto additional lines; the other columns stay aligned.  The cells are
+// wrapped at spaces; words longer than the width are split.  A width less
+// than the column's header is widened to the header.  The default, 0, is
+// no wrapping.
+func (b *StringBench) WrapWidth(n int) {
+	b.wrapWidth = n
+}
+
+// setWrapLength limits the Desc and Note columns' lengths to the wrap
+// width.  Like the column lengths, the header lengths are in runes.
+func (b *StringBench) setWrapLength() {
+	if b.wrapWidth <= 0 {
+		return
+	}
+	b.length.Desc = wrapLength(b.length.Desc, b.wrapWidth, utf8.RuneCountInString(b.header.Desc))
+	b.length.Note = wrapLength(b.length.Note, b.wrapWidth, utf8.RuneCountInString(b.header.Note))
+}
+
+// wrapLength returns the length of a column that's wrapped at width w; a
+// column that isn't output stays that way.
+func wrapLength(l, w, hdr int) int {
+	if l == 0 || l <= w {
+		return l
+	}
+	if hdr > w {
+		return hdr
+	}
+	return w
+}
+
+// wrapCell returns s wrapped into lines of at most w runes.  If w < 1, s is
+// returned as the only line.
+func wrapCell(s string, w int) []string {
+	if w < 1 || utf8.RuneCountInString(s) <= w {
+		return []string{s}
+	}
+	var lines []string
+	var line string
+	var n int // the line's length, in runes.
+	for _, word := range strings.Fields(s) {
+		r := []rune(word)
+		for len(r) > w {
+			if line != "" {
+				lines = append(lines, line)
+				line, n = "", 0
+			}
+			lines = append(lines, string(r[:w]))
+			r = r[w:]
+		}
+		word = string(r)
+		switch {
+		case line == "":
+			line, n = word, len(r)
+		case n+1+len(r) <= w:
+			line += " " + word
+			n += 1 + len(r)
+		default:
+			lines = append(lines, line)
+			line, n = word, len(r)
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWrapCell(t *testing.T) {
+	tests := []struct {
+		s    string
+		w    int
+		want []string
+	}{
+		{"", 10, []string{""}},
+		{"short", 10, []string{"short"}},
+		{"short", 0, []string{"short"}},
+		{"the quick brown fox jumps", 10, []string{"the quick", "brown fox", "jumps"}},
+		{"a verylongwordthatsplits here", 8, []string{"a", "verylong", "wordthat", "splits", "here"}},
+		{"décodé", 6, []string{"décodé"}},
+		{"déjà vu über café", 8, []string{"déjà vu", "über", "café"}},
+		{"日本語のテキスト", 3, []string{"日本語", "のテキ", "スト"}},
+	}
+	for _, test := range tests {
+		got := wrapCell(test.s, test.w)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("wrapCell(%q, %d): got %q; want %q", test.s, test.w, got, test.want)
+		}
+	}
+}
+
+func TestStringBenchWrap(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewStringBench(&buf)
+	b.Hostname = "host"
+	b.WrapWidth(10)
+	b.Append(
+		Bench{Name: "a", Desc: "decodes small json values", Iterations: 1, Result: Result{Ops: 10, NsOp: 100}, Note: "the quick brown fox"},
+		Bench{Name: "b", Desc: "short", Iterations: 1, Result: Result{Ops: 20, NsOp: 200}},
+	)
+	err := b.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `Host:        host
+Timestamp:   ` + b.Timestamp.Format("2006-01-02T15:04:05Z07:00") + `
+
+Name  Desc        Ops  ns/Op  B/Op  Allocs/Op  Note
+---------------------------------------------------------
+a     decodes      10    100     0          0  the quick
+      small json                               brown fox
+      values
+b     short        20    200     0          0  
+`
+	if buf.String() != want {
+		t.Errorf("got %q; want %q", buf.String(), want)
+	}
+}
+
+func TestStringBenchWrapRunes(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewStringBench(&buf)
+	b.WrapWidth(8)
+	b.Append(
+		Bench{Name: "a", Desc: "déjà vu über café", Iterations: 1, Result: Result{Ops: 10, NsOp: 100}},
+		Bench{Name: "b", Desc: "Grüße", Iterations: 1, Result: Result{Ops: 20, NsOp: 200}},
+	)
+	err := b.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// the cells are wrapped, and the columns aligned, by runes, not bytes.
+	want := `Name  Desc      Ops  ns/Op  B/Op  Allocs/Op  
+---------------------------------------------
+a     déjà vu    10    100     0          0  
+      über
+      café
+b     Grüße      20    200     0          0  
+`
+	if !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("got %q; want it to end with %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"io"
+)
+
+// countWriter counts the bytes written to w.
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error // the first write error.
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	if err != nil && c.err == nil {
+		c.err = err
+	}
+	return n, err
+}
+
+// result returns the bytes written and the first error, either the
+// output's or a write error.
+func (c *countWriter) result(err error) (int64, error) {
+	if err == nil {
+		err = c.err
+	}
+	return c.n, err
+}
+
+// WriteTo implements io.WriterTo; it writes the benches to w as text, with
+// b's output settings.
+func (b *Benches) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	s := &StringBench{w: cw, Benches: *b}
+	return cw.result(s.Out())
+}
+
+// String returns the benches as a text table, with b's output settings.
+func (b *Benches) String() string {
+	var buf bytes.Buffer
+	b.WriteTo(&buf)
+	return buf.String()
+}
+
+// WriteTo implements io.WriterTo; it writes the output to w instead of the
+// StringBench's writer, with its wrap width and alignments.
+func (b *StringBench) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	s := &StringBench{w: cw, Benches: b.Benches, wrapWidth: b.wrapWidth, align: b.align}
+	return cw.result(s.Out())
+}
+
+// String returns the output as a text table, with the StringBench's
+// settings.
+func (b *StringBench) String() string {
+	var buf bytes.Buffer
+	b.WriteTo(&buf)
+	return buf.String()
+}
+
+// WriteTo implements io.WriterTo; it writes the output to w instead of the
+// CSVBench's writer.
+func (b *CSVBench) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	c := NewCSVBench(cw)
+	c.Benches = b.Benches
+	return cw.result(c.Out())
+}
+
+// WriteTo implements io.WriterTo; it writes the output to w instead of the
+// MDBench's writer.
+func (b *MDBench) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	m := &MDBench{w: cw, Benches: b.Benches, SectionHeaderHash: b.SectionHeaderHash}
+	return cw.result(m.Out())
+}
+
+// WriteTo implements io.WriterTo; it writes the output to w instead of the
+// HTMLBench's writer.
+func (b *HTMLBench) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	h := &HTMLBench{w: cw, Benches: b.Benches}
+	return cw.result(h.Out())
+}
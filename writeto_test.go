@@ -0,0 +1,123 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write error")
+}
+
+func TestWriteTo(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		new  func(w io.Writer) Benchmarker
+	}{
+		{"txt", func(w io.Writer) Benchmarker { return NewStringBench(w) }},
+		{"csv", func(w io.Writer) Benchmarker { return NewCSVBench(w) }},
+		{"md", func(w io.Writer) Benchmarker { return NewMDBench(w) }},
+		{"html", func(w io.Writer) Benchmarker { return NewHTMLBench(w) }},
+	} {
+		var want bytes.Buffer
+		b := test.new(&want)
+		SetBenches(b, &Benches{Hostname: "host", Timestamp: time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC), Benchmarks: testBenches()})
+		err := b.Out()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		var got bytes.Buffer
+		n, err := b.(io.WriterTo).WriteTo(&got)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("%s: got %q; want %q", test.name, got.String(), want.String())
+		}
+		if n != int64(got.Len()) {
+			t.Errorf("%s: got %d bytes; want %d", test.name, n, got.Len())
+		}
+		_, err = b.(io.WriterTo).WriteTo(errWriter{})
+		if err == nil {
+			t.Errorf("%s: got no error; want the write error", test.name)
+		}
+	}
+}
+
+func TestBenchesWriteTo(t *testing.T) {
+	var want bytes.Buffer
+	s := NewStringBench(&want)
+	s.Append(testBenches()...)
+	s.Out()
+	b := NewBenches()
+	b.Hostname, b.Timestamp = s.Hostname, s.Timestamp
+	b.Append(testBenches()...)
+	var got bytes.Buffer
+	n, err := b.WriteTo(io.MultiWriter(&got, ioutil.Discard))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got.String() != want.String() || n != int64(want.Len()) {
+		t.Errorf("got %d bytes %q; want %q", n, got.String(), want.String())
+	}
+}
+
+func TestString(t *testing.T) {
+	b := NewBenches()
+	b.Hostname = "host"
+	b.Append(testBenches()...)
+	var want bytes.Buffer
+	b.WriteTo(&want)
+	if got := b.String(); got != want.String() {
+		t.Errorf("got %q; want %q", got, want.String())
+	}
+	tests := []struct {
+		b    Bench
+		want string
+	}{
+		{Bench{Group: "json", SubGroup: "decode", Name: "small", Iterations: 2, Result: Result{Ops: 500, NsOp: 200, BytesOp: 32, AllocsOp: 2}}, "json/decode/small: 1000 ops  100 ns/op  16 bytes/op  1 allocs/op"},
+		{Bench{Name: "empty"}, "empty: 0 ops  0 ns/op  0 bytes/op  0 allocs/op"},
+		{Bench{Name: "broken", Err: "boom"}, "broken: FAILED: boom"},
+	}
+	for _, test := range tests {
+		if got := test.b.String(); got != test.want {
+			t.Errorf("got %q; want %q", got, test.want)
+		}
+	}
+}
+
+// WriteTo uses the StringBench's settings, not just its Benches'.
+func TestStringBenchWriteTo(t *testing.T) {
+	var want bytes.Buffer
+	s := NewStringBench(&want)
+	SetBenches(s, &Benches{Hostname: "host", Timestamp: time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC), Benchmarks: testBenches()})
+	s.Benchmarks[0].Desc = "a description long enough to wrap"
+	s.WrapWidth(12)
+	s.SetAlignment(OpsColumn, AlignLeft)
+	s.SetAlignment(NameColumn, AlignRight)
+	err := s.Out()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var got bytes.Buffer
+	_, err = s.WriteTo(&got)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("got %q; want %q", got.String(), want.String())
+	}
+	if s.String() != want.String() {
+		t.Errorf("got %q; want %q", s.String(), want.String())
+	}
+}
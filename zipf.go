@@ -0,0 +1,125 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import "math"
+
+// Zipf generates Zipf distributed item indexes: index k, of n items, is
+// chosen with a probability proportional to 1/(k+1)^s.  Index 0 is the most
+// frequently chosen.  Skewed keys like these are more representative of real
+// workloads, e.g. cache lookups, than uniformly random keys.
+//
+// It uses the rejection-inversion method of Hörmann and Derflinger, which has
+// constant setup and sampling costs regardless of n and supports any s > 0,
+// including the s < 1 values commonly used in benchmarks, e.g. YCSB's 0.99.
+type Zipf struct {
+	g           *Gen
+	n           float64
+	s           float64
+	hIntegralX1 float64
+	hIntegralN  float64
+	t           float64
+}
+
+// NewZipf returns a Zipf, using the package's Gen, that generates indexes
+// in [0, n) with exponent s.  It panics if s <= 0 or n == 0.
+func NewZipf(s float64, n uint64) *Zipf {
+	return defaultGen.NewZipf(s, n)
+}
+
+// NewZipf returns a Zipf, using g, that generates indexes in [0, n) with
+// exponent s.  It panics if s <= 0 or n == 0.
+func (g *Gen) NewZipf(s float64, n uint64) *Zipf {
+	if !(s > 0) || n == 0 {
+		panic("invalid argument to NewZipf")
+	}
+	z := &Zipf{g: g, n: float64(n), s: s}
+	z.hIntegralX1 = z.hIntegral(1.5) - 1
+	z.hIntegralN = z.hIntegral(z.n + 0.5)
+	z.t = 2 - z.hIntegralInverse(z.hIntegral(2.5)-z.h(2))
+	return z
+}
+
+// Next returns the next item index, in [0, n).
+func (z *Zipf) Next() uint64 {
+	for {
+		u := z.hIntegralN + z.g.RandFloat64()*(z.hIntegralX1-z.hIntegralN)
+		x := z.hIntegralInverse(u)
+		k := math.Floor(x + 0.5)
+		if k < 1 {
+			k = 1
+		} else if k > z.n {
+			k = z.n
+		}
+		if k-x <= z.t || u >= z.hIntegral(k+0.5)-z.h(k) {
+			return uint64(k) - 1
+		}
+	}
+}
+
+// h is the unnormalized probability density, x^-s.
+func (z *Zipf) h(x float64) float64 {
+	return math.Exp(-z.s * math.Log(x))
+}
+
+// hIntegral is the integral of h; it's defined for s == 1 too.
+func (z *Zipf) hIntegral(x float64) float64 {
+	l := math.Log(x)
+	return helper2((1-z.s)*l) * l
+}
+
+// hIntegralInverse is the inverse of hIntegral.
+func (z *Zipf) hIntegralInverse(x float64) float64 {
+	t := x * (1 - z.s)
+	if t < -1 {
+		// limit the value to the function's domain; this can only happen
+		// because of rounding.
+		t = -1
+	}
+	return math.Exp(helper1(t) * x)
+}
+
+// helper1 returns log1p(x)/x, using its Taylor series close to 0.
+func helper1(x float64) float64 {
+	if math.Abs(x) > 1e-8 {
+		return math.Log1p(x) / x
+	}
+	return 1 - x*(0.5-x*(1.0/3-0.25*x))
+}
+
+// helper2 returns expm1(x)/x, using its Taylor series close to 0.
+func helper2(x float64) float64 {
+	if math.Abs(x) > 1e-8 {
+		return math.Expm1(x) / x
+	}
+	return 1 + x*0.5*(1+x*(1.0/3)*(1+0.25*x))
+}
+
+// RandPowerLaw returns a pseudo-random value in [min, max] from a bounded
+// power-law (Pareto) distribution with shape alpha: small values are common
+// and large values are rare, like real world object and message sizes.  The
+// larger alpha is, the more skewed towards min the values are.  It panics if
+// alpha <= 0, min <= 0, or max < min.
+func RandPowerLaw(alpha, min, max float64) float64 {
+	return defaultGen.RandPowerLaw(alpha, min, max)
+}
+
+// RandPowerLaw returns a pseudo-random value in [min, max] from a bounded
+// power-law distribution with shape alpha.  See the RandPowerLaw function.
+func (g *Gen) RandPowerLaw(alpha, min, max float64) float64 {
+	if !(alpha > 0) || !(min > 0) || max < min {
+		panic("invalid argument to RandPowerLaw")
+	}
+	if max == min {
+		return min
+	}
+	// inverse of the bounded Pareto CDF.
+	u := g.RandFloat64()
+	la := math.Pow(min, alpha)
+	ha := math.Pow(max, alpha)
+	v := math.Pow(-(u*ha-u*la-ha)/(ha*la), -1/alpha)
+	// rounding can put the value just outside the bounds.
+	return math.Max(min, math.Min(max, v))
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2016 Joel Scoble: https://github.com/mohae.  All rights
+// reserved.  Licensed under the MIT License. See the LICENSE file in the
+// project root for license information.
+
+package benchutil
+
+import (
+	"math"
+	"testing"
+)
+
+func TestZipf(t *testing.T) {
+	for _, s := range []float64{0.99, 1, 1.5} {
+		const n, samples = 100, 100000
+		z := NewGen(1).NewZipf(s, n)
+		counts := make([]int, n)
+		for i := 0; i < samples; i++ {
+			k := z.Next()
+			if k >= n {
+				t.Fatalf("s %v: got %d; want [0, %d)", s, k, n)
+			}
+			counts[k]++
+		}
+		// compare the frequency of the first item to its expected
+		// probability: 1 / H(n, s).
+		var hn float64
+		for k := 1; k <= n; k++ {
+			hn += 1 / math.Pow(float64(k), s)
+		}
+		want := samples / hn
+		if math.Abs(float64(counts[0])-want)/want > 0.05 {
+			t.Errorf("s %v: got %d samples of item 0; want about %.0f", s, counts[0], want)
+		}
+		if counts[0] <= counts[1] || counts[1] <= counts[n-1] {
+			t.Errorf("s %v: got counts %d, %d, ..., %d; want descending frequencies", s, counts[0], counts[1], counts[n-1])
+		}
+	}
+}
+
+func TestRandPowerLaw(t *testing.T) {
+	g := NewGen(1)
+	var small int
+	for i := 0; i < 10000; i++ {
+		v := g.RandPowerLaw(1.2, 64, 1<<20)
+		if v < 64 || v > 1<<20 {
+			t.Fatalf("got %v; want [64, %d]", v, 1<<20)
+		}
+		if v < 1024 {
+			small++
+		}
+	}
+	// P(X < 1024) is ~96% for alpha 1.2.
+	if small < 9000 {
+		t.Errorf("got %d of 10000 values < 1024; want most of them", small)
+	}
+}